@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/bingemate/media-service/initializers"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Config runs the config sub-command: "print" writes the effective configuration with the secrets
+// redacted, as a YAML (default), TOML or env file, and "validate" only checks it. Both check it for the server,
+// whose external services need their credentials.
+func Config(env initializers.Env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing config action, expected print or validate")
+	}
+	switch args[0] {
+	case "print":
+		flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+		format := flags.String("format", "yaml", "output format: yaml, toml or env")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := printConfig(env, *format); err != nil {
+			return err
+		}
+		if err := env.Validate(serverServices...); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return nil
+	case "validate":
+		if err := env.Validate(serverServices...); err != nil {
+			return err
+		}
+		fmt.Println("Configuration is valid")
+		return nil
+	default:
+		return fmt.Errorf("unknown config action %q, expected print or validate", args[0])
+	}
+}
+
+func printConfig(env initializers.Env, format string) error {
+	redacted := env.Redacted()
+	switch format {
+	case "yaml", "toml":
+		values := make(map[string]any, len(redacted))
+		for key, value := range redacted {
+			values[strings.ToLower(key)] = value
+		}
+		var (
+			content []byte
+			err     error
+		)
+		if format == "yaml" {
+			content, err = yaml.Marshal(values)
+		} else {
+			content, err = toml.Marshal(values)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(content)
+		return err
+	case "env":
+		keys := make([]string, 0, len(redacted))
+		for key := range redacted {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s=%v\n", key, redacted[key])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown config format %q, expected yaml, toml or env", format)
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/initializers"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/bingemate/media-service/internal/logging"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+type libraryStats struct {
+	Movies           int64 `json:"movies"`
+	TvShows          int64 `json:"tvShows"`
+	Episodes         int64 `json:"episodes"`
+	MediaFiles       int64 `json:"mediaFiles"`
+	TotalSize        int64 `json:"totalSize"`
+	MoviesDuration   int64 `json:"moviesDuration"`
+	EpisodesDuration int64 `json:"episodesDuration"`
+	Comments         int   `json:"comments"`
+	Ratings          int   `json:"ratings"`
+}
+
+type exportedLibrary struct {
+	ExportedAt time.Time        `json:"exportedAt"`
+	Movies     []exportedMovie  `json:"movies"`
+	TvShows    []exportedTvShow `json:"tvShows"`
+}
+
+type exportedMovie struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	ReleaseDate string        `json:"releaseDate"`
+	Categories  []string      `json:"categories"`
+	File        *exportedFile `json:"file,omitempty"`
+}
+
+type exportedTvShow struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	ReleaseDate string            `json:"releaseDate"`
+	Categories  []string          `json:"categories"`
+	Episodes    []exportedEpisode `json:"episodes"`
+}
+
+type exportedEpisode struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Season      int           `json:"season"`
+	Episode     int           `json:"episode"`
+	ReleaseDate string        `json:"releaseDate"`
+	File        *exportedFile `json:"file,omitempty"`
+}
+
+type exportedFile struct {
+	ID        string          `json:"id"`
+	Filename  string          `json:"filename"`
+	Duration  float64         `json:"duration"`
+	Size      int64           `json:"size"`
+	Audios    []exportedTrack `json:"audios"`
+	Subtitles []exportedTrack `json:"subtitles"`
+}
+
+type exportedTrack struct {
+	Filename string `json:"filename"`
+	Language string `json:"language"`
+}
+
+// Stats runs the stats sub-command, printing the library counts as a table or as JSON with -json
+func Stats(env initializers.Env, args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the statistics as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	ctx, end := commandContext("stats")
+	defer end()
+	mediaRepository, err := connectRepository(env)
+	if err != nil {
+		return err
+	}
+	mediaFile := features.NewMediaFile(env.MovieTargetFolder, env.TvTargetFolder, mediaRepository, nil, features.StorageThresholds{}).WithContext(ctx)
+	commentService := features.NewCommentService(mediaRepository).WithContext(ctx)
+	ratingService := features.NewRatingService(mediaRepository).WithContext(ctx)
+
+	var stats libraryStats
+	if stats.Movies, err = mediaFile.CountAvailableMovies(); err != nil {
+		return err
+	}
+	if stats.TvShows, err = mediaFile.CountAvailableTvShows(); err != nil {
+		return err
+	}
+	if stats.Episodes, err = mediaFile.CountAvailableEpisodes(); err != nil {
+		return err
+	}
+	if stats.MediaFiles, err = mediaFile.MediaFilesCount(); err != nil {
+		return err
+	}
+	if stats.TotalSize, err = mediaFile.MediaFilesTotalSize(); err != nil {
+		return err
+	}
+	if stats.MoviesDuration, err = mediaFile.CountMoviesTotalDuration(); err != nil {
+		return err
+	}
+	if stats.EpisodesDuration, err = mediaFile.CountEpisodesTotalDuration(); err != nil {
+		return err
+	}
+	if stats.Comments, err = commentService.CountComments(); err != nil {
+		return err
+	}
+	if stats.Ratings, err = ratingService.CountRatings(); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Movies\t%d\n", stats.Movies)
+	fmt.Fprintf(w, "TV shows\t%d\n", stats.TvShows)
+	fmt.Fprintf(w, "Episodes\t%d\n", stats.Episodes)
+	fmt.Fprintf(w, "Media files\t%d\n", stats.MediaFiles)
+	fmt.Fprintf(w, "Total size\t%d bytes\n", stats.TotalSize)
+	fmt.Fprintf(w, "Movies duration\t%s\n", time.Duration(stats.MoviesDuration)*time.Second)
+	fmt.Fprintf(w, "Episodes duration\t%s\n", time.Duration(stats.EpisodesDuration)*time.Second)
+	fmt.Fprintf(w, "Comments\t%d\n", stats.Comments)
+	fmt.Fprintf(w, "Ratings\t%d\n", stats.Ratings)
+	return w.Flush()
+}
+
+// Export runs the export sub-command, writing the movies and tv shows with their files as JSON
+// to stdout or to the file given with -o. With -available, only the medias having a file are exported.
+func Export(env initializers.Env, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("o", "", "file to write the export to (default stdout)")
+	availableOnly := flags.Bool("available", false, "only export the medias having a file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	ctx, end := commandContext("export")
+	defer end()
+	mediaRepository, err := connectRepository(env)
+	if err != nil {
+		return err
+	}
+	movies, tvShows, err := features.NewMediaData(nil, mediaRepository).WithContext(ctx).GetLibrary(*availableOnly)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(toExportedLibrary(movies, tvShows)); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Library exported", "movies", len(movies), "tv_shows", len(tvShows))
+	return nil
+}
+
+func toExportedLibrary(movies []repository2.Movie, tvShows []repository2.TvShow) exportedLibrary {
+	library := exportedLibrary{
+		ExportedAt: time.Now(),
+		Movies:     make([]exportedMovie, len(movies)),
+		TvShows:    make([]exportedTvShow, len(tvShows)),
+	}
+	for i, movie := range movies {
+		library.Movies[i] = exportedMovie{
+			ID:          movie.ID,
+			Name:        movie.Name,
+			ReleaseDate: movie.ReleaseDate.Format("2006-01-02"),
+			Categories:  toExportedCategories(movie.Categories),
+			File:        toExportedFile(movie.MediaFile),
+		}
+	}
+	for i, tvShow := range tvShows {
+		episodes := make([]exportedEpisode, len(tvShow.Episodes))
+		for j, episode := range tvShow.Episodes {
+			episodes[j] = exportedEpisode{
+				ID:          episode.ID,
+				Name:        episode.Name,
+				Season:      episode.NbSeason,
+				Episode:     episode.NbEpisode,
+				ReleaseDate: episode.ReleaseDate.Format("2006-01-02"),
+				File:        toExportedFile(episode.MediaFile),
+			}
+		}
+		library.TvShows[i] = exportedTvShow{
+			ID:          tvShow.ID,
+			Name:        tvShow.Name,
+			ReleaseDate: tvShow.ReleaseDate.Format("2006-01-02"),
+			Categories:  toExportedCategories(tvShow.Categories),
+			Episodes:    episodes,
+		}
+	}
+	return library
+}
+
+func toExportedCategories(categories []repository2.Category) []string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+	}
+	return names
+}
+
+func toExportedFile(mediaFile *repository2.MediaFile) *exportedFile {
+	if mediaFile == nil {
+		return nil
+	}
+	file := &exportedFile{
+		ID:        mediaFile.ID,
+		Filename:  mediaFile.Filename,
+		Duration:  mediaFile.Duration,
+		Size:      mediaFile.Size,
+		Audios:    make([]exportedTrack, len(mediaFile.Audios)),
+		Subtitles: make([]exportedTrack, len(mediaFile.Subtitles)),
+	}
+	for i, audio := range mediaFile.Audios {
+		file.Audios[i] = exportedTrack{Filename: audio.Filename, Language: audio.Language}
+	}
+	for i, subtitle := range mediaFile.Subtitles {
+		file.Subtitles[i] = exportedTrack{Filename: subtitle.Filename, Language: subtitle.Language}
+	}
+	return file
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/initializers"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
+)
+
+// ReindexSearch runs the reindex-search sub-command, rebuilding the indexes used by the searches
+func ReindexSearch(env initializers.Env, _ []string) error {
+	ctx, end := commandContext("reindex-search")
+	defer end()
+	mediaRepository, err := connectRepository(env)
+	if err != nil {
+		return err
+	}
+	mediaFile := features.NewMediaFile(env.MovieTargetFolder, env.TvTargetFolder, mediaRepository, nil, features.StorageThresholds{}).WithContext(ctx)
+	if err := mediaFile.ReindexSearch(); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Search indexes rebuilt")
+	return nil
+}
+
+// RefreshMetadata runs the refresh-metadata sub-command, fetching again from TMDB the metadata
+// of every stored movie, tv show and episode. It bypasses the Redis cache used by the server.
+func RefreshMetadata(env initializers.Env, _ []string) error {
+	ctx, end := commandContext("refresh-metadata")
+	defer end()
+	mediaRepository, err := connectRepository(env)
+	if err != nil {
+		return err
+	}
+	refresh := features.NewMetadataRefresh(tmdb.NewMediaClient(env.TMDBApiKey), mediaRepository).WithContext(ctx)
+	report, err := refresh.RefreshAll()
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Metadata refreshed",
+		"movies", report.Movies,
+		"tv_shows", report.TvShows,
+		"episodes", report.Episodes,
+		"failed", report.Failed,
+	)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d medias could not be refreshed", report.Failed)
+	}
+	return nil
+}
+
+// ReconcileStorage runs the reconcile-storage sub-command, printing the medias missing from the bucket or missing
+// some of their files, and the bucket directories and objects not matching any media. It fails if there is any
+// difference, unless -repair is given and they could be fixed.
+func ReconcileStorage(env initializers.Env, args []string) error {
+	flags := flag.NewFlagSet("reconcile-storage", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "delete the orphaned objects and the database rows of the missing files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	ctx, end := commandContext("reconcile-storage")
+	defer end()
+	mediaRepository, err := connectRepository(env)
+	if err != nil {
+		return err
+	}
+	bucket, err := storage.NewBucket(env.S3AccessKeyId, env.S3SecretAccessKey, env.S3Endpoint, env.S3Region, env.S3BucketName)
+	if err != nil {
+		return err
+	}
+	report, err := features.NewStorageReconciliation(mediaRepository, bucket).WithContext(ctx).Reconcile(*repair)
+	if report != nil {
+		printReconciliation(report)
+	}
+	if err != nil {
+		return err
+	}
+	if report.Repaired {
+		logging.FromContext(ctx).Info("Storage repaired")
+		return nil
+	}
+	if !report.Consistent() {
+		return errors.New("the database and the bucket are not consistent")
+	}
+	return nil
+}
+
+func printReconciliation(report *features.ReconciliationReport) {
+	fmt.Printf("Checked %d movies and %d episodes\n", report.CheckedMovies, report.CheckedEpisodes)
+	for _, missing := range report.MissingObjects {
+		fmt.Printf("missing\t%s\tmedia file %s\n", missing.Prefix, missing.MediaFileID)
+	}
+	for _, missing := range report.MissingFiles {
+		fmt.Printf("missing\t%s\t%s %s\n", missing.Key, missing.Kind, missing.ID)
+	}
+	for _, prefix := range report.OrphanedPrefixes {
+		fmt.Printf("orphaned\t%s\n", prefix)
+	}
+	for _, key := range report.OrphanedObjects {
+		fmt.Printf("orphaned\t%s\n", key)
+	}
+}
+
+func connectRepository(env initializers.Env) (*repository.MediaRepository, error) {
+	db, err := initializers.ConnectToDB(env)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewMediaRepository(db), nil
+}
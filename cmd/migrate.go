@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/bingemate/media-service/initializers"
+	"github.com/bingemate/media-service/internal/migrations"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Migrate runs the migrate sub-command: "up" applies the pending migrations,
+// "down [n]" reverts the last n applied migrations (1 by default) and "status" lists them
+func Migrate(env initializers.Env, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	env.DBSync = false
+	db, err := initializers.ConnectToDB(env)
+	if err != nil {
+		return err
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		slog.Info("Migrations applied", "count", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to revert %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("Migrations reverted", "count", reverted)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	default:
+		return fmt.Errorf("unknown migrate action %q, expected up, down or status", action)
+	}
+	return nil
+}
+
+func printMigrationStatus(statuses []migrations.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/bingemate/media-service/initializers"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/tracing"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+)
+
+// command is a sub-command of the binary, run with the environment shared by all the commands.
+// services are the external services it uses, whose credentials must be configured.
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(env initializers.Env, args []string) error
+	services    []initializers.Service
+}
+
+var commands []command
+
+// serverServices are the external services used by the server, the configuration being validated for them
+var serverServices = []initializers.Service{initializers.ServiceTMDB, initializers.ServiceBucket, initializers.ServicePlayback}
+
+func init() {
+	commands = []command{
+		{"serve", "serve", "Start the HTTP API (default command)", func(env initializers.Env, _ []string) error { return Serve(env) }, serverServices},
+		{"migrate", "migrate [up | down [n] | status]", "Apply, revert or list the database migrations", Migrate, nil},
+		{"reindex-search", "reindex-search", "Rebuild the database search indexes", ReindexSearch, nil},
+		{"refresh-metadata", "refresh-metadata", "Fetch again from TMDB the metadata of the stored medias", RefreshMetadata, []initializers.Service{initializers.ServiceTMDB}},
+		{"reconcile-storage", "reconcile-storage [-repair]", "Report, or repair, the differences between the database and the bucket", ReconcileStorage, []initializers.Service{initializers.ServiceBucket}},
+		{"stats", "stats [-json]", "Print the library statistics", Stats, nil},
+		{"export", "export [-o file] [-available]", "Export the library as JSON", Export, nil},
+		{"config", "config [print [-format yaml|toml|env] | validate]", "Print the configuration with the secrets redacted, or validate it", Config, nil},
+		{"help", "help", "Print this help", nil, nil},
+	}
+}
+
+// Execute runs the command named by the first argument (serve if there is none) after loading and
+// validating the configuration and setting up logging and tracing, and returns the exit status of the process.
+// Commands other than serve log to stderr, leaving stdout for their output.
+func Execute(configFile string, args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		Usage()
+		return 2
+	}
+	if cmd.name == "help" {
+		Usage()
+		return 0
+	}
+
+	env, err := initializers.LoadEnv(configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// config prints or validates the configuration itself, it must run even if the configuration is invalid
+	if cmd.name == "config" {
+		if err := cmd.run(env, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	if err := env.Validate(cmd.services...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var console io.Writer = os.Stderr
+	if cmd.name == "serve" {
+		console = os.Stdout
+	}
+	logFile := initializers.InitLog(env, console)
+	defer logFile.Close()
+	shutdownTracing, err := initializers.InitTracing(env)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer shutdownTracing(context.Background())
+	if err := cmd.run(env, args); err != nil {
+		slog.Error("Command failed", "command", cmd.name, "error", err)
+		return 1
+	}
+	return 0
+}
+
+// Usage prints the available commands
+func Usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [flags] <command> [arguments]\n\nCommands:\n", os.Args[0])
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.usage, cmd.description)
+	}
+	w.Flush()
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// commandContext returns the context of a maintenance command, carrying a span covering the whole run
+// and a logger tagged with the command name, and the function ending the span
+func commandContext(name string) (context.Context, func()) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "cmd."+name)
+	ctx = logging.With(ctx, "command", name)
+	return ctx, func() { span.End() }
+}
@@ -1,29 +1,58 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/bingemate/media-service/docs"
 	"github.com/bingemate/media-service/initializers"
 	"github.com/bingemate/media-service/internal/controllers"
 	"github.com/gin-gonic/gin"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
-func Serve(env initializers.Env) {
-	var engine = gin.Default()
+// shutdownTimeout is how long the requests in progress are given to complete once the server is stopped
+const shutdownTimeout = 10 * time.Second
+
+// Serve runs the HTTP API until the process is interrupted or terminated
+func Serve(env initializers.Env) error {
+	var engine = gin.New()
+	engine.Use(gin.Recovery())
 	addCors(engine)
 	db, err := initializers.ConnectToDB(env)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := controllers.InitRouter(ctx, engine, db, env); err != nil {
+		return err
 	}
-	controllers.InitRouter(engine, db, env)
 	doc()
-	fmt.Println("Starting server on port", env.Port)
-	err = engine.Run(":" + env.Port)
-	if err != nil {
-		log.Fatal(err)
+	var server = &http.Server{Addr: ":" + env.Port, Handler: engine}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		slog.Info("Stopping server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("error stopping server", "error", err)
+		}
+	}()
+	slog.Info("Starting server", "port", env.Port)
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
-	fmt.Print(engine)
+	// the requests in progress are still being completed once ListenAndServe returned
+	<-stopped
+	return nil
 }
 
 func addCors(engine *gin.Engine) gin.IRoutes {
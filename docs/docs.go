@@ -16,22 +16,22 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/assets/actor/{id}": {
+        "/admin/duplicates": {
             "get": {
-                "description": "Get actor by id",
+                "description": "Group the duplicated media files by the movie or episode they are copies of: the file of the media with\nthe files no media uses having the same filename, size and duration. The copies matching the file of no\nmedia, or the files of several media, are grouped without media. The files pending deletion are skipped.\nReserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets"
+                    "Admin"
                 ],
-                "summary": "Get actor",
+                "summary": "Detect the duplicated files",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Actor ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -39,11 +39,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.actor"
+                            "$ref": "#/definitions/controllers.duplicateReportResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
@@ -57,23 +57,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/assets/movie-genre/{id}": {
+        "/admin/duplicates/last": {
             "get": {
-                "description": "Get movie genre by id",
+                "description": "Get the report of the last detection of the duplicated files, scheduled or not. Reserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets",
-                    "Movie"
+                    "Admin"
                 ],
-                "summary": "Get movie genre",
+                "summary": "Get the last duplicate detection",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Genre ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -81,17 +80,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.genre"
+                            "$ref": "#/definitions/controllers.duplicateReportResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
@@ -99,61 +98,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/assets/movie-genres": {
-            "get": {
-                "description": "Get all movie genres",
-                "produces": [
+        "/admin/duplicates/resolve": {
+            "post": {
+                "description": "Keep a copy of a duplicated file and delete the others through deletion jobs. The movie or episode\nusing another copy is made to use the kept one, nothing being deleted if it cannot be. The copies whose\ndeletion could not start are listed among the errors, the other deletions going on. Reserved to the admins.",
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Media Assets",
-                    "Movie"
-                ],
-                "summary": "Get movie genres",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.genre"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/assets/network/{id}": {
-            "get": {
-                "description": "Get network by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets",
-                    "TvShow"
+                    "Admin"
                 ],
-                "summary": "Get network",
+                "summary": "Resolve a duplicated file",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Network ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "File to keep",
+                        "name": "keep",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.duplicateResolveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.studio"
+                            "$ref": "#/definitions/controllers.duplicateResolutionResponse"
                         }
                     },
                     "400": {
@@ -162,6 +142,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -171,23 +163,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/assets/studio/{id}": {
+        "/admin/retention": {
             "get": {
-                "description": "Get studio by id",
+                "description": "Evaluate the retention policy now, listing the files it selects for deletion with the rules selecting\nthem, without deleting anything. Reserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets",
-                    "Movie"
+                    "Admin"
                 ],
-                "summary": "Get studio",
+                "summary": "Get the retention report",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Studio ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -195,11 +186,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.studio"
+                            "$ref": "#/definitions/controllers.retentionReportResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
@@ -213,23 +204,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/assets/tv-genre/{id}": {
+        "/admin/retention/last": {
             "get": {
-                "description": "Get tv genre by id",
+                "description": "Get the report of the last application of the retention policy, scheduled or not. Reserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets",
-                    "TvShow"
+                    "Admin"
                 ],
-                "summary": "Get tv genre",
+                "summary": "Get the last retention run",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Genre ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -237,17 +227,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.genre"
+                            "$ref": "#/definitions/controllers.retentionReportResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
@@ -255,25 +245,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/assets/tv-genres": {
-            "get": {
-                "description": "Get all tv genres",
+        "/admin/retention/run": {
+            "post": {
+                "description": "Apply the retention policy now: the selected files are deleted through deletion jobs when the policy\ndeletes them, otherwise they are only reported. Reserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Assets",
-                    "TvShow"
+                    "Admin"
+                ],
+                "summary": "Apply the retention policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get tv genres",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.genre"
-                            }
+                            "$ref": "#/definitions/controllers.retentionReportResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -285,47 +286,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/calendar/movies": {
-            "get": {
-                "description": "Get movies calendar",
+        "/admin/storage/reconcile": {
+            "post": {
+                "description": "Compare the media files, audios and subtitles of the database with the objects of the bucket, reporting\nthe medias missing their directory or some of their files, and the directories and objects of no media.\nWith repair, the orphaned objects are deleted from the bucket and the rows of the missing files from\nthe database. The files pending deletion are skipped. Reserved to the admins.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Calendar",
-                    "Movie"
+                    "Admin"
                 ],
-                "summary": "Get movies calendar",
+                "summary": "Reconcile the storage",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Month",
-                        "name": "month",
-                        "in": "query",
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Year",
-                        "name": "year",
+                        "type": "boolean",
+                        "description": "Fix the differences",
+                        "name": "repair",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "header",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.movieResults"
-                            }
+                            "$ref": "#/definitions/controllers.reconciliationResponse"
                         }
                     },
                     "400": {
@@ -334,6 +324,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -343,31 +339,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/calendar/movies/ical/{user-id}": {
+        "/assets/actor/{id}": {
             "get": {
-                "description": "Get movies calendar in iCal format",
+                "description": "Get actor by id",
                 "produces": [
-                    "text/calendar"
+                    "application/json"
                 ],
                 "tags": [
-                    "Calendar",
-                    "Movie"
+                    "Media Assets"
                 ],
-                "summary": "Get movies calendar in iCal format",
+                "summary": "Get actor",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "iCal data",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/controllers.actor"
                         }
                     },
                     "400": {
@@ -385,44 +380,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/calendar/tvshows": {
+        "/assets/movie-genre/{id}": {
             "get": {
-                "description": "Get tv shows calendar",
+                "description": "Get movie genre by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Calendar",
-                    "TvShow"
+                    "Media Assets",
+                    "Movie"
                 ],
-                "summary": "Get tv shows calendar",
+                "summary": "Get movie genre",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "Month",
-                        "name": "month",
-                        "in": "query",
+                        "description": "Genre ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Year",
-                        "name": "year",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvReleasesResults"
+                            "$ref": "#/definitions/controllers.genre"
                         }
                     },
                     "400": {
@@ -440,37 +422,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/calendar/tvshows/ical/{user-id}": {
+        "/assets/movie-genres": {
             "get": {
-                "description": "Get tv shows calendar in iCal format",
+                "description": "Get all movie genres",
                 "produces": [
-                    "text/calendar"
+                    "application/json"
                 ],
                 "tags": [
-                    "Calendar",
-                    "TvShow"
-                ],
-                "summary": "Get tv shows calendar in iCal format",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Media Assets",
+                    "Movie"
                 ],
+                "summary": "Get movie genres",
                 "responses": {
                     "200": {
-                        "description": "iCal data",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.genre"
+                            }
                         }
                     },
                     "500": {
@@ -482,21 +452,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/count": {
+        "/assets/network/{id}": {
             "get": {
-                "description": "Get comments count",
+                "description": "Get network by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "Media Assets",
+                    "TvShow"
+                ],
+                "summary": "Get network",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Network ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get comments count",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/controllers.studio"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -508,38 +494,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/history": {
+        "/assets/studio/{id}": {
             "get": {
-                "description": "Get comments history",
+                "description": "Get studio by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "Media Assets",
+                    "Movie"
                 ],
-                "summary": "Get comments history",
+                "summary": "Get studio",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "end",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Studio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.commentHistoryReponse"
-                            }
+                            "$ref": "#/definitions/controllers.studio"
                         }
                     },
                     "400": {
@@ -557,27 +536,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/movie/user/{userID}": {
+        "/assets/tv-genre/{id}": {
             "get": {
-                "description": "Get user's movie comments",
+                "description": "Get tv genre by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "Media Assets",
+                    "TvShow"
                 ],
-                "summary": "Get user's movie comments",
+                "summary": "Get tv genre",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userID",
+                        "description": "Genre ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -586,7 +560,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.commentResults"
+                            "$ref": "#/definitions/controllers.genre"
                         }
                     },
                     "400": {
@@ -604,58 +578,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/movie/{commentID}": {
-            "put": {
-                "description": "Update movie comment",
+        "/assets/tv-genres": {
+            "get": {
+                "description": "Get all tv genres",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
-                ],
-                "summary": "Update movie comment",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Movie Comment ID",
-                        "name": "commentID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Comment",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.commentRequest"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User roles",
-                        "name": "roles",
-                        "in": "header"
-                    }
+                    "Media Assets",
+                    "TvShow"
                 ],
+                "summary": "Get tv genres",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.commentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.genre"
+                            }
                         }
                     },
                     "500": {
@@ -665,43 +606,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete movie comment",
+            }
+        },
+        "/calendar/movies": {
+            "get": {
+                "description": "Get movies calendar",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "Calendar",
+                    "Movie"
                 ],
-                "summary": "Delete movie comment",
+                "summary": "Get movies calendar",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie Comment ID",
-                        "name": "commentID",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "Month",
+                        "name": "month",
+                        "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "User ID",
                         "name": "user-id",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User roles",
-                        "name": "roles",
-                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "comment deleted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.movieResults"
+                            }
                         }
                     },
                     "400": {
@@ -719,36 +666,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/movie/{mediaID}": {
+        "/calendar/movies/ical/{user-id}": {
             "get": {
-                "description": "Get movie's comments",
+                "description": "Get movies calendar in iCal format",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "Comment"
+                    "Calendar",
+                    "Movie"
                 ],
-                "summary": "Get movie's comments",
+                "summary": "Get movies calendar in iCal format",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "mediaID",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "iCal data",
                         "schema": {
-                            "$ref": "#/definitions/controllers.commentResults"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -764,46 +706,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Add comment to a movie",
+            }
+        },
+        "/calendar/tvshows": {
+            "get": {
+                "description": "Get tv shows calendar",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "Calendar",
+                    "TvShow"
                 ],
-                "summary": "Add movie comment",
+                "summary": "Get tv shows calendar",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "mediaID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Comment",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.commentRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "User ID",
                         "name": "user-id",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.commentResponse"
+                            "$ref": "#/definitions/controllers.tvReleasesResults"
                         }
                     },
                     "400": {
@@ -821,23 +763,170 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/tv/user/{userID}": {
+        "/calendar/tvshows/ical/{user-id}": {
             "get": {
-                "description": "Get user's tv show comments",
+                "description": "Get tv shows calendar in iCal format",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar",
+                    "TvShow"
+                ],
+                "summary": "Get tv shows calendar in iCal format",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCal data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comment/count": {
+            "get": {
+                "description": "Get comments count",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Get user's tv show comments",
+                "summary": "Get comments count",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comment/history": {
+            "get": {
+                "description": "Get the number of comments per day, from start to end, the oldest day first.\nThe days without comments are omitted. With a cursor, page or limit, a commentHistoryResults page\nof the days is returned instead, the most recent day first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "Get comments history",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), the first day of the current month by default",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), the last day of the current month by default",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 31 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.commentHistoryReponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comment/movie/user/{userID}": {
+            "get": {
+                "description": "Get user's movie comments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "Get user's movie comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 5 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "User ID",
@@ -868,20 +957,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/tv/{commentID}": {
+        "/comment/movie/{commentID}": {
             "put": {
-                "description": "Update tv show comment",
+                "description": "Update movie comment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Update tv show comment",
+                "summary": "Update movie comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "TV Show Comment ID",
+                        "description": "Movie Comment ID",
                         "name": "commentID",
                         "in": "path",
                         "required": true
@@ -931,18 +1020,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete tv show comment",
+                "description": "Delete movie comment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Delete tv show comment",
+                "summary": "Delete movie comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "TV Show Comment ID",
+                        "description": "Movie Comment ID",
                         "name": "commentID",
                         "in": "path",
                         "required": true
@@ -983,26 +1072,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/tv/{mediaID}": {
+        "/comment/movie/{mediaID}": {
             "get": {
-                "description": "Get tv show's comments",
+                "description": "Get movie's comments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Get tv show's comments",
+                "summary": "Get movie's comments",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number, ignored with a cursor",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "TV Show ID",
+                        "description": "Page size, 5 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
                         "name": "mediaID",
                         "in": "path",
                         "required": true
@@ -1030,18 +1131,18 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Add comment to a tv show",
+                "description": "Add comment to a movie",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Add tv show comment",
+                "summary": "Add movie comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TV Show ID",
+                        "description": "Movie ID",
                         "name": "mediaID",
                         "in": "path",
                         "required": true
@@ -1085,17 +1186,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/user/count/{userID}": {
+        "/comment/tv/user/{userID}": {
             "get": {
-                "description": "Get User's comments count",
+                "description": "Get user's tv show comments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Get User's comments count",
+                "summary": "Get user's tv show comments",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 5 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "User ID",
@@ -1108,7 +1227,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/controllers.commentResults"
                         }
                     },
                     "400": {
@@ -1126,45 +1245,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/user/history/{userID}": {
-            "get": {
-                "description": "Get User's comments history",
+        "/comment/tv/{commentID}": {
+            "put": {
+                "description": "Update tv show comment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Comment"
                 ],
-                "summary": "Get User's comments history",
+                "summary": "Update tv show comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userID",
+                        "description": "TV Show Comment ID",
+                        "name": "commentID",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.commentRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "start",
-                        "in": "query"
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "end",
-                        "in": "query"
+                        "description": "User roles",
+                        "name": "roles",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.commentHistoryReponse"
-                            }
+                            "$ref": "#/definitions/controllers.commentResponse"
                         }
                     },
                     "400": {
@@ -1180,45 +1306,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/discover/actor/search": {
-            "get": {
-                "description": "Search actors by query",
+            },
+            "delete": {
+                "description": "Delete tv show comment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Actor"
+                    "Comment"
                 ],
-                "summary": "Search Actors",
+                "summary": "Delete tv show comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
+                        "description": "TV Show Comment ID",
+                        "name": "commentID",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Include adult actors",
-                        "name": "adult",
-                        "in": "query"
+                        "type": "string",
+                        "description": "User roles",
+                        "name": "roles",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "comment deleted",
                         "schema": {
-                            "$ref": "#/definitions/controllers.actorResults"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1236,37 +1360,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/movie/actor": {
+        "/comment/tv/{mediaID}": {
             "get": {
-                "description": "Get movies by actor",
+                "description": "Get tv show's comments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Movie"
+                    "Comment"
                 ],
-                "summary": "Get movies by actor",
+                "summary": "Get tv show's comments",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Actor id",
-                        "name": "actor",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number, ignored with a cursor",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 5 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
+                            "$ref": "#/definitions/controllers.commentResults"
                         }
                     },
                     "400": {
@@ -1282,35 +1417,52 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/discover/movie/comments": {
-            "get": {
-                "description": "Get movies ordered by number of comments",
+            },
+            "post": {
+                "description": "Add comment to a tv show",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Movie"
+                    "Comment"
                 ],
-                "summary": "Get movies by comments",
+                "summary": "Add tv show comment",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Only available movies",
-                        "name": "available",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.commentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/controllers.commentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -1322,37 +1474,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/movie/director": {
+        "/comment/user/count/{userID}": {
             "get": {
-                "description": "Get movies by director",
+                "description": "Get User's comments count",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Movie"
+                    "Comment"
                 ],
-                "summary": "Get movies by director",
+                "summary": "Get User's comments count",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Director id",
-                        "name": "director",
-                        "in": "query",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
+                            "type": "integer"
                         }
                     },
                     "400": {
@@ -1370,37 +1515,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/movie/genre": {
+        "/comment/user/history/{userID}": {
             "get": {
-                "description": "Get movies by genre",
+                "description": "Get the number of comments of a user per day, from start to end, the oldest day first.\nThe days without comments are omitted. With a cursor, page or limit, a commentHistoryResults page\nof the days is returned instead, the most recent day first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Movie"
+                    "Comment"
                 ],
-                "summary": "Get movies by genre",
+                "summary": "Get User's comments history",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Genre id",
-                        "name": "genre",
-                        "in": "query",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), the first day of the current month by default",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), the last day of the current month by default",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number, ignored with a cursor",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 31 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.commentHistoryReponse"
+                            }
                         }
                     },
                     "400": {
@@ -1418,18 +1589,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/movie/popular": {
+        "/discover/actor/search": {
             "get": {
-                "description": "Get popular movies",
+                "description": "Search actors by query",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "Movie"
+                    "Actor"
                 ],
-                "summary": "Get popular movies",
+                "summary": "Search Actors",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Page number",
@@ -1438,8 +1616,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "boolean",
-                        "description": "Only available movies",
-                        "name": "available",
+                        "description": "Include adult actors",
+                        "name": "adult",
                         "in": "query"
                     }
                 ],
@@ -1447,46 +1625,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
+                            "$ref": "#/definitions/controllers.actorResults"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/discover/movie/recent": {
-            "get": {
-                "description": "Get recent movies",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Discover",
-                    "Movie"
-                ],
-                "summary": "Get recent movies",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Only available movies",
-                        "name": "available",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.movieResponse"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1497,129 +1643,88 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/movie/recommendations/{movie}": {
+        "/discover/library": {
             "get": {
-                "description": "Get movie's recommendations",
+                "description": "Available movies or tv shows matching all the given filters, the values of a list being alternatives.\nFor tv shows, an episode must match the duration and language filters.\nThe facets count the results for every value of each filter, ignoring the filter itself.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discover",
-                    "Movie"
+                    "Discover"
                 ],
-                "summary": "Get movie's recommendations",
+                "summary": "Filter the library",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie id",
-                        "name": "movie",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.movieResponse"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Media type: movie (default) or tv",
+                        "name": "type",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/discover/movie/search": {
-            "get": {
-                "description": "Search movies by query",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Discover",
-                    "Movie"
-                ],
-                "summary": "Search movies",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
-                        "required": true
+                        "description": "Comma separated genre names",
+                        "name": "genre",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "description": "Minimum release year",
+                        "name": "yearMin",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Only available movies",
-                        "name": "available",
+                        "type": "integer",
+                        "description": "Maximum release year",
+                        "name": "yearMax",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Include adult movies",
-                        "name": "adult",
+                        "type": "integer",
+                        "description": "Minimum duration in minutes",
+                        "name": "durationMin",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/discover/movie/studio": {
-            "get": {
-                "description": "Get movies by studio",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Discover",
-                    "Movie"
-                ],
-                "summary": "Get movies by studio",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Studio id",
-                        "name": "studio",
-                        "in": "query",
-                        "required": true
+                        "description": "Maximum duration in minutes",
+                        "name": "durationMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages",
+                        "name": "subtitle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum average rating",
+                        "name": "ratingMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum average rating",
+                        "name": "ratingMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort: name (default), rating, release or added",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default asc for name and desc otherwise",
+                        "name": "order",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
@@ -1632,7 +1737,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResults"
+                            "$ref": "#/definitions/controllers.libraryResults"
                         }
                     },
                     "400": {
@@ -1650,17 +1755,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/actor": {
+        "/discover/movie/actor": {
             "get": {
-                "description": "Get tv shows by actor",
+                "description": "Get movies by actor",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get tv shows by actor",
+                "summary": "Get movies by actor",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1680,7 +1785,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResults"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
                     "400": {
@@ -1698,21 +1803,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/comments": {
+        "/discover/movie/comments": {
             "get": {
-                "description": "Get tv shows ordered by number of comments",
+                "description": "Get movies ordered by number of comments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get tv shows by comments",
+                "summary": "Get movies by comments",
                 "parameters": [
                     {
                         "type": "boolean",
-                        "description": "Only available tv shows",
+                        "description": "Only available movies",
                         "name": "available",
                         "in": "query"
                     }
@@ -1736,22 +1841,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/genre": {
+        "/discover/movie/director": {
             "get": {
-                "description": "Get tv shows by genre",
+                "description": "Get movies by director",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get tv shows by genre",
+                "summary": "Get movies by director",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Genre id",
-                        "name": "genre",
+                        "description": "Director id",
+                        "name": "director",
                         "in": "query",
                         "required": true
                     },
@@ -1766,7 +1871,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResults"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
                     "400": {
@@ -1784,22 +1889,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/network": {
+        "/discover/movie/genre": {
             "get": {
-                "description": "Get tv shows by network",
+                "description": "Get movies by genre",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get tv shows by network",
+                "summary": "Get movies by genre",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Network id",
-                        "name": "network",
+                        "description": "Genre id",
+                        "name": "genre",
                         "in": "query",
                         "required": true
                     },
@@ -1814,7 +1919,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResults"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
                     "400": {
@@ -1832,17 +1937,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/popular": {
+        "/discover/movie/popular": {
             "get": {
-                "description": "Get popular tv shows",
+                "description": "Get popular movies",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get popular tv shows",
+                "summary": "Get popular movies",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1852,16 +1957,28 @@ const docTemplate = `{
                     },
                     {
                         "type": "boolean",
-                        "description": "Only available tv shows",
+                        "description": "Only available movies",
                         "name": "available",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResults"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
                     "500": {
@@ -1873,23 +1990,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/recent": {
+        "/discover/movie/recent": {
             "get": {
-                "description": "Get recent tv shows",
+                "description": "Get recent movies",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get recent tv shows",
+                "summary": "Get recent movies",
                 "parameters": [
                     {
                         "type": "boolean",
-                        "description": "Only available tv shows",
+                        "description": "Only available movies",
                         "name": "available",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1898,7 +2027,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.tvShowResponse"
+                                "$ref": "#/definitions/controllers.movieResponse"
                             }
                         }
                     },
@@ -1911,22 +2040,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/recommendations/{tv}": {
+        "/discover/movie/recommendations/{movie}": {
             "get": {
-                "description": "Get tv show's recommendations",
+                "description": "Get movie's recommendations",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Get tv show's recommendations",
+                "summary": "Get movie's recommendations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Show id",
-                        "name": "show",
+                        "description": "Movie id",
+                        "name": "movie",
                         "in": "path",
                         "required": true
                     }
@@ -1937,7 +2066,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.tvShowResponse"
+                                "$ref": "#/definitions/controllers.movieResponse"
                             }
                         }
                     },
@@ -1956,17 +2085,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/discover/tv/search": {
+        "/discover/movie/search": {
             "get": {
-                "description": "Search tv shows by query",
+                "description": "Search movies by query",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Discover",
-                    "TvShow"
+                    "Movie"
                 ],
-                "summary": "Search tv shows",
+                "summary": "Search movies",
                 "parameters": [
                     {
                         "type": "string",
@@ -1983,13 +2112,25 @@ const docTemplate = `{
                     },
                     {
                         "type": "boolean",
-                        "description": "Only available tv shows",
+                        "description": "Only available movies, ordered by relevance",
                         "name": "available",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
-                        "description": "Include adult tv shows",
+                        "description": "Include adult movies",
                         "name": "adult",
                         "in": "query"
                     }
@@ -1998,7 +2139,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResults"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
                     "400": {
@@ -2016,47 +2157,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/available": {
+        "/discover/movie/studio": {
             "get": {
-                "description": "Get available space",
+                "description": "Get movies by studio",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "Movie"
+                ],
+                "summary": "Get movies by studio",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Studio id",
+                        "name": "studio",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get available space",
                 "responses": {
                     "200": {
-                        "description": "Available space in bytes",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/controllers.movieResults"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/file/count": {
-            "get": {
-                "description": "Count files",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "File"
-                ],
-                "summary": "Count files",
-                "responses": {
-                    "200": {
-                        "description": "Total number of files",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -2068,21 +2205,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/episode/count": {
+        "/discover/movie/trending": {
             "get": {
-                "description": "Count available episodes",
+                "description": "Get the available movies ranked by their recent comments, ratings and viewers, the most recent activity\nweighing the most. The ranking is refreshed every 15 minutes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "Movie"
+                ],
+                "summary": "Get trending movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Activity window: day, week (default) or month",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count available episodes",
                 "responses": {
                     "200": {
-                        "description": "Total number of available episodes",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/controllers.movieResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -2094,21 +2252,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/episode/duration": {
+        "/discover/recommendations": {
             "get": {
-                "description": "Count episodes total duration",
+                "description": "Recommend available movies and tv shows the user did not rate yet, from the ratings of the user and of\nthe other users: titles rated alike by the same users as the ones the user liked come first. Each one\nlists the liked titles it is the most similar to. The list is empty when the user rated nothing.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover"
+                ],
+                "summary": "Get user's recommendations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated types to recommend: movie, tv (default all)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count episodes total duration",
                 "responses": {
                     "200": {
-                        "description": "Total duration in seconds",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.recommendationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -2120,42 +2308,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/episode/search": {
+        "/discover/search": {
             "get": {
-                "description": "Search tv show episodes files",
+                "description": "Search movies, tv shows and people by query, merged in a single list ordered by relevance.\nWith available set, the movies, tv shows and episodes of the library are ranked together, a page being\na slice of this single list, and people, never part of the library, are not searched.\nOtherwise TMDB is searched and pages are per type: a page holds the same page of each searched type,\ntotalPage being the largest page count and totalResult the sum of the results of the types, and episodes\nare not searched. totals gives the page count and results of each type.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover"
                 ],
-                "summary": "Search tv show episodes files",
+                "summary": "Search movies, tv shows and people",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number, from 1",
                         "name": "page",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Comma separated types to search: movie, tv, episode, person (default all)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only available movies, tv shows and episodes",
+                        "name": "available",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
-                        "required": true
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include adult results",
+                        "name": "adult",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.episodeFilesResult"
+                            "$ref": "#/definitions/controllers.searchResults"
                         }
                     },
                     "400": {
@@ -2164,12 +2376,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2179,30 +2385,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/episode/{id}": {
+        "/discover/suggest": {
             "get": {
-                "description": "Get episode file info by its Episode TMDB ID",
+                "description": "Type-ahead suggestions: available movies, tv shows and episodes of the library having a word starting with q,\nignoring case and accents. With tmdb set, TMDB movies and tv shows complete the list up to the limit.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover"
                 ],
-                "summary": "Get episode file info by its Episode TMDB ID",
+                "summary": "Suggest titles",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Beginning of the title",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of suggestions (default 10, at most 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Complete with TMDB titles",
+                        "name": "tmdb",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.mediaFileResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.suggestionResponse"
+                            }
                         }
                     },
                     "400": {
@@ -2211,12 +2432,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2226,21 +2441,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/movie/count": {
+        "/discover/tv/actor": {
             "get": {
-                "description": "Count available movies",
+                "description": "Get tv shows by actor",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
+                ],
+                "summary": "Get tv shows by actor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Actor id",
+                        "name": "actor",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count available movies",
                 "responses": {
                     "200": {
-                        "description": "Total number of available movies",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/controllers.tvShowResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
                     "500": {
@@ -2252,21 +2489,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/movie/duration": {
+        "/discover/tv/comments": {
             "get": {
-                "description": "Count movies total duration",
+                "description": "Get tv shows ordered by number of comments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
+                ],
+                "summary": "Get tv shows by comments",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only available tv shows",
+                        "name": "available",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count movies total duration",
                 "responses": {
                     "200": {
-                        "description": "Total duration in seconds",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "500": {
@@ -2278,42 +2527,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/movie/search": {
+        "/discover/tv/genre": {
             "get": {
-                "description": "Search movie files",
+                "description": "Get tv shows by genre",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
                 ],
-                "summary": "Search movie files",
+                "summary": "Get tv shows by genre",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
+                        "description": "Page number",
+                        "name": "page",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieFilesResult"
+                            "$ref": "#/definitions/controllers.tvShowResults"
                         }
                     },
                     "400": {
@@ -2322,12 +2566,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2337,30 +2575,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/movie/{id}": {
+        "/discover/tv/network": {
             "get": {
-                "description": "Get movie file info by its Movie TMDB ID",
+                "description": "Get tv shows by network",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
                 ],
-                "summary": "Get movie file info by its Movie TMDB ID",
+                "summary": "Get tv shows by network",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Network id",
+                        "name": "network",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.mediaFileResponse"
+                            "$ref": "#/definitions/controllers.tvShowResults"
                         }
                     },
                     "400": {
@@ -2369,12 +2614,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2384,21 +2623,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/size": {
+        "/discover/tv/popular": {
             "get": {
-                "description": "Get total size taken by all files",
+                "description": "Get popular tv shows",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
+                ],
+                "summary": "Get popular tv shows",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only available tv shows",
+                        "name": "available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get total size",
                 "responses": {
                     "200": {
-                        "description": "Total size in bytes",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "$ref": "#/definitions/controllers.tvShowResults"
                         }
                     },
                     "500": {
@@ -2410,21 +2676,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/tv/count": {
+        "/discover/tv/recent": {
             "get": {
-                "description": "Count available tv shows",
+                "description": "Get recent tv shows",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
+                ],
+                "summary": "Get recent tv shows",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only available tv shows",
+                        "name": "available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Count available tv shows",
                 "responses": {
                     "200": {
-                        "description": "Total number of available tv shows",
+                        "description": "OK",
                         "schema": {
-                            "type": "int"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.tvShowResponse"
+                            }
                         }
                     },
                     "500": {
@@ -2436,21 +2726,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/tv/{id}/available": {
+        "/discover/tv/recommendations/{tv}": {
             "get": {
-                "description": "Get all available episodes id for a tv show",
+                "description": "Get tv show's recommendations",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
                 ],
-                "summary": "Get tv show available episodes",
+                "summary": "Get tv show's recommendations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
+                        "description": "Show id",
+                        "name": "show",
                         "in": "path",
                         "required": true
                     }
@@ -2461,7 +2752,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "integer"
+                                "$ref": "#/definitions/controllers.tvShowResponse"
                             }
                         }
                     },
@@ -2471,12 +2762,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2486,30 +2771,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/file/{id}": {
-            "delete": {
-                "description": "Delete a file",
+        "/discover/tv/search": {
+            "get": {
+                "description": "Search tv shows by query",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Discover",
+                    "TvShow"
                 ],
-                "summary": "Delete a file",
+                "summary": "Search tv shows",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "File ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only available tv shows, ordered by relevance",
+                        "name": "available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required (available only)",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required (available only)",
+                        "name": "subtitle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include adult tv shows",
+                        "name": "adult",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/controllers.tvShowResults"
                         }
                     },
                     "400": {
@@ -2527,31 +2843,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/base/episode/{id}": {
+        "/discover/tv/trending": {
             "get": {
-                "description": "Get episode base info by TMDB ID",
+                "description": "Get the available tv shows ranked by their recent comments, ratings and viewers, the most recent activity\nweighing the most. The ranking is refreshed every 15 minutes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "TvEpisode Data",
-                    "Base"
+                    "Discover",
+                    "TvShow"
                 ],
-                "summary": "Get episode base info",
+                "summary": "Get trending tv shows",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Activity window: day, week (default) or month",
+                        "name": "window",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.mediaResponse"
+                            "$ref": "#/definitions/controllers.tvShowResults"
                         }
                     },
                     "400": {
@@ -2560,12 +2881,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2575,48 +2890,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/base/episodes": {
-            "post": {
-                "description": "Get episodes base info by TMDB ID",
+        "/file/available": {
+            "get": {
+                "description": "Get available space",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "TvEpisode Data",
-                    "Base"
-                ],
-                "summary": "Get episodes base info",
-                "parameters": [
-                    {
-                        "description": "TMDB IDs",
-                        "name": "ids",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.idsRequest"
-                        }
-                    }
+                    "File"
                 ],
+                "summary": "Get available space",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.mediaResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "Available space in bytes",
                         "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "int"
                         }
                     },
                     "500": {
@@ -2628,43 +2916,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/base/movie/{id}": {
+        "/file/count": {
             "get": {
-                "description": "Get movie base info by TMDB ID",
+                "description": "Count files",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie Data",
-                    "Base"
-                ],
-                "summary": "Get movie base info",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "File"
                 ],
+                "summary": "Count files",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.mediaResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "Total number of files",
                         "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "int"
                         }
                     },
                     "500": {
@@ -2676,43 +2942,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/base/tv/{id}": {
+        "/file/episode/count": {
             "get": {
-                "description": "Get tvshow base info by TMDB ID",
+                "description": "Count available episodes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "TvShow Data",
-                    "Base"
-                ],
-                "summary": "Get tvshow base info",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "File"
                 ],
+                "summary": "Count available episodes",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Total number of available episodes",
                         "schema": {
-                            "$ref": "#/definitions/controllers.mediaResponse"
+                            "type": "int"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                }
+            }
+        },
+        "/file/episode/duration": {
+            "get": {
+                "description": "Count episodes total duration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Count episodes total duration",
+                "responses": {
+                    "200": {
+                        "description": "Total duration in seconds",
                         "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "int"
                         }
                     },
                     "500": {
@@ -2724,31 +2994,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/episode-tmdb/{id}": {
+        "/file/episode/search": {
             "get": {
-                "description": "Get TvShow Episode Metadata by TMDB ID",
+                "description": "Search tv show episodes files by episode or tv show name, ordered by relevance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
+                    "File"
                 ],
-                "summary": "Get TvShow Episode Metadata by TMDB ID",
+                "summary": "Search tv show episodes files",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required",
+                        "name": "subtitle",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                            "$ref": "#/definitions/controllers.episodeFilesResult"
                         }
                     },
                     "400": {
@@ -2772,36 +3071,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/episodes-tmdb": {
-            "post": {
-                "description": "Get TvShow Episodes metadata by TMDB IDs",
+        "/file/episode/{id}": {
+            "get": {
+                "description": "Get episode file info by its Episode TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
+                    "File"
                 ],
-                "summary": "Get TvShow Episodes metadata by TMDB IDs",
+                "summary": "Get episode file info by its Episode TMDB ID",
                 "parameters": [
                     {
-                        "description": "TMDB IDs",
-                        "name": "ids",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.idsRequest"
-                        }
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
-                            }
+                            "$ref": "#/definitions/controllers.mediaFileResponse"
                         }
                     },
                     "400": {
@@ -2825,21 +3118,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/movie-tmdb/{id}": {
+        "/file/jobs/{id}": {
             "get": {
-                "description": "Get Movie Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "description": "Get the status of a file deletion: PENDING, RUNNING, DONE once the file is removed,\nor FAILED when its objects could not be deleted from the storage, the file being kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "Movie"
+                    "File"
                 ],
-                "summary": "Get Movie Metadata",
+                "summary": "Get a deletion job",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "TMDB ID",
+                        "type": "string",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2849,7 +3141,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResponse"
+                            "$ref": "#/definitions/controllers.deletionJobResponse"
                         }
                     },
                     "400": {
@@ -2858,6 +3150,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2867,37 +3165,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/movie-tmdb/{id}/short": {
+        "/file/languages": {
             "get": {
-                "description": "Get Movie Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "description": "Audio and subtitle languages present in the library, with the number of movies and episodes having each of them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "Movie"
-                ],
-                "summary": "Get Movie Short Metadata",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "File"
                 ],
+                "summary": "Get languages",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.movieResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "$ref": "#/definitions/controllers.languagesResponse"
                         }
                     },
                     "500": {
@@ -2909,43 +3191,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/movies-tmdb": {
-            "post": {
-                "description": "Get Movies Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+        "/file/movie/count": {
+            "get": {
+                "description": "Count available movies",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "Movie"
-                ],
-                "summary": "Get Movies Short Metadata",
-                "parameters": [
-                    {
-                        "description": "TMDB IDs",
-                        "name": "ids",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.idsRequest"
-                        }
-                    }
+                    "File"
                 ],
+                "summary": "Count available movies",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Total number of available movies",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.movieResponse"
-                            }
+                            "type": "int"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/file/movie/duration": {
+            "get": {
+                "description": "Count movies total duration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Count movies total duration",
+                "responses": {
+                    "200": {
+                        "description": "Total duration in seconds",
+                        "schema": {
+                            "type": "int"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -2956,45 +3243,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-episode-tmdb/{id}/{season}/{episode}": {
+        "/file/movie/search": {
             "get": {
-                "description": "Get TvShow Episode Metadata by TvShow TMDB ID, Season and Episode Number",
+                "description": "Search movie files by name, ordered by relevance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
+                    "File"
                 ],
-                "summary": "Get TvShow Episode Metadata",
+                "summary": "Search movie files",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "TvShow TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Season Number",
-                        "name": "season",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Episode Number",
-                        "name": "episode",
-                        "in": "path",
+                        "description": "Page size, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated audio languages, one of them is required",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated subtitle languages, one of them is required",
+                        "name": "subtitle",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                            "$ref": "#/definitions/controllers.movieFilesResult"
                         }
                     },
                     "400": {
@@ -3018,17 +3320,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-episodes-tmdb/{id}": {
+        "/file/movie/{id}": {
             "get": {
-                "description": "Get TvShow All Episodes by TvShow TMDB ID",
+                "description": "Get movie file info by its Movie TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
+                    "File"
                 ],
-                "summary": "Get TvShow Episodes",
+                "summary": "Get movie file info by its Movie TMDB ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -3042,10 +3343,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
-                            }
+                            "$ref": "#/definitions/controllers.mediaFileResponse"
                         }
                     },
                     "400": {
@@ -3069,46 +3367,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-episodes-tmdb/{id}/ids": {
+        "/file/size": {
             "get": {
-                "description": "Get TvShow All Episodes ids by TvShow TMDB ID",
+                "description": "Get total size taken by all files",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
-                ],
-                "summary": "Get TvShow Episodes ids",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "File"
                 ],
+                "summary": "Get total size",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Total size in bytes",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "type": "int"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                }
+            }
+        },
+        "/file/tv/count": {
+            "get": {
+                "description": "Count available tv shows",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Count available tv shows",
+                "responses": {
+                    "200": {
+                        "description": "Total number of available tv shows",
                         "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "int"
                         }
                     },
                     "500": {
@@ -3120,31 +3419,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-season-episodes-tmdb/{id}/{season}": {
+        "/file/tv/{id}/available": {
             "get": {
-                "description": "Get TvShow Season Episodes Metadata by TvShow TMDB ID and Season Number",
+                "description": "Get all available episodes id for a tv show",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvEpisode"
+                    "File"
                 ],
-                "summary": "Get TvShow Season Episodes Metadata",
+                "summary": "Get tv show available episodes",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TvShow TMDB ID",
+                        "description": "TMDB ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Season Number",
-                        "name": "season",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -3153,7 +3444,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                                "type": "integer"
                             }
                         }
                     },
@@ -3178,31 +3469,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-tmdb/{id}": {
+        "/file/usage": {
             "get": {
-                "description": "Get TvShow Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "description": "Get the storage used by the files, by movies and episodes, by tv show, by season and by year added,\nwith the largest files and the available space of the movie and tv folders",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvShow"
+                    "File"
                 ],
-                "summary": "Get TvShow Metadata",
+                "summary": "Get storage usage",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Number of tv shows, seasons and files listed, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResponse"
+                            "$ref": "#/definitions/controllers.storageUsageResponse"
                         }
                     },
                     "400": {
@@ -3220,37 +3509,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshow-tmdb/{id}/short": {
+        "/file/usage/folders": {
             "get": {
-                "description": "Get TvShow Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "description": "Get the available space of the movie and tv folders, flagged LOW or CRITICAL\nwhen it is under the configured percentages",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvShow"
-                ],
-                "summary": "Get TvShow Short Metadata",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "TMDB ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "File"
                 ],
+                "summary": "Get folders usage",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.tvShowResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.errorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.folderUsageResponse"
+                            }
                         }
                     },
                     "500": {
@@ -3262,36 +3538,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/media/tvshows-tmdb": {
-            "post": {
-                "description": "Get TvShows Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+        "/file/{id}": {
+            "delete": {
+                "description": "Mark a file pending deletion and delete it in the background: its objects are deleted from the storage,\nretrying on failure, then the file is removed. The returned job tells when it is done, see /file/jobs/{id}.\nThe job already in progress is returned if the file is being deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Media Data",
-                    "TvShow"
+                    "File"
                 ],
-                "summary": "Get TvShows Short Metadata",
+                "summary": "Delete a file",
                 "parameters": [
                     {
-                        "description": "TMDB IDs",
-                        "name": "ids",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.idsRequest"
-                        }
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.tvShowResponse"
-                            }
+                            "$ref": "#/definitions/controllers.deletionJobResponse"
                         }
                     },
                     "400": {
@@ -3300,54 +3570,11 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/ping": {
-            "get": {
-                "description": "Ping",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Ping"
-                ],
-                "summary": "Ping",
-                "responses": {
-                    "200": {
-                        "description": "pong",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/rating/count": {
-            "get": {
-                "description": "Get rating count",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rating"
-                ],
-                "summary": "Get rating count",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "integer"
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -3358,36 +3585,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/movie/user/{userID}": {
+        "/file/{id}/subtitles": {
             "get": {
-                "description": "Get user's ratings",
+                "description": "Get the subtitles of a file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "File"
                 ],
-                "summary": "Get user's movie ratings",
+                "summary": "Get file subtitles",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userID",
+                        "description": "File ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResults"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.subtitleResponse"
+                            }
                         }
                     },
                     "400": {
@@ -3396,6 +3620,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3403,38 +3633,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/rating/movie/{mediaID}": {
-            "get": {
-                "description": "Get movie's rating",
+            },
+            "post": {
+                "description": "Add a subtitle to a file. SRT, ASS, SSA and WebVTT files are accepted, the format being given or\ntaken from the file extension, and stored as WebVTT. Reserved to the admins, unless the users are\nallowed to upload subtitles by the configuration.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "File"
                 ],
-                "summary": "Get movie's rating",
+                "summary": "Upload a subtitle",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "mediaID",
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required unless users can upload",
+                        "name": "roles",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, required if not admin",
+                        "name": "user-id",
+                        "in": "header"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Subtitle file, 5 MB at most",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "fre",
+                        "description": "ISO 639-2 language code",
+                        "name": "language",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle format: srt, ass, ssa or vtt",
+                        "name": "format",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResults"
+                            "$ref": "#/definitions/controllers.subtitleResponse"
                         }
                     },
                     "400": {
@@ -3443,53 +3701,89 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
                 }
-            },
-            "post": {
-                "description": "Save movie's rating",
+            }
+        },
+        "/file/{id}/subtitles/{subtitleID}": {
+            "put": {
+                "description": "Replace the content and the language of a subtitle of a file, see the upload. Reserved to the admins.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "File"
                 ],
-                "summary": "Save movie's rating",
+                "summary": "Replace a subtitle",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "mediaID",
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
+                        "description": "Subtitle ID",
+                        "name": "subtitleID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Rating",
-                        "name": "rating",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ratingRequest"
-                        }
+                        "type": "file",
+                        "description": "Subtitle file, 5 MB at most",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "fre",
+                        "description": "ISO 639-2 language code",
+                        "name": "language",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle format: srt, ass, ssa or vtt",
+                        "name": "format",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResponse"
+                            "$ref": "#/definitions/controllers.subtitleResponse"
                         }
                     },
                     "400": {
@@ -3498,6 +3792,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3505,40 +3811,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/rating/movie/{mediaID}/own": {
-            "get": {
-                "description": "Get user's movie rating",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Remove a subtitle from a file and delete it from the storage. Reserved to the admins.",
                 "tags": [
-                    "Rating"
+                    "File"
                 ],
-                "summary": "Get user's movie rating",
+                "summary": "Delete a subtitle",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "mediaID",
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
+                        "description": "Subtitle ID",
+                        "name": "subtitleID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User roles, bingemate-admin is required",
+                        "name": "roles",
                         "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ratingResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3546,6 +3851,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3555,36 +3872,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/tv/user/{userID}": {
+        "/file/{id}/subtitles/{subtitleID}/content": {
             "get": {
-                "description": "Get user's ratings",
+                "description": "Get a subtitle of a file converted to SRT or WebVTT, optionally shifted to fix its synchronization:\nthe times are scaled by the source framerate over the target framerate, then moved by the offset.\nThe cues ending before the start are removed.",
                 "produces": [
-                    "application/json"
+                    "text/vtt",
+                    "application/x-subrip"
                 ],
                 "tags": [
-                    "Rating"
+                    "File"
                 ],
-                "summary": "Get user's tv show ratings",
+                "summary": "Get subtitle content",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userID",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle ID",
+                        "name": "subtitleID",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Output format, srt or vtt (default)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "description": "Offset in milliseconds, negative to show the subtitles earlier",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "23.976:25",
+                        "description": "Framerate the subtitle was made for and framerate of the video",
+                        "name": "framerate",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Subtitle file",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResults"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -3593,6 +3931,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3602,36 +3946,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/tv/{mediaID}": {
+        "/media/base/episode/{id}": {
             "get": {
-                "description": "Get tv show's rating",
+                "description": "Get episode base info by TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "TvEpisode Data",
+                    "Base"
                 ],
-                "summary": "Get tv show's rating",
+                "summary": "Get episode base info",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TV Show ID",
-                        "name": "mediaID",
+                        "description": "TMDB ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResults"
+                            "$ref": "#/definitions/controllers.mediaResponse"
                         }
                     },
                     "400": {
@@ -3640,6 +3979,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3647,38 +3992,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/media/base/episodes": {
             "post": {
-                "description": "Save tv show's rating",
+                "description": "Get episodes base info by TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "TvEpisode Data",
+                    "Base"
                 ],
-                "summary": "Save tv show's rating",
+                "summary": "Get episodes base info",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "TV Show ID",
-                        "name": "mediaID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "Rating",
-                        "name": "rating",
+                        "description": "TMDB IDs",
+                        "name": "ids",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingRequest"
+                            "$ref": "#/definitions/controllers.idsRequest"
                         }
                     }
                 ],
@@ -3686,7 +4020,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.mediaResponse"
+                            }
                         }
                     },
                     "400": {
@@ -3695,6 +4032,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3704,37 +4047,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/tv/{mediaID}/own": {
+        "/media/base/movie/{id}": {
             "get": {
-                "description": "Get user's tv show rating",
+                "description": "Get movie base info by TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "Movie Data",
+                    "Base"
                 ],
-                "summary": "Get user's tv show rating",
+                "summary": "Get movie base info",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "TV Show ID",
-                        "name": "mediaID",
+                        "description": "TMDB ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user-id",
-                        "in": "header",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ratingResponse"
+                            "$ref": "#/definitions/controllers.mediaResponse"
                         }
                     },
                     "400": {
@@ -3743,6 +4080,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3752,21 +4095,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/rating/user/count/{userID}": {
+        "/media/base/tv/{id}": {
             "get": {
-                "description": "Get User's rating count",
+                "description": "Get tvshow base info by TMDB ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rating"
+                    "TvShow Data",
+                    "Base"
                 ],
-                "summary": "Get User's rating count",
+                "summary": "Get tvshow base info",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userID",
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -3775,7 +4119,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/controllers.mediaResponse"
                         }
                     },
                     "400": {
@@ -3784,7 +4128,198 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.errorResponse"
                         }
                     },
-                    "500": {
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/episode-tmdb/{id}": {
+            "get": {
+                "description": "Get TvShow Episode Metadata by TMDB ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Episode Metadata by TMDB ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/episodes-tmdb": {
+            "post": {
+                "description": "Get TvShow Episodes metadata by TMDB IDs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Episodes metadata by TMDB IDs",
+                "parameters": [
+                    {
+                        "description": "TMDB IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.idsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/movie-tmdb/{id}": {
+            "get": {
+                "description": "Get Movie Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "Movie"
+                ],
+                "summary": "Get Movie Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/movie-tmdb/{id}/short": {
+            "get": {
+                "description": "Get Movie Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "Movie"
+                ],
+                "summary": "Get Movie Short Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.errorResponse"
@@ -3792,37 +4327,2931 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.actor": {
+        },
+        "/media/movies-tmdb": {
+            "post": {
+                "description": "Get Movies Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "Movie"
+                ],
+                "summary": "Get Movies Short Metadata",
+                "parameters": [
+                    {
+                        "description": "TMDB IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.idsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.movieResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-episode-tmdb/{id}/{season}/{episode}": {
+            "get": {
+                "description": "Get TvShow Episode Metadata by TvShow TMDB ID, Season and Episode Number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Episode Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TvShow TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season Number",
+                        "name": "season",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Episode Number",
+                        "name": "episode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-episodes-tmdb/{id}": {
+            "get": {
+                "description": "Get TvShow All Episodes by TvShow TMDB ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Episodes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-episodes-tmdb/{id}/ids": {
+            "get": {
+                "description": "Get TvShow All Episodes ids by TvShow TMDB ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Episodes ids",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-season-episodes-tmdb/{id}/{season}": {
+            "get": {
+                "description": "Get TvShow Season Episodes Metadata by TvShow TMDB ID and Season Number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvEpisode"
+                ],
+                "summary": "Get TvShow Season Episodes Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TvShow TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season Number",
+                        "name": "season",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.tvEpisodeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-tmdb/{id}": {
+            "get": {
+                "description": "Get TvShow Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvShow"
+                ],
+                "summary": "Get TvShow Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.tvShowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshow-tmdb/{id}/short": {
+            "get": {
+                "description": "Get TvShow Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvShow"
+                ],
+                "summary": "Get TvShow Short Metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TMDB ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.tvShowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/tvshows-tmdb": {
+            "post": {
+                "description": "Get TvShows Short Metadata by TMDB ID\nThe rating is from BingeMate, not from TMDB (only if available, else from TMDB)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media Data",
+                    "TvShow"
+                ],
+                "summary": "Get TvShows Short Metadata",
+                "parameters": [
+                    {
+                        "description": "TMDB IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.idsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.tvShowResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Ping",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ping"
+                ],
+                "summary": "Ping",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playback/episode/{mediaID}": {
+            "get": {
+                "description": "Get time-limited URLs reading the video manifest, the audio playlists and the subtitles of an episode,\nwhich do not need access to the bucket. They expire at expiresAt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playback"
+                ],
+                "summary": "Get episode playback URLs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.playbackURLsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playback/episode/{mediaID}/master.m3u8": {
+            "get": {
+                "description": "Get an HLS master playlist of an episode, with a rendition per audio language and per WebVTT subtitle,\nwhich any HLS player can read without access to the bucket. Its URLs expire like the playback URLs.\nThe audio in the preferred language is the default one, else the subtitle in it.\nIts BANDWIDTH and CODECS are assumed from the transcoder settings, not read from the file:\nH.264 High 4.0 video capped at 3 Mb/s and AAC-LC audio at 160 kb/s.",
+                "produces": [
+                    "application/vnd.apple.mpegurl"
+                ],
+                "tags": [
+                    "Playback"
+                ],
+                "summary": "Get episode master playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language (ISO 639 or BCP 47)",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HLS master playlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playback/movie/{mediaID}": {
+            "get": {
+                "description": "Get time-limited URLs reading the video manifest, the audio playlists and the subtitles of a movie,\nwhich do not need access to the bucket. They expire at expiresAt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playback"
+                ],
+                "summary": "Get movie playback URLs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.playbackURLsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playback/movie/{mediaID}/master.m3u8": {
+            "get": {
+                "description": "Get an HLS master playlist of a movie, with a rendition per audio language and per WebVTT subtitle,\nwhich any HLS player can read without access to the bucket. Its URLs expire like the playback URLs.\nThe audio in the preferred language is the default one, else the subtitle in it.\nIts BANDWIDTH and CODECS are assumed from the transcoder settings, not read from the file:\nH.264 High 4.0 video capped at 3 Mb/s and AAC-LC audio at 160 kb/s.",
+                "produces": [
+                    "application/vnd.apple.mpegurl"
+                ],
+                "tags": [
+                    "Playback"
+                ],
+                "summary": "Get movie master playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred language (ISO 639 or BCP 47)",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HLS master playlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/playback/playlist/{key}": {
+            "get": {
+                "description": "Get an HLS playlist of the bucket whose segments are presigned URLs. Its URL is returned by the\nplayback endpoints and is valid until its expiry time. A subtitle key followed by .m3u8 gives a\nplaylist made of the subtitle.",
+                "produces": [
+                    "application/vnd.apple.mpegurl"
+                ],
+                "tags": [
+                    "Playback"
+                ],
+                "summary": "Get a signed playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist key in the bucket",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry time (Unix seconds)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HLS playlist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/continue-watching": {
+            "get": {
+                "description": "Get the unfinished movies and the next episodes of the tv shows the user started, the most recently played first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Continue watching",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.continueWatchingResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/episode/{mediaID}": {
+            "get": {
+                "description": "Get the playback position of the user in an episode, 0 if the user never played it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get episode progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Save the playback position of the user in an episode, which is marked watched once 90% of it is reached",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Save episode progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Episode ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Position",
+                        "name": "progress",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.progressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/movie/{mediaID}": {
+            "get": {
+                "description": "Get the playback position of the user in a movie, 0 if the user never played it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get movie progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Save the playback position of the user in a movie, which is marked watched once 90% of it is reached",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Save movie progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Position",
+                        "name": "progress",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.progressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/stats/{userID}": {
+            "get": {
+                "description": "Get the time spent watching, the titles finished, the top genres and the most watched tv shows of a user\nbetween two days, both included. The current year is used by default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get viewing stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of top genres and tv shows, 5 by default and at most 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.viewingStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/tv/{mediaID}/next": {
+            "get": {
+                "description": "Get the available episode of a tv show the user should play next: the last played episode if it is unfinished,\nelse the first unwatched episode following it, ordered by season and episode number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get next episode",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.continueWatchingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/count": {
+            "get": {
+                "description": "Get rating count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get rating count",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/movie/user/{userID}": {
+            "get": {
+                "description": "Get user's ratings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get user's movie ratings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/movie/{mediaID}": {
+            "get": {
+                "description": "Get movie's rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get movie's rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Save movie's rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Save movie's rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating",
+                        "name": "rating",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/movie/{mediaID}/own": {
+            "get": {
+                "description": "Get user's movie rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get user's movie rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/tv/user/{userID}": {
+            "get": {
+                "description": "Get user's ratings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get user's tv show ratings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/tv/{mediaID}": {
+            "get": {
+                "description": "Get tv show's rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get tv show's rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, returned as next by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, ignored with a cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Save tv show's rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Save tv show's rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating",
+                        "name": "rating",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/tv/{mediaID}/own": {
+            "get": {
+                "description": "Get user's tv show rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get user's tv show rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ratingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rating/user/count/{userID}": {
+            "get": {
+                "description": "Get User's rating count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rating"
+                ],
+                "summary": "Get User's rating count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/import": {
+            "post": {
+                "description": "Add movies and tv shows to the user's watch list at once, updating the status of those already in it.\nNothing is imported if an item is invalid.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Import watch list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Items",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/movie": {
+            "get": {
+                "description": "Get the movies of the user's watch list, ordered by movie ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Get movie watch list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated statuses (PLAN_TO_WATCH, WATCHING, FINISHED, ABANDONED), one of them is required",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the movies available in the library",
+                        "name": "available",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.watchListItemResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/movie/{mediaID}": {
+            "get": {
+                "description": "Get the status of a movie in the user's watch list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Get movie watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Add a movie to the user's watch list, or change its status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Save movie watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Status",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a movie from the user's watch list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Delete movie watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/tv": {
+            "get": {
+                "description": "Get the tv shows of the user's watch list, ordered by tv show ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Get tv show watch list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated statuses (PLAN_TO_WATCH, WATCHING, FINISHED, ABANDONED), one of them is required",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the tv shows having episodes in the library",
+                        "name": "available",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.watchListItemResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/tv/{mediaID}": {
+            "get": {
+                "description": "Get the status of a tv show in the user's watch list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Get tv show watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Add a tv show to the user's watch list, or change its status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Save tv show watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Status",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.watchListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a tv show from the user's watch list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WatchList"
+                ],
+                "summary": "Delete tv show watch list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "TV Show ID",
+                        "name": "mediaID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user-id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.errorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.actor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3292
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nicholas Hoult"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "Nicholas Caradoc Hoult (born 7 December 1989) is an English actor. His body of work includes supporting work in big-budget mainstream productions and starring roles in independent projects in both the American and the British film industries. He has been nominated for awards such as a British Academy Film Award and a Critics Choice Award for his work."
+                },
+                "profileUrl": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/t/p/original/rbyi6sOw0dGV3wJzKXDopm2h0NO.jpg"
+                }
+            }
+        },
+        "controllers.actorResults": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.actor"
+                    }
+                },
+                "totalPage": {
+                    "type": "integer",
+                    "example": 71
+                },
+                "totalResult": {
+                    "type": "integer",
+                    "example": 1412
+                }
+            }
+        },
+        "controllers.audioResponse": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "type": "string",
+                    "example": "audio_1.m3u8"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "jpn"
+                }
+            }
+        },
+        "controllers.commentHistoryReponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2023-05-07"
+                }
+            }
+        },
+        "controllers.commentRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "This is a comment"
+                }
+            }
+        },
+        "controllers.commentResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "This is a comment"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                },
+                "mediaId": {
+                    "type": "integer",
+                    "example": 134564
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                }
+            }
+        },
+        "controllers.commentResults": {
+            "type": "object",
+            "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "next": {
+                    "type": "string",
+                    "example": "eyJjIjoiMjAyMy0wNS0wN1QyMDozMToyOFoiLCJpIjoiMTIifQ"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.commentResponse"
+                    }
+                },
+                "totalResult": {
+                    "type": "integer",
+                    "example": 1412
+                }
+            }
+        },
+        "controllers.continueWatchingResponse": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "number",
+                    "example": 6840
+                },
+                "episode": {
+                    "$ref": "#/definitions/controllers.episodeFileResponse"
+                },
+                "movie": {
+                    "$ref": "#/definitions/controllers.movieFileResponse"
+                },
+                "position": {
+                    "type": "number",
+                    "example": 1832.5
+                },
+                "type": {
+                    "type": "string",
+                    "example": "episode"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "watched": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "controllers.crew": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 24310
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mitchell Amundsen"
+                },
+                "profileUrl": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/t/p/original/zK4o3lfe6chC2qDHhxpuO1RJl2X.jpg"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "Director"
+                }
+            }
+        },
+        "controllers.deletionJobResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "RequestError: send request failed"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b9f0a1e-3f1c-4c1e-9d0a-2f4b8c6d7e8f"
+                },
+                "mediaFileId": {
+                    "type": "string",
+                    "example": "5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "FAILED"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                }
+            }
+        },
+        "controllers.duplicateFileResponse": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "linked": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "mediaFileId": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                }
+            }
+        },
+        "controllers.duplicateGroupResponse": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "number",
+                    "example": 8160.5
+                },
+                "episode": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "index.m3u8"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.duplicateFileResponse"
+                    }
+                },
+                "mediaId": {
+                    "type": "integer",
+                    "example": 603
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Matrix"
+                },
+                "reclaimableSize": {
+                    "type": "integer",
+                    "example": 4294967296
+                },
+                "season": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 4294967296
+                },
+                "type": {
+                    "type": "string",
+                    "example": "movie"
+                }
+            }
+        },
+        "controllers.duplicateReportResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.duplicateGroupResponse"
+                    }
+                },
+                "pending": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reclaimableSize": {
+                    "type": "integer",
+                    "example": 8589934592
+                },
+                "scannedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                }
+            }
+        },
+        "controllers.duplicateResolutionResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "$ref": "#/definitions/controllers.duplicateGroupResponse"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.deletionJobResponse"
+                    }
+                },
+                "keep": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                },
+                "relinked": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "controllers.duplicateResolveRequest": {
+            "type": "object",
+            "properties": {
+                "keep": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                }
+            }
+        },
+        "controllers.episodeFileResponse": {
+            "type": "object",
+            "properties": {
+                "episodeNumber": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "file": {
+                    "$ref": "#/definitions/controllers.mediaFileResponse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4137463
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Le plus puissant sorcier du monde révèle Akasha"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2023-03-24"
+                },
+                "seasonNumber": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 200777
+                },
+                "tvShowName": {
+                    "type": "string",
+                    "example": "The Iceblade Sorcerer Shall Rule the World"
+                }
+            }
+        },
+        "controllers.episodeFilesResult": {
+            "type": "object",
+            "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "next": {
+                    "type": "string",
+                    "example": "eyJyIjowLjUsImMiOiIyMDIzLTA1LTA3VDIwOjMxOjI4WiIsImkiOiIxMiJ9"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.episodeFileResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.errorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "error message"
+                },
+                "traceId": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                }
+            }
+        },
+        "controllers.facetValue": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "value": {
+                    "type": "string",
+                    "example": "Action"
+                }
+            }
+        },
+        "controllers.fileUsageResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "episode": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "mediaFileId": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                },
+                "mediaId": {
+                    "type": "integer",
+                    "example": 63056
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Game of Thrones"
+                },
+                "season": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 4294967296
+                },
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 1399
+                },
+                "type": {
+                    "type": "string",
+                    "example": "episode"
+                }
+            }
+        },
+        "controllers.folderUsageResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer",
+                    "example": 219902325555
+                },
+                "availablePercent": {
+                    "type": "number",
+                    "example": 5
+                },
+                "status": {
+                    "type": "string",
+                    "example": "LOW"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4398046511104
+                },
+                "type": {
+                    "type": "string",
+                    "example": "tv"
+                }
+            }
+        },
+        "controllers.genre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 35
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Comédie"
+                }
+            }
+        },
+        "controllers.genreViewingResponse": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "number",
+                    "example": 42.75
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Drama"
+                },
+                "titles": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "controllers.idsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controllers.languageCountResponse": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "integer",
+                    "example": 1345
+                },
+                "language": {
+                    "type": "string",
+                    "example": "fre"
+                },
+                "movies": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "controllers.languagesResponse": {
+            "type": "object",
+            "properties": {
+                "audios": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.languageCountResponse"
+                    }
+                },
+                "subtitles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.languageCountResponse"
+                    }
+                }
+            }
+        },
+        "controllers.libraryFacets": {
+            "type": "object",
+            "properties": {
+                "audioLanguages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                },
+                "durations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                },
+                "ratings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                },
+                "subtitleLanguages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                },
+                "years": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.facetValue"
+                    }
+                }
+            }
+        },
+        "controllers.libraryResults": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/controllers.libraryFacets"
+                },
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.movieResponse"
+                    }
+                },
+                "totalPage": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "totalResult": {
+                    "type": "integer",
+                    "example": 52
+                },
+                "tvShows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.tvShowResponse"
+                    }
+                }
+            }
+        },
+        "controllers.mediaFileResponse": {
+            "type": "object",
+            "properties": {
+                "audios": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.audioResponse"
+                    }
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "duration": {
+                    "type": "number",
+                    "example": 1450.76
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "index.m3u8"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 123456789
+                },
+                "subtitles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.subtitleResponse"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                }
+            }
+        },
+        "controllers.mediaResponse": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
                 "id": {
                     "type": "integer",
-                    "example": 3292
+                    "example": 134564
                 },
                 "name": {
                     "type": "string",
-                    "example": "Nicholas Hoult"
+                    "example": "The Iceblade Sorcerer Shall Rule the World"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2023-01-06"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                }
+            }
+        },
+        "controllers.missingFileResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "movies/603/subtitle_3.vtt"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "subtitle"
+                },
+                "mediaFileId": {
+                    "type": "string",
+                    "example": "5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"
+                },
+                "mediaId": {
+                    "type": "integer",
+                    "example": 603
+                }
+            }
+        },
+        "controllers.missingMediaResponse": {
+            "type": "object",
+            "properties": {
+                "mediaFileId": {
+                    "type": "string",
+                    "example": "5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"
+                },
+                "mediaId": {
+                    "type": "integer",
+                    "example": 603
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "movies/603"
+                }
+            }
+        },
+        "controllers.movieFileResponse": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "$ref": "#/definitions/controllers.mediaFileResponse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 134564
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Apex"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2023-01-06"
+                }
+            }
+        },
+        "controllers.movieFilesResult": {
+            "type": "object",
+            "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "next": {
+                    "type": "string",
+                    "example": "eyJyIjowLjUsImMiOiIyMDIzLTA1LTA3VDIwOjMxOjI4WiIsImkiOiIxMiJ9"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.movieFileResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.movieResponse": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.person"
+                    }
+                },
+                "backdropUrl": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/t/p/original/e7FzphKs5gzoghDotAEp2FeP46u.jpg"
+                },
+                "crew": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.crew"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.genre"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 649609
                 },
                 "overview": {
                     "type": "string",
-                    "example": "Nicholas Caradoc Hoult (born 7 December 1989) is an English actor. His body of work includes supporting work in big-budget mainstream productions and starring roles in independent projects in both the American and the British film industries. He has been nominated for awards such as a British Academy Film Award and a Critics Choice Award for his work."
+                    "example": "Le mal ne saurait survivre une éternité sans un petit coup de pouce.\r Dans cette version moderne du mythe de Dracula..."
                 },
-                "profileUrl": {
+                "posterUrl": {
                     "type": "string",
-                    "example": "https://image.tmdb.org/t/p/original/rbyi6sOw0dGV3wJzKXDopm2h0NO.jpg"
+                    "example": "https://image.tmdb.org/t/p/original/lm3y4RNPu4aRDePsX5CkB9ndEdQ.jpg"
+                },
+                "present": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "2023-04-07"
+                },
+                "studios": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.studio"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Renfield"
+                },
+                "voteAverage": {
+                    "type": "number",
+                    "example": 7.252
+                },
+                "voteCount": {
+                    "type": "integer",
+                    "example": 278
                 }
             }
         },
-        "controllers.actorResults": {
+        "controllers.movieResults": {
             "type": "object",
             "properties": {
                 "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.actor"
+                        "$ref": "#/definitions/controllers.movieResponse"
                     }
                 },
                 "totalPage": {
@@ -3835,60 +7264,98 @@ const docTemplate = `{
                 }
             }
         },
-        "controllers.audioResponse": {
+        "controllers.person": {
             "type": "object",
             "properties": {
-                "filename": {
+                "character": {
                     "type": "string",
-                    "example": "audio_1.m3u8"
+                    "example": "R.M. Renfield"
                 },
-                "language": {
+                "id": {
+                    "type": "integer",
+                    "example": 3292
+                },
+                "name": {
                     "type": "string",
-                    "example": "jpn"
+                    "example": "Nicholas Hoult"
+                },
+                "profileUrl": {
+                    "type": "string",
+                    "example": "https://image.tmdb.org/t/p/original/rbyi6sOw0dGV3wJzKXDopm2h0NO.jpg"
                 }
             }
         },
-        "controllers.commentHistoryReponse": {
+        "controllers.playbackTrackResponse": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer",
-                    "example": 12
+                "language": {
+                    "type": "string",
+                    "example": "fre"
                 },
-                "date": {
+                "url": {
                     "type": "string",
-                    "example": "2023-05-07"
+                    "example": "/media-service/playback/playlist/movies/603/audio_1.m3u8?expires=1683491488\u0026signature=3q2-7w"
                 }
             }
         },
-        "controllers.commentRequest": {
+        "controllers.playbackURLsResponse": {
             "type": "object",
             "properties": {
-                "content": {
+                "audios": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.playbackTrackResponse"
+                    }
+                },
+                "expiresAt": {
                     "type": "string",
-                    "example": "This is a comment"
+                    "example": "2023-05-08T02:31:28+02:00"
+                },
+                "manifest": {
+                    "type": "string",
+                    "example": "/media-service/playback/playlist/movies/603/index.m3u8?expires=1683491488\u0026signature=3q2-7w"
+                },
+                "subtitles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.playbackTrackResponse"
+                    }
+                }
+            }
+        },
+        "controllers.progressRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "number",
+                    "example": 1832.5
+                }
+            }
+        },
+        "controllers.ratingRequest": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "example": 5
                 }
             }
         },
-        "controllers.commentResponse": {
+        "controllers.ratingResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string",
-                    "example": "This is a comment"
-                },
                 "createdAt": {
                     "type": "string",
                     "example": "2023-05-07T20:31:28.327382+02:00"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
-                },
                 "mediaId": {
                     "type": "integer",
                     "example": 134564
                 },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
                 "updatedAt": {
                     "type": "string",
                     "example": "2023-05-07T20:31:28.327382+02:00"
@@ -3899,297 +7366,295 @@ const docTemplate = `{
                 }
             }
         },
-        "controllers.commentResults": {
+        "controllers.ratingResults": {
             "type": "object",
             "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "next": {
+                    "type": "string",
+                    "example": "eyJjIjoiMjAyMy0wNS0wN1QyMDozMToyOFoiLCJpIjoiMTIifQ"
+                },
                 "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.commentResponse"
+                        "$ref": "#/definitions/controllers.ratingResponse"
                     }
                 },
                 "totalResult": {
                     "type": "integer",
-                    "example": 1412
+                    "example": 14
                 }
             }
         },
-        "controllers.crew": {
+        "controllers.recommendationResponse": {
             "type": "object",
             "properties": {
+                "because": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.recommendedTitleResponse"
+                    }
+                },
                 "id": {
                     "type": "integer",
-                    "example": 24310
+                    "example": 603
                 },
                 "name": {
                     "type": "string",
-                    "example": "Mitchell Amundsen"
+                    "example": "The Matrix"
                 },
-                "profileUrl": {
-                    "type": "string",
-                    "example": "https://image.tmdb.org/t/p/original/zK4o3lfe6chC2qDHhxpuO1RJl2X.jpg"
+                "score": {
+                    "type": "number",
+                    "example": 1.25
                 },
-                "role": {
+                "type": {
                     "type": "string",
-                    "example": "Director"
+                    "example": "movie"
                 }
             }
         },
-        "controllers.episodeFileResponse": {
+        "controllers.recommendedTitleResponse": {
             "type": "object",
             "properties": {
-                "episodeNumber": {
-                    "type": "integer",
-                    "example": 12
-                },
-                "file": {
-                    "$ref": "#/definitions/controllers.mediaFileResponse"
-                },
                 "id": {
                     "type": "integer",
-                    "example": 4137463
+                    "example": 603
                 },
                 "name": {
                     "type": "string",
-                    "example": "Le plus puissant sorcier du monde révèle Akasha"
-                },
-                "releaseDate": {
-                    "type": "string",
-                    "example": "2023-03-24"
-                },
-                "seasonNumber": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "tvShowId": {
-                    "type": "integer",
-                    "example": 200777
+                    "example": "The Matrix"
                 },
-                "tvShowName": {
+                "type": {
                     "type": "string",
-                    "example": "The Iceblade Sorcerer Shall Rule the World"
+                    "example": "movie"
                 }
             }
         },
-        "controllers.episodeFilesResult": {
+        "controllers.reconciliationResponse": {
             "type": "object",
             "properties": {
-                "results": {
+                "checkedEpisodes": {
+                    "type": "integer",
+                    "example": 5230
+                },
+                "checkedMovies": {
+                    "type": "integer",
+                    "example": 412
+                },
+                "consistent": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "missingFiles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.episodeFileResponse"
+                        "$ref": "#/definitions/controllers.missingFileResponse"
                     }
                 },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.errorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "error message"
-                }
-            }
-        },
-        "controllers.genre": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 35
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Comédie"
-                }
-            }
-        },
-        "controllers.idsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
+                "missingMedia": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/controllers.missingMediaResponse"
                     }
+                },
+                "orphanedObjects": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "movies/603/audio_2.m3u8"
+                    ]
+                },
+                "orphanedPrefixes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tv-shows/1234567"
+                    ]
+                },
+                "repaired": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "controllers.mediaFileResponse": {
+        "controllers.retentionCandidateResponse": {
             "type": "object",
             "properties": {
-                "audios": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.audioResponse"
-                    }
-                },
-                "createdAt": {
+                "addedAt": {
                     "type": "string",
                     "example": "2023-05-07T20:31:28.327382+02:00"
                 },
-                "duration": {
-                    "type": "number",
-                    "example": 1450.76
+                "episode": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "filename": {
+                "lastViewed": {
                     "type": "string",
-                    "example": "index.m3u8"
+                    "example": "2023-06-01T00:00:00Z"
                 },
-                "id": {
+                "mediaFileId": {
                     "type": "string",
                     "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
                 },
-                "size": {
+                "mediaId": {
                     "type": "integer",
-                    "example": 123456789
+                    "example": 63056
                 },
-                "subtitles": {
+                "name": {
+                    "type": "string",
+                    "example": "Game of Thrones"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 3.5
+                },
+                "reasons": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.subtitleResponse"
-                    }
-                },
-                "updatedAt": {
-                    "type": "string",
-                    "example": "2023-05-07T20:31:28.327382+02:00"
-                }
-            }
-        },
-        "controllers.mediaResponse": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string",
-                    "example": "2023-05-07T20:31:28.327382+02:00"
+                        "type": "string"
+                    },
+                    "example": [
+                        "UNWATCHED",
+                        "OLD_SEASON"
+                    ]
                 },
-                "id": {
+                "season": {
                     "type": "integer",
-                    "example": 134564
+                    "example": 1
                 },
-                "name": {
-                    "type": "string",
-                    "example": "The Iceblade Sorcerer Shall Rule the World"
+                "size": {
+                    "type": "integer",
+                    "example": 4294967296
                 },
-                "releaseDate": {
-                    "type": "string",
-                    "example": "2023-01-06"
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 1399
                 },
-                "updatedAt": {
+                "type": {
                     "type": "string",
-                    "example": "2023-05-07T20:31:28.327382+02:00"
+                    "example": "episode"
                 }
             }
         },
-        "controllers.movieFileResponse": {
+        "controllers.retentionPolicyResponse": {
             "type": "object",
             "properties": {
-                "file": {
-                    "$ref": "#/definitions/controllers.mediaFileResponse"
+                "delete": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "id": {
+                "interval": {
+                    "type": "string",
+                    "example": "24h0m0s"
+                },
+                "keepSeasons": {
                     "type": "integer",
-                    "example": 134564
+                    "example": 2
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Apex"
+                "protectRating": {
+                    "type": "number",
+                    "example": 4.5
                 },
-                "releaseDate": {
-                    "type": "string",
-                    "example": "2023-01-06"
+                "unwatchedDays": {
+                    "type": "integer",
+                    "example": 180
                 }
             }
         },
-        "controllers.movieFilesResult": {
+        "controllers.retentionReportResponse": {
             "type": "object",
             "properties": {
-                "results": {
+                "candidates": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.movieFileResponse"
+                        "$ref": "#/definitions/controllers.retentionCandidateResponse"
                     }
                 },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.movieResponse": {
-            "type": "object",
-            "properties": {
-                "actors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.person"
-                    }
+                "checkedFiles": {
+                    "type": "integer",
+                    "example": 5642
                 },
-                "backdropUrl": {
-                    "type": "string",
-                    "example": "https://image.tmdb.org/t/p/original/e7FzphKs5gzoghDotAEp2FeP46u.jpg"
+                "deleted": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "crew": {
+                "errors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.crew"
+                        "type": "string"
                     }
                 },
-                "genres": {
+                "evaluatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "jobs": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.genre"
+                        "$ref": "#/definitions/controllers.deletionJobResponse"
                     }
                 },
-                "id": {
+                "pending": {
                     "type": "integer",
-                    "example": 649609
-                },
-                "overview": {
-                    "type": "string",
-                    "example": "Le mal ne saurait survivre une éternité sans un petit coup de pouce.\r Dans cette version moderne du mythe de Dracula..."
-                },
-                "posterUrl": {
-                    "type": "string",
-                    "example": "https://image.tmdb.org/t/p/original/lm3y4RNPu4aRDePsX5CkB9ndEdQ.jpg"
+                    "example": 0
                 },
-                "present": {
-                    "type": "boolean",
-                    "example": true
+                "policy": {
+                    "$ref": "#/definitions/controllers.retentionPolicyResponse"
                 },
-                "releaseDate": {
-                    "type": "string",
-                    "example": "2023-04-07"
+                "protected": {
+                    "type": "integer",
+                    "example": 12
                 },
-                "studios": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.studio"
-                    }
+                "reclaimableSize": {
+                    "type": "integer",
+                    "example": 536870912000
+                }
+            }
+        },
+        "controllers.searchResultResponse": {
+            "type": "object",
+            "properties": {
+                "episode": {
+                    "$ref": "#/definitions/controllers.tvEpisodeResponse"
                 },
-                "title": {
-                    "type": "string",
-                    "example": "Renfield"
+                "movie": {
+                    "$ref": "#/definitions/controllers.movieResponse"
                 },
-                "voteAverage": {
-                    "type": "number",
-                    "example": 7.252
+                "person": {
+                    "$ref": "#/definitions/controllers.actor"
                 },
-                "voteCount": {
-                    "type": "integer",
-                    "example": 278
+                "present": {
+                    "type": "boolean"
+                },
+                "tvShow": {
+                    "$ref": "#/definitions/controllers.tvShowResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "movie",
+                        "tv",
+                        "episode",
+                        "person"
+                    ],
+                    "example": "movie"
                 }
             }
         },
-        "controllers.movieResults": {
+        "controllers.searchResults": {
             "type": "object",
             "properties": {
                 "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.movieResponse"
+                        "$ref": "#/definitions/controllers.searchResultResponse"
                     }
                 },
                 "totalPage": {
@@ -4199,76 +7664,91 @@ const docTemplate = `{
                 "totalResult": {
                     "type": "integer",
                     "example": 1412
+                },
+                "totals": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/controllers.searchTotal"
+                    }
                 }
             }
         },
-        "controllers.person": {
+        "controllers.searchTotal": {
             "type": "object",
             "properties": {
-                "character": {
-                    "type": "string",
-                    "example": "R.M. Renfield"
-                },
-                "id": {
+                "totalPage": {
                     "type": "integer",
-                    "example": 3292
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Nicholas Hoult"
+                    "example": 71
                 },
-                "profileUrl": {
-                    "type": "string",
-                    "example": "https://image.tmdb.org/t/p/original/rbyi6sOw0dGV3wJzKXDopm2h0NO.jpg"
-                }
-            }
-        },
-        "controllers.ratingRequest": {
-            "type": "object",
-            "properties": {
-                "rating": {
+                "totalResult": {
                     "type": "integer",
-                    "example": 5
+                    "example": 1412
                 }
             }
         },
-        "controllers.ratingResponse": {
+        "controllers.seasonUsageResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "files": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "name": {
                     "type": "string",
-                    "example": "2023-05-07T20:31:28.327382+02:00"
+                    "example": "Game of Thrones"
                 },
-                "mediaId": {
+                "season": {
                     "type": "integer",
-                    "example": 134564
+                    "example": 8
                 },
-                "rating": {
+                "size": {
                     "type": "integer",
-                    "example": 5
-                },
-                "updatedAt": {
-                    "type": "string",
-                    "example": "2023-05-07T20:31:28.327382+02:00"
+                    "example": 12884901888
                 },
-                "userId": {
-                    "type": "string",
-                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 1399
                 }
             }
         },
-        "controllers.ratingResults": {
+        "controllers.storageUsageResponse": {
             "type": "object",
             "properties": {
-                "results": {
+                "episodes": {
+                    "$ref": "#/definitions/controllers.usageResponse"
+                },
+                "folders": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.ratingResponse"
+                        "$ref": "#/definitions/controllers.folderUsageResponse"
                     }
                 },
-                "totalResult": {
-                    "type": "integer",
-                    "example": 14
+                "largestFiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.fileUsageResponse"
+                    }
+                },
+                "movies": {
+                    "$ref": "#/definitions/controllers.usageResponse"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.seasonUsageResponse"
+                    }
+                },
+                "tvShows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.tvShowUsageResponse"
+                    }
+                },
+                "years": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.yearUsageResponse"
+                    }
                 }
             }
         },
@@ -4296,12 +7776,50 @@ const docTemplate = `{
                     "type": "string",
                     "example": "subtitle_1.vtt"
                 },
+                "id": {
+                    "type": "string",
+                    "example": "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"
+                },
                 "language": {
                     "type": "string",
                     "example": "fre"
                 }
             }
         },
+        "controllers.suggestionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Star Wars"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "library",
+                        "tmdb"
+                    ],
+                    "example": "library"
+                },
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 1399
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "movie",
+                        "tv",
+                        "episode"
+                    ],
+                    "example": "movie"
+                }
+            }
+        },
         "controllers.tvEpisodeResponse": {
             "type": "object",
             "properties": {
@@ -4458,6 +7976,228 @@ const docTemplate = `{
                     "example": 1412
                 }
             }
+        },
+        "controllers.tvShowUsageResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "integer",
+                    "example": 73
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Game of Thrones"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 107374182400
+                },
+                "tvShowId": {
+                    "type": "integer",
+                    "example": 1399
+                }
+            }
+        },
+        "controllers.tvShowViewingResponse": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "integer",
+                    "example": 73
+                },
+                "hours": {
+                    "type": "number",
+                    "example": 61.2
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1399
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Game of Thrones"
+                }
+            }
+        },
+        "controllers.usageResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "integer",
+                    "example": 412
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 1099511627776
+                }
+            }
+        },
+        "controllers.viewingDayResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2023-05-07"
+                },
+                "hours": {
+                    "type": "number",
+                    "example": 2.5
+                }
+            }
+        },
+        "controllers.viewingStatsResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.viewingDayResponse"
+                    }
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2023-12-31"
+                },
+                "episodesFinished": {
+                    "type": "integer",
+                    "example": 260
+                },
+                "hours": {
+                    "type": "number",
+                    "example": 312.4
+                },
+                "moviesFinished": {
+                    "type": "integer",
+                    "example": 48
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "topGenres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.genreViewingResponse"
+                    }
+                },
+                "topTvShows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.tvShowViewingResponse"
+                    }
+                },
+                "tvShowsWatched": {
+                    "type": "integer",
+                    "example": 14
+                }
+            }
+        },
+        "controllers.watchListImportItem": {
+            "type": "object",
+            "properties": {
+                "mediaId": {
+                    "type": "integer",
+                    "example": 550
+                },
+                "status": {
+                    "type": "string",
+                    "example": "FINISHED"
+                }
+            }
+        },
+        "controllers.watchListImportRequest": {
+            "type": "object",
+            "properties": {
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.watchListImportItem"
+                    }
+                },
+                "tvShows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.watchListImportItem"
+                    }
+                }
+            }
+        },
+        "controllers.watchListImportResponse": {
+            "type": "object",
+            "properties": {
+                "movies": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "tvShows": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "controllers.watchListItemResponse": {
+            "type": "object",
+            "properties": {
+                "mediaId": {
+                    "type": "integer",
+                    "example": 550
+                },
+                "status": {
+                    "type": "string",
+                    "example": "WATCHING"
+                }
+            }
+        },
+        "controllers.watchListStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "PLAN_TO_WATCH"
+                }
+            }
+        },
+        "controllers.watchProgressResponse": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "number",
+                    "example": 6840
+                },
+                "position": {
+                    "type": "number",
+                    "example": 1832.5
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2023-05-07T20:31:28.327382+02:00"
+                },
+                "watched": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "controllers.yearUsageResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 322122547200
+                },
+                "type": {
+                    "type": "string",
+                    "example": "movie"
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2023
+                }
+            }
         }
     }
 }`
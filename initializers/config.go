@@ -0,0 +1,114 @@
+package initializers
+
+import (
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const redactedValue = "********"
+
+// readConfigFile reads a YAML (.yaml, .yml) or TOML (.toml) configuration file and returns its values
+// keyed by environment variable name. Keys are case-insensitive and nested tables are joined with
+// underscores, so that "db: {host: x}" and "DB_HOST: x" both set DB_HOST.
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration file: %w", err)
+	}
+	var values map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("unknown configuration file format %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing configuration file %s: %w", path, err)
+	}
+
+	environment := map[string]string{}
+	if err := flattenConfig("", values, environment); err != nil {
+		return nil, fmt.Errorf("configuration file %s: %w", path, err)
+	}
+	known := envKeys()
+	for _, key := range sortedKeys(environment) {
+		if !known[key] {
+			return nil, fmt.Errorf("configuration file %s: unknown key %s", path, key)
+		}
+	}
+	return environment, nil
+}
+
+func flattenConfig(prefix string, values map[string]any, environment map[string]string) error {
+	for key, value := range values {
+		key = strings.ToUpper(prefix + key)
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flattenConfig(key+"_", value, environment); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("lists are not supported, found one for %s", key)
+		case nil:
+			environment[key] = ""
+		default:
+			environment[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+// envKeys returns the environment variable names of the Env fields
+func envKeys() map[string]bool {
+	keys := map[string]bool{}
+	envType := reflect.TypeOf(Env{})
+	for i := 0; i < envType.NumField(); i++ {
+		if key, ok := envType.Field(i).Tag.Lookup("env"); ok {
+			keys[strings.Split(key, ",")[0]] = true
+		}
+	}
+	return keys
+}
+
+// Redacted returns the configuration values keyed by environment variable name,
+// with the secrets replaced by a placeholder when they are set
+func (e Env) Redacted() map[string]any {
+	values := map[string]any{}
+	envType := reflect.TypeOf(e)
+	envValue := reflect.ValueOf(e)
+	for i := 0; i < envType.NumField(); i++ {
+		field := envType.Field(i)
+		key, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		value := envValue.Field(i).Interface()
+		if duration, ok := value.(time.Duration); ok {
+			// printed as 6h0m0s rather than nanoseconds, so that it can be loaded again
+			value = duration.String()
+		}
+		if field.Tag.Get("redact") == "true" && !envValue.Field(i).IsZero() {
+			value = redactedValue
+		}
+		values[strings.Split(key, ",")[0]] = value
+	}
+	return values
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,31 +2,38 @@ package initializers
 
 import (
 	"fmt"
-	"github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/migrations"
+	"github.com/bingemate/media-service/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
 )
 
 func ConnectToDB(env Env) (*gorm.DB, error) {
-	dns := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Europe/Paris",
-		env.DBHost, env.DBUser, env.DBPassword, env.DBName, env.DBPort)
-	log.Println("Connecting to database...")
+	dns := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		env.DBHost, env.DBUser, env.DBPassword, env.DBName, env.DBPort, env.DBSSLMode, env.DBTimeZone)
+	slog.Info("Connecting to database...", "host", env.DBHost, "port", env.DBPort, "name", env.DBName)
 	db, err := gorm.Open(postgres.Open(dns), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
-	log.Println("Connected to database")
+	slog.Info("Connected to database")
+	err = db.Use(tracing.NewGormPlugin())
+	if err != nil {
+		return nil, err
+	}
 
 	if env.DBSync {
-		log.Println("Syncing database...")
-		db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
-		db.Exec("CREATE EXTENSION IF NOT EXISTS \"unaccent\"")
-		err = repository.Migrate(db)
+		slog.Info("Syncing database...")
+		migrator, err := migrations.NewMigrator(db)
+		if err != nil {
+			return nil, err
+		}
+		applied, err := migrator.Up()
 		if err != nil {
 			return nil, err
 		}
-		log.Println("Database synced")
+		slog.Info("Database synced", "applied_migrations", applied)
 	}
 	return db, nil
 }
@@ -1,32 +1,65 @@
 package initializers
 
 import (
+	"errors"
+	"fmt"
 	"github.com/caarlos0/env/v8"
 	"github.com/joho/godotenv"
+	"log/slog"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Env is the configuration of the service. Each value is read from the environment variable named in
+// its env tag, then from the configuration file (see LoadEnv), then from its default (envDefault).
+// Values tagged with redact are secrets hidden by Redacted.
 type Env struct {
-	Port              string `env:"PORT" envDefault:"8080"`
-	LogFile           string `env:"LOG_FILE" envDefault:"gin.log"`
-	MovieTargetFolder string `env:"MOVIE_TARGET_FOLDER" envDefault:"./"`
-	TvTargetFolder    string `env:"TV_TARGET_FOLDER" envDefault:"./"`
-	TMDBApiKey        string `env:"TMDB_API_KEY" envDefault:""`
-	DBSync            bool   `env:"DB_SYNC" envDefault:"false"`
-	DBHost            string `env:"DB_HOST" envDefault:"localhost"`
-	DBPort            string `env:"DB_PORT" envDefault:"5432"`
-	DBUser            string `env:"DB_USER" envDefault:"postgres"`
-	DBPassword        string `env:"DB_PASSWORD" envDefault:"postgres"`
-	DBName            string `env:"DB_NAME" envDefault:"postgres"`
-	RedisHost         string `env:"REDIS_HOST" envDefault:"localhost:6379"`
-	S3AccessKeyId     string `env:"S3_ACCESS_KEY_ID" envDefault:""`
-	S3SecretAccessKey string `env:"S3_SECRET_ACCESS_KEY" envDefault:""`
-	S3BucketName      string `env:"S3_BUCKET_NAME" envDefault:""`
-	S3Endpoint        string `env:"S3_ENDPOINT" envDefault:"https://s3.fr-par.scw.cloud"`
-	RedisPassword     string `env:"REDIS_PASSWORD" envDefault:""`
+	Port                   string        `env:"PORT" envDefault:"8080"`
+	LogFile                string        `env:"LOG_FILE" envDefault:"gin.log"`
+	LogLevel               string        `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat              string        `env:"LOG_FORMAT" envDefault:"json"`
+	MovieTargetFolder      string        `env:"MOVIE_TARGET_FOLDER" envDefault:"./"`
+	TvTargetFolder         string        `env:"TV_TARGET_FOLDER" envDefault:"./"`
+	TMDBApiKey             string        `env:"TMDB_API_KEY" redact:"true"`
+	DBSync                 bool          `env:"DB_SYNC" envDefault:"false"`
+	DBHost                 string        `env:"DB_HOST" envDefault:"localhost"`
+	DBPort                 string        `env:"DB_PORT" envDefault:"5432"`
+	DBUser                 string        `env:"DB_USER"`
+	DBPassword             string        `env:"DB_PASSWORD" redact:"true"`
+	DBName                 string        `env:"DB_NAME" envDefault:"postgres"`
+	DBSSLMode              string        `env:"DB_SSL_MODE" envDefault:"disable"`
+	DBTimeZone             string        `env:"DB_TIMEZONE" envDefault:"Europe/Paris"`
+	RedisHost              string        `env:"REDIS_HOST" envDefault:"localhost:6379"`
+	S3AccessKeyId          string        `env:"S3_ACCESS_KEY_ID" redact:"true"`
+	S3SecretAccessKey      string        `env:"S3_SECRET_ACCESS_KEY" redact:"true"`
+	S3BucketName           string        `env:"S3_BUCKET_NAME"`
+	S3Endpoint             string        `env:"S3_ENDPOINT" envDefault:"https://s3.fr-par.scw.cloud"`
+	S3Region               string        `env:"S3_REGION" envDefault:"fr-par"`
+	S3URLExpiry            time.Duration `env:"S3_URL_EXPIRY" envDefault:"6h"`
+	PlaybackSigningKey     string        `env:"PLAYBACK_SIGNING_KEY" redact:"true"`
+	RedisPassword          string        `env:"REDIS_PASSWORD" envDefault:"" redact:"true"`
+	SubtitleUserUpload     bool          `env:"SUBTITLE_USER_UPLOAD" envDefault:"false"`
+	StorageLowPercent      float64       `env:"STORAGE_LOW_PERCENT" envDefault:"15"`
+	StorageCriticalPercent float64       `env:"STORAGE_CRITICAL_PERCENT" envDefault:"5"`
+	StorageCheckInterval   time.Duration `env:"STORAGE_CHECK_INTERVAL" envDefault:"15m"`
+	RetentionUnwatchedDays int           `env:"RETENTION_UNWATCHED_DAYS" envDefault:"0"`
+	RetentionKeepSeasons   int           `env:"RETENTION_KEEP_SEASONS" envDefault:"0"`
+	RetentionProtectRating float64       `env:"RETENTION_PROTECT_RATING" envDefault:"0"`
+	RetentionDelete        bool          `env:"RETENTION_DELETE" envDefault:"false"`
+	RetentionInterval      time.Duration `env:"RETENTION_INTERVAL" envDefault:"24h"`
+	DuplicatesInterval     time.Duration `env:"DUPLICATES_INTERVAL" envDefault:"24h"`
+	TracingExporter        string        `env:"TRACING_EXPORTER" envDefault:"none"`
+	TracingServiceName     string        `env:"OTEL_SERVICE_NAME" envDefault:"media-service"`
+	TracingSampleRatio     float64       `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
 }
 
-func LoadEnv() (Env, error) {
+// LoadEnv loads the configuration from the environment variables (including the ones of the .env file),
+// layered over the YAML or TOML configuration file at configFile, or at $CONFIG_FILE if configFile is empty.
+// The configuration is not validated, see Env.Validate.
+func LoadEnv(configFile string) (Env, error) {
 	var envCfg = &Env{}
 
 	err := godotenv.Load(".env")
@@ -34,8 +67,118 @@ func LoadEnv() (Env, error) {
 		return Env{}, err
 	}
 
-	if err := env.Parse(envCfg); err != nil {
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
+	}
+	environment := map[string]string{}
+	if configFile != "" {
+		environment, err = readConfigFile(configFile)
+		if err != nil {
+			return Env{}, err
+		}
+	}
+	for _, variable := range os.Environ() {
+		// variables set but empty do not override the file
+		if key, value, _ := strings.Cut(variable, "="); value != "" || environment[key] == "" {
+			environment[key] = value
+		}
+	}
+
+	if err := env.ParseWithOptions(envCfg, env.Options{Environment: environment}); err != nil {
 		return Env{}, err
 	}
 	return *envCfg, nil
 }
+
+// Service is a service used by some commands only, whose credentials are then required.
+// ServicePlayback is the signing of the playback URLs.
+type Service string
+
+const (
+	ServiceTMDB     Service = "tmdb"
+	ServiceBucket   Service = "bucket"
+	ServicePlayback Service = "playback"
+)
+
+// minPlaybackSigningKeyLength is the minimum length of the key signing the playback URLs, 256 bits
+const minPlaybackSigningKeyLength = 32
+
+// Validate checks that the required values are set and that the others are valid, returning an error listing
+// every problem found. The credentials of the given services are required, the database ones always are.
+func (e Env) Validate(services ...Service) error {
+	var errs []error
+	required := map[string]string{
+		"DB_USER":     e.DBUser,
+		"DB_PASSWORD": e.DBPassword,
+	}
+	if slices.Contains(services, ServiceTMDB) {
+		required["TMDB_API_KEY"] = e.TMDBApiKey
+	}
+	if slices.Contains(services, ServiceBucket) {
+		required["S3_ACCESS_KEY_ID"] = e.S3AccessKeyId
+		required["S3_SECRET_ACCESS_KEY"] = e.S3SecretAccessKey
+		required["S3_BUCKET_NAME"] = e.S3BucketName
+	}
+	if slices.Contains(services, ServicePlayback) {
+		required["PLAYBACK_SIGNING_KEY"] = e.PlaybackSigningKey
+		if e.PlaybackSigningKey != "" && len(e.PlaybackSigningKey) < minPlaybackSigningKeyLength {
+			errs = append(errs, fmt.Errorf("PLAYBACK_SIGNING_KEY must be at least %d characters long", minPlaybackSigningKeyLength))
+		}
+		if e.PlaybackSigningKey != "" && e.PlaybackSigningKey == e.S3SecretAccessKey {
+			errs = append(errs, errors.New("PLAYBACK_SIGNING_KEY must not be the S3_SECRET_ACCESS_KEY"))
+		}
+	}
+	for _, key := range sortedKeys(required) {
+		if required[key] == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	if port, err := strconv.Atoi(e.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT %q is not a valid port", e.Port))
+	}
+	if port, err := strconv.Atoi(e.DBPort); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("DB_PORT %q is not a valid port", e.DBPort))
+	}
+	if !slices.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, e.DBSSLMode) {
+		errs = append(errs, fmt.Errorf("DB_SSL_MODE %q is invalid, expected disable, allow, prefer, require, verify-ca or verify-full", e.DBSSLMode))
+	}
+	if _, err := time.LoadLocation(e.DBTimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("DB_TIMEZONE %q is not a known time zone", e.DBTimeZone))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(e.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL %q is invalid, expected debug, info, warn or error", e.LogLevel))
+	}
+	if !slices.Contains([]string{"json", "text"}, strings.ToLower(e.LogFormat)) {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT %q is invalid, expected json or text", e.LogFormat))
+	}
+	if !slices.Contains([]string{"", "none", "otlp", "stdout"}, strings.ToLower(e.TracingExporter)) {
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER %q is invalid, expected otlp, stdout or none", e.TracingExporter))
+	}
+	if e.S3URLExpiry <= 0 {
+		errs = append(errs, fmt.Errorf("S3_URL_EXPIRY %v must be positive", e.S3URLExpiry))
+	}
+	if e.StorageCriticalPercent < 0 || e.StorageCriticalPercent > e.StorageLowPercent || e.StorageLowPercent > 100 {
+		errs = append(errs, fmt.Errorf("STORAGE_CRITICAL_PERCENT %v and STORAGE_LOW_PERCENT %v must be percentages, the critical one being the lowest",
+			e.StorageCriticalPercent, e.StorageLowPercent))
+	}
+	if e.StorageCheckInterval <= 0 {
+		errs = append(errs, fmt.Errorf("STORAGE_CHECK_INTERVAL %v must be positive", e.StorageCheckInterval))
+	}
+	if e.RetentionUnwatchedDays < 0 || e.RetentionKeepSeasons < 0 || e.RetentionProtectRating < 0 {
+		errs = append(errs, errors.New("RETENTION_UNWATCHED_DAYS, RETENTION_KEEP_SEASONS and RETENTION_PROTECT_RATING must not be negative"))
+	}
+	if e.RetentionInterval <= 0 {
+		errs = append(errs, fmt.Errorf("RETENTION_INTERVAL %v must be positive", e.RetentionInterval))
+	}
+	if e.DuplicatesInterval <= 0 {
+		errs = append(errs, fmt.Errorf("DUPLICATES_INTERVAL %v must be positive", e.DuplicatesInterval))
+	}
+	if e.TracingSampleRatio < 0 || e.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("TRACING_SAMPLE_RATIO %v must be between 0 and 1", e.TracingSampleRatio))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package initializers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validEnv() Env {
+	return Env{
+		Port:                 "8080",
+		LogLevel:             "info",
+		LogFormat:            "json",
+		DBUser:               "media",
+		DBPassword:           "secret",
+		DBPort:               "5432",
+		DBSSLMode:            "disable",
+		DBTimeZone:           "Europe/Paris",
+		S3URLExpiry:          time.Hour,
+		StorageLowPercent:    15,
+		StorageCheckInterval: time.Hour,
+		RetentionInterval:    time.Hour,
+		DuplicatesInterval:   time.Hour,
+	}
+}
+
+func TestValidateServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []Service
+		missing  []string
+	}{
+		{"no service", nil, nil},
+		{"tmdb", []Service{ServiceTMDB}, []string{"TMDB_API_KEY"}},
+		{"bucket", []Service{ServiceBucket}, []string{"S3_ACCESS_KEY_ID", "S3_SECRET_ACCESS_KEY", "S3_BUCKET_NAME"}},
+		{"playback", []Service{ServicePlayback}, []string{"PLAYBACK_SIGNING_KEY"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validEnv().Validate(test.services...)
+			if len(test.missing) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			for _, key := range test.missing {
+				if !strings.Contains(err.Error(), key+" is required") {
+					t.Errorf("Validate() = %v, want %s to be required", err, key)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDatabaseAlwaysRequired(t *testing.T) {
+	env := validEnv()
+	env.DBPassword = ""
+	if err := env.Validate(); err == nil || !strings.Contains(err.Error(), "DB_PASSWORD is required") {
+		t.Fatalf("Validate() = %v, want DB_PASSWORD to be required", err)
+	}
+}
+
+func TestValidatePlaybackSigningKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		s3Secret  string
+		wantError string
+	}{
+		{"valid", strings.Repeat("k", 32), "secret", ""},
+		{"too short", strings.Repeat("k", 31), "secret", "PLAYBACK_SIGNING_KEY must be at least 32 characters long"},
+		{"reusing the S3 secret", strings.Repeat("k", 40), strings.Repeat("k", 40), "PLAYBACK_SIGNING_KEY must not be the S3_SECRET_ACCESS_KEY"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env := validEnv()
+			env.PlaybackSigningKey = test.key
+			env.S3SecretAccessKey = test.s3Secret
+			err := env.Validate(ServicePlayback)
+			if test.wantError == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantError) {
+				t.Fatalf("Validate() = %v, want %q", err, test.wantError)
+			}
+		})
+	}
+}
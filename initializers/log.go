@@ -3,15 +3,34 @@ package initializers
 import (
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 )
 
-func InitLog(logfile string) *os.File {
-	logFile, err := os.OpenFile(logfile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+// InitLog configures the default structured logger to write to the console writer and to the log file,
+// using the level and format (json or text) defined in the environment
+func InitLog(env Env, console io.Writer) *os.File {
+	logFile, err := os.OpenFile(env.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		log.Fatal(err)
 	}
-	w := io.MultiWriter(os.Stdout, logFile)
-	log.SetOutput(w)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(env.LogLevel)); err != nil {
+		log.Fatal(err)
+	}
+	w := io.MultiWriter(console, logFile)
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(env.LogFormat) {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		log.Fatalf("unknown log format %q, expected json or text", env.LogFormat)
+	}
+	slog.SetDefault(slog.New(handler))
 	return logFile
 }
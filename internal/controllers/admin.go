@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+func InitAdminController(engine *gin.RouterGroup, reconciliation *features.StorageReconciliation, retention *features.Retention, duplicates *features.Duplicates) {
+	engine.Use(adminMiddleware())
+	engine.POST("storage/reconcile", func(c *gin.Context) {
+		reconcileStorage(c, reconciliation.WithContext(c.Request.Context()))
+	})
+	engine.GET("retention", func(c *gin.Context) {
+		getRetentionReport(c, retention.WithContext(c.Request.Context()))
+	})
+	engine.GET("retention/last", func(c *gin.Context) {
+		getLastRetentionReport(c, retention.WithContext(c.Request.Context()))
+	})
+	engine.POST("retention/run", func(c *gin.Context) {
+		runRetention(c, retention.WithContext(c.Request.Context()))
+	})
+	engine.GET("duplicates", func(c *gin.Context) {
+		getDuplicates(c, duplicates.WithContext(c.Request.Context()))
+	})
+	engine.GET("duplicates/last", func(c *gin.Context) {
+		getLastDuplicates(c, duplicates.WithContext(c.Request.Context()))
+	})
+	engine.POST("duplicates/resolve", func(c *gin.Context) {
+		resolveDuplicate(c, duplicates.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary Reconcile the storage
+// @Description Compare the media files, audios and subtitles of the database with the objects of the bucket, reporting
+// @Description the medias missing their directory or some of their files, and the directories and objects of no media.
+// @Description With repair, the orphaned objects are deleted from the bucket and the rows of the missing files from
+// @Description the database. The files pending deletion are skipped. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Param repair query bool false "Fix the differences"
+// @Produce json
+// @Success 200 {object} reconciliationResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/storage/reconcile [post]
+func reconcileStorage(c *gin.Context, reconciliation *features.StorageReconciliation) {
+	repair, err := strconv.ParseBool(c.DefaultQuery("repair", "false"))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: "repair must be a boolean",
+		})
+		return
+	}
+	report, err := reconciliation.Reconcile(repair)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toReconciliationResponse(report))
+}
+
+// @Summary Get the retention report
+// @Description Evaluate the retention policy now, listing the files it selects for deletion with the rules selecting
+// @Description them, without deleting anything. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Produce json
+// @Success 200 {object} retentionReportResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/retention [get]
+func getRetentionReport(c *gin.Context, retention *features.Retention) {
+	report, err := retention.Evaluate()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toRetentionReportResponse(report))
+}
+
+// @Summary Get the last retention run
+// @Description Get the report of the last application of the retention policy, scheduled or not. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Produce json
+// @Success 200 {object} retentionReportResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /admin/retention/last [get]
+func getLastRetentionReport(c *gin.Context, retention *features.Retention) {
+	report := retention.LastReport()
+	if report == nil {
+		c.JSON(404, errorResponse{
+			Error: "the retention policy was not applied yet",
+		})
+		return
+	}
+	c.JSON(200, toRetentionReportResponse(report))
+}
+
+// @Summary Apply the retention policy
+// @Description Apply the retention policy now: the selected files are deleted through deletion jobs when the policy
+// @Description deletes them, otherwise they are only reported. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Produce json
+// @Success 200 {object} retentionReportResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/retention/run [post]
+func runRetention(c *gin.Context, retention *features.Retention) {
+	report, err := retention.Run()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toRetentionReportResponse(report))
+}
+
+// @Summary Detect the duplicated files
+// @Description Group the duplicated media files by the movie or episode they are copies of: the file of the media with
+// @Description the files no media uses having the same filename, size and duration. The copies matching the file of no
+// @Description media, or the files of several media, are grouped without media. The files pending deletion are skipped.
+// @Description Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Produce json
+// @Success 200 {object} duplicateReportResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/duplicates [get]
+func getDuplicates(c *gin.Context, duplicates *features.Duplicates) {
+	report, err := duplicates.Detect()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toDuplicateReportResponse(report))
+}
+
+// @Summary Get the last duplicate detection
+// @Description Get the report of the last detection of the duplicated files, scheduled or not. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Produce json
+// @Success 200 {object} duplicateReportResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /admin/duplicates/last [get]
+func getLastDuplicates(c *gin.Context, duplicates *features.Duplicates) {
+	report := duplicates.LastReport()
+	if report == nil {
+		c.JSON(404, errorResponse{
+			Error: "the duplicates were not detected yet",
+		})
+		return
+	}
+	c.JSON(200, toDuplicateReportResponse(report))
+}
+
+// @Summary Resolve a duplicated file
+// @Description Keep a copy of a duplicated file and delete the others through deletion jobs. The movie or episode
+// @Description using another copy is made to use the kept one, nothing being deleted if it cannot be. The copies whose
+// @Description deletion could not start are listed among the errors, the other deletions going on. Reserved to the admins.
+// @Tags Admin
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Accept json
+// @Param keep body duplicateResolveRequest true "File to keep"
+// @Produce json
+// @Success 200 {object} duplicateResolutionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/duplicates/resolve [post]
+func resolveDuplicate(c *gin.Context, duplicates *features.Duplicates) {
+	var request duplicateResolveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := uuid.Parse(request.Keep); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "keep must be a file ID",
+		})
+		return
+	}
+	resolution, err := duplicates.Resolve(request.Keep)
+	if errors.Is(err, features.ErrDuplicateNotFound) || errors.Is(err, features.ErrMediaNotFound) {
+		c.JSON(404, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toDuplicateResolutionResponse(resolution))
+}
@@ -8,25 +8,25 @@ import (
 
 func InitMediaAssetsController(engine *gin.RouterGroup, mediaAssets *features.MediaAssetsData) {
 	engine.GET("/movie-genre/:id", func(c *gin.Context) {
-		getMovieGenre(c, mediaAssets)
+		getMovieGenre(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movie-genres", func(c *gin.Context) {
-		getMovieGenres(c, mediaAssets)
+		getMovieGenres(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tv-genre/:id", func(c *gin.Context) {
-		getTVGenre(c, mediaAssets)
+		getTVGenre(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tv-genres", func(c *gin.Context) {
-		getTVGenres(c, mediaAssets)
+		getTVGenres(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/studio/:id", func(c *gin.Context) {
-		getStudio(c, mediaAssets)
+		getStudio(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/network/:id", func(c *gin.Context) {
-		getNetwork(c, mediaAssets)
+		getNetwork(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 	engine.GET("/actor/:id", func(c *gin.Context) {
-		getActor(c, mediaAssets)
+		getActor(c, mediaAssets.WithContext(c.Request.Context()))
 	})
 }
 
@@ -12,16 +12,16 @@ import (
 
 func InitCalendarController(engine *gin.RouterGroup, calendarService *features.CalendarService) {
 	engine.GET("/movies", func(c *gin.Context) {
-		getMoviesCalendar(c, calendarService)
+		getMoviesCalendar(c, calendarService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshows", func(c *gin.Context) {
-		getTvShowsCalendar(c, calendarService)
+		getTvShowsCalendar(c, calendarService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movies/ical/:user-id", func(c *gin.Context) {
-		getMoviesCalendarIcal(c, calendarService)
+		getMoviesCalendarIcal(c, calendarService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshows/ical/:user-id", func(c *gin.Context) {
-		getTvShowsCalendarIcal(c, calendarService)
+		getTvShowsCalendarIcal(c, calendarService.WithContext(c.Request.Context()))
 	})
 }
 
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/bingemate/media-service/internal/features"
 	"github.com/gin-gonic/gin"
 	"strconv"
@@ -10,53 +11,55 @@ import (
 
 func InitCommentController(engine *gin.RouterGroup, commentService *features.CommentService) {
 	engine.GET("movie/:mediaID", func(c *gin.Context) {
-		getMovieComments(c, commentService)
+		getMovieComments(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/user/:userID", func(c *gin.Context) {
-		getUserMovieComments(c, commentService)
+		getUserMovieComments(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.POST("movie/:mediaID", func(c *gin.Context) {
-		addMovieComment(c, commentService)
+		addMovieComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.DELETE("movie/:commentID", func(c *gin.Context) {
-		deleteMovieComment(c, commentService)
+		deleteMovieComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.PUT("movie/:commentID", func(c *gin.Context) {
-		updateMovieComment(c, commentService)
+		updateMovieComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/:mediaID", func(c *gin.Context) {
-		getTVShowComments(c, commentService)
+		getTVShowComments(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/user/:userID", func(c *gin.Context) {
-		getUserTVShowComments(c, commentService)
+		getUserTVShowComments(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.POST("tv/:mediaID", func(c *gin.Context) {
-		addTVShowComment(c, commentService)
+		addTVShowComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.DELETE("tv/:commentID", func(c *gin.Context) {
-		deleteTVShowComment(c, commentService)
+		deleteTVShowComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.PUT("tv/:commentID", func(c *gin.Context) {
-		updateTVShowComment(c, commentService)
+		updateTVShowComment(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("user/history/:userID", func(c *gin.Context) {
-		getUserCommentHistory(c, commentService)
+		getUserCommentHistory(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("user/count/:userID", func(c *gin.Context) {
-		getUserCommentCount(c, commentService)
+		getUserCommentCount(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/history", func(c *gin.Context) {
-		getCommentHistory(c, commentService)
+		getCommentHistory(c, commentService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/count", func(c *gin.Context) {
-		getCommentCount(c, commentService)
+		getCommentCount(c, commentService.WithContext(c.Request.Context()))
 	})
 }
 
 // @Summary Get movie's comments
 // @Description Get movie's comments
 // @Tags Comment
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 5 by default and at most 100"
 // @Param mediaID path int true "Movie ID"
 // @Produce json
 // @Success 200 {object} commentResults
@@ -64,9 +67,10 @@ func InitCommentController(engine *gin.RouterGroup, commentService *features.Com
 // @Failure 500 {object} errorResponse
 // @Router /comment/movie/{mediaID} [get]
 func getMovieComments(c *gin.Context, commentService *features.CommentService) {
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 	mediaID, err := strconv.Atoi(c.Param("mediaID"))
 	if err != nil {
@@ -77,21 +81,25 @@ func getMovieComments(c *gin.Context, commentService *features.CommentService) {
 		c.JSON(400, errorResponse{Error: "mediaID must be a positive number"})
 		return
 	}
-	comments, total, err := commentService.GetMovieComments(mediaID, page)
+	comments, err := commentService.GetMovieComments(mediaID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, commentResults{
-		Results:     toMovieCommentsResponse(comments),
-		TotalResult: total,
+		Results:     toMovieCommentsResponse(comments.Items),
+		TotalResult: comments.Total,
+		Next:        cursorToken(comments.Next),
+		HasNext:     comments.Next != nil,
 	})
 }
 
 // @Summary Get tv show's comments
 // @Description Get tv show's comments
 // @Tags Comment
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 5 by default and at most 100"
 // @Param mediaID path int true "TV Show ID"
 // @Produce json
 // @Success 200 {object} commentResults
@@ -99,9 +107,10 @@ func getMovieComments(c *gin.Context, commentService *features.CommentService) {
 // @Failure 500 {object} errorResponse
 // @Router /comment/tv/{mediaID} [get]
 func getTVShowComments(c *gin.Context, commentService *features.CommentService) {
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 	mediaID, err := strconv.Atoi(c.Param("mediaID"))
 	if err != nil {
@@ -112,21 +121,25 @@ func getTVShowComments(c *gin.Context, commentService *features.CommentService)
 		c.JSON(400, errorResponse{Error: "mediaID must be a positive number"})
 		return
 	}
-	comments, total, err := commentService.GetTvShowComments(mediaID, page)
+	comments, err := commentService.GetTvShowComments(mediaID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, commentResults{
-		Results:     toTVShowCommentsResponse(comments),
-		TotalResult: total,
+		Results:     toTVShowCommentsResponse(comments.Items),
+		TotalResult: comments.Total,
+		Next:        cursorToken(comments.Next),
+		HasNext:     comments.Next != nil,
 	})
 }
 
 // @Summary Get user's movie comments
 // @Description Get user's movie comments
 // @Tags Comment
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 5 by default and at most 100"
 // @Param userID path string true "User ID"
 // @Produce json
 // @Success 200 {object} commentResults
@@ -134,30 +147,35 @@ func getTVShowComments(c *gin.Context, commentService *features.CommentService)
 // @Failure 500 {object} errorResponse
 // @Router /comment/movie/user/{userID} [get]
 func getUserMovieComments(c *gin.Context, commentService *features.CommentService) {
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 	userID := c.Param("userID")
 	if userID == "" {
 		c.JSON(400, errorResponse{Error: "userID must be a string"})
 		return
 	}
-	comments, total, err := commentService.GetMovieUserComments(userID, page)
+	comments, err := commentService.GetMovieUserComments(userID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, commentResults{
-		Results:     toMovieCommentsResponse(comments),
-		TotalResult: total,
+		Results:     toMovieCommentsResponse(comments.Items),
+		TotalResult: comments.Total,
+		Next:        cursorToken(comments.Next),
+		HasNext:     comments.Next != nil,
 	})
 }
 
 // @Summary Get user's tv show comments
 // @Description Get user's tv show comments
 // @Tags Comment
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 5 by default and at most 100"
 // @Param userID path string true "User ID"
 // @Produce json
 // @Success 200 {object} commentResults
@@ -165,23 +183,26 @@ func getUserMovieComments(c *gin.Context, commentService *features.CommentServic
 // @Failure 500 {object} errorResponse
 // @Router /comment/tv/user/{userID} [get]
 func getUserTVShowComments(c *gin.Context, commentService *features.CommentService) {
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 	userID := c.Param("userID")
 	if userID == "" {
 		c.JSON(400, errorResponse{Error: "userID must be a string"})
 		return
 	}
-	comments, total, err := commentService.GetTvShowUserComments(userID, page)
+	comments, err := commentService.GetTvShowUserComments(userID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, commentResults{
-		Results:     toTVShowCommentsResponse(comments),
-		TotalResult: total,
+		Results:     toTVShowCommentsResponse(comments.Items),
+		TotalResult: comments.Total,
+		Next:        cursorToken(comments.Next),
+		HasNext:     comments.Next != nil,
 	})
 }
 
@@ -442,11 +463,16 @@ func updateTVShowComment(c *gin.Context, commentService *features.CommentService
 }
 
 // @Summary Get User's comments history
-// @Description Get User's comments history
+// @Description Get the number of comments of a user per day, from start to end, the oldest day first.
+// @Description The days without comments are omitted. With a cursor, page or limit, a commentHistoryResults page
+// @Description of the days is returned instead, the most recent day first.
 // @Tags Comment
 // @Param userID path string true "User ID"
-// @Param start query string false "Start date (YYYY-MM-DD)"
-// @Param end query string false "End date (YYYY-MM-DD)"
+// @Param start query string false "Start date (YYYY-MM-DD), the first day of the current month by default"
+// @Param end query string false "End date (YYYY-MM-DD), the last day of the current month by default"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 31 by default and at most 100"
 // @Produce json
 // @Success 200 {array} commentHistoryReponse
 // @Failure 400 {object} errorResponse
@@ -458,41 +484,7 @@ func getUserCommentHistory(c *gin.Context, commentService *features.CommentServi
 		c.JSON(400, errorResponse{Error: "userID is required"})
 		return
 	}
-
-	start := c.Query("start")
-	end := c.Query("end")
-
-	if start == "" {
-		now := time.Now()
-		currentYear := now.Year()
-		currentMonth := now.Month()
-		currentLocation := now.Location()
-
-		firstDayOfCurrentMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation)
-		start = firstDayOfCurrentMonth.Format("2006-01-02")
-	}
-	if end == "" {
-		now := time.Now()
-		currentYear := now.Year()
-		currentMonth := now.Month()
-		currentLocation := now.Location()
-
-		firstDayOfCurrentMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation)
-		lastDayOfCurrentMonth := firstDayOfCurrentMonth.AddDate(0, 1, -1)
-		end = lastDayOfCurrentMonth.Format("2006-01-02")
-	}
-
-	movieCommentsHistory, err := commentService.GetUserMovieCommentsByRange(userID, start, end)
-	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
-		return
-	}
-	tvShowCommentsHistory, err := commentService.GetUserTvShowCommentsByRange(userID, start, end)
-	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
-		return
-	}
-	c.JSON(200, toCommentHistories(movieCommentsHistory, tvShowCommentsHistory))
+	commentHistory(c, commentService, userID)
 }
 
 // @Summary Get User's comments count
@@ -520,50 +512,66 @@ func getUserCommentCount(c *gin.Context, commentService *features.CommentService
 }
 
 // @Summary Get comments history
-// @Description Get comments history
+// @Description Get the number of comments per day, from start to end, the oldest day first.
+// @Description The days without comments are omitted. With a cursor, page or limit, a commentHistoryResults page
+// @Description of the days is returned instead, the most recent day first.
 // @Tags Comment
-// @Param start query string false "Start date (YYYY-MM-DD)"
-// @Param end query string false "End date (YYYY-MM-DD)"
+// @Param start query string false "Start date (YYYY-MM-DD), the first day of the current month by default"
+// @Param end query string false "End date (YYYY-MM-DD), the last day of the current month by default"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 31 by default and at most 100"
 // @Produce json
 // @Success 200 {array} commentHistoryReponse
 // @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /comment/history [get]
 func getCommentHistory(c *gin.Context, commentService *features.CommentService) {
-	start := c.Query("start")
-	end := c.Query("end")
-
-	if start == "" {
-		now := time.Now()
-		currentYear := now.Year()
-		currentMonth := now.Month()
-		currentLocation := now.Location()
-
-		firstDayOfCurrentMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation)
-		start = firstDayOfCurrentMonth.Format("2006-01-02")
-	}
-	if end == "" {
-		now := time.Now()
-		currentYear := now.Year()
-		currentMonth := now.Month()
-		currentLocation := now.Location()
+	commentHistory(c, commentService, "")
+}
 
-		firstDayOfCurrentMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation)
-		lastDayOfCurrentMonth := firstDayOfCurrentMonth.AddDate(0, 1, -1)
-		end = lastDayOfCurrentMonth.Format("2006-01-02")
+// commentHistory responds with the comments per day of a user, or of every user if userID is empty, over the start
+// and end query parameters, the current month by default. The days are paginated when the cursor, page or limit query
+// parameter is set, and all returned otherwise.
+func commentHistory(c *gin.Context, commentService *features.CommentService, userID string) {
+	page, err := queryPage(c)
+	if err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
+	now := time.Now()
+	firstDayOfCurrentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	start := c.DefaultQuery("start", firstDayOfCurrentMonth.Format("2006-01-02"))
+	end := c.DefaultQuery("end", firstDayOfCurrentMonth.AddDate(0, 1, -1).Format("2006-01-02"))
 
-	movieCommentsHistory, err := commentService.GetMovieCommentsByRange(start, end)
-	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+	if c.Query("cursor") == "" && c.Query("page") == "" && c.Query("limit") == "" {
+		days, err := commentService.GetCommentDays(userID, start, end)
+		if errors.Is(err, features.ErrInvalidDate) {
+			c.JSON(400, errorResponse{Error: err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(500, errorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(200, toCommentHistories(days))
+		return
+	}
+	days, err := commentService.GetCommentHistory(userID, start, end, page)
+	if errors.Is(err, features.ErrInvalidDate) {
+		c.JSON(400, errorResponse{Error: err.Error()})
 		return
 	}
-	tvShowCommentsHistory, err := commentService.GetTvShowCommentsByRange(start, end)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
-	c.JSON(200, toCommentHistories(movieCommentsHistory, tvShowCommentsHistory))
+	c.JSON(200, commentHistoryResults{
+		Results:     toCommentHistories(days.Items),
+		TotalResult: days.Total,
+		Next:        cursorToken(days.Next),
+		HasNext:     days.Next != nil,
+	})
 }
 
 // @Summary Get comments count
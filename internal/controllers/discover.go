@@ -2,75 +2,335 @@ package controllers
 
 import (
 	"github.com/bingemate/media-service/internal/features"
+	"github.com/bingemate/media-service/internal/repository"
 	"github.com/gin-gonic/gin"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func InitDiscoverController(engine *gin.RouterGroup, mediaDiscover *features.MediaDiscovery) {
+	engine.GET("search", func(c *gin.Context) {
+		searchAll(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+	engine.GET("suggest", func(c *gin.Context) {
+		suggest(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+	engine.GET("library", func(c *gin.Context) {
+		filterLibrary(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
 	engine.GET("movie/search", func(c *gin.Context) {
-		searchMovie(c, mediaDiscover)
+		searchMovie(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/search", func(c *gin.Context) {
-		searchTv(c, mediaDiscover)
+		searchTv(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("actor/search", func(c *gin.Context) {
-		searchActor(c, mediaDiscover)
+		searchActor(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/popular", func(c *gin.Context) {
-		getPopularMovies(c, mediaDiscover)
+		getPopularMovies(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/popular", func(c *gin.Context) {
-		getPopularTvShows(c, mediaDiscover)
+		getPopularTvShows(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+	engine.GET("movie/trending", func(c *gin.Context) {
+		getTrendingMovies(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+	engine.GET("tv/trending", func(c *gin.Context) {
+		getTrendingTvShows(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/recent", func(c *gin.Context) {
-		getRecentMovies(c, mediaDiscover)
+		getRecentMovies(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/recent", func(c *gin.Context) {
-		getRecentTvShows(c, mediaDiscover)
+		getRecentTvShows(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/genre", func(c *gin.Context) {
-		getMoviesByGenre(c, mediaDiscover)
+		getMoviesByGenre(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/genre", func(c *gin.Context) {
-		getTvShowsByGenre(c, mediaDiscover)
+		getTvShowsByGenre(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/actor", func(c *gin.Context) {
-		getMoviesByActor(c, mediaDiscover)
+		getMoviesByActor(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/actor", func(c *gin.Context) {
-		getTvShowsByActor(c, mediaDiscover)
+		getTvShowsByActor(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/director", func(c *gin.Context) {
-		getMoviesByDirector(c, mediaDiscover)
+		getMoviesByDirector(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/studio", func(c *gin.Context) {
-		getMoviesByStudio(c, mediaDiscover)
+		getMoviesByStudio(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/network", func(c *gin.Context) {
-		getTvShowsByNetwork(c, mediaDiscover)
+		getTvShowsByNetwork(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/recommendations/:movie", func(c *gin.Context) {
-		getMovieRecommendations(c, mediaDiscover)
+		getMovieRecommendations(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/recommendations/:tv", func(c *gin.Context) {
-		getTvShowRecommendations(c, mediaDiscover)
+		getTvShowRecommendations(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+	engine.GET("recommendations", func(c *gin.Context) {
+		getUserRecommendations(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/comments", func(c *gin.Context) {
-		getMoviesByComments(c, mediaDiscover)
+		getMoviesByComments(c, mediaDiscover.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/comments", func(c *gin.Context) {
-		getTvShowsByComments(c, mediaDiscover)
+		getTvShowsByComments(c, mediaDiscover.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary		Search movies, tv shows and people
+// @Description	Search movies, tv shows and people by query, merged in a single list ordered by relevance.
+// @Description	With available set, the movies, tv shows and episodes of the library are ranked together, a page being
+// @Description	a slice of this single list, and people, never part of the library, are not searched.
+// @Description	Otherwise TMDB is searched and pages are per type: a page holds the same page of each searched type,
+// @Description	totalPage being the largest page count and totalResult the sum of the results of the types, and episodes
+// @Description	are not searched. totals gives the page count and results of each type.
+// @Tags			Discover
+// @Param			query query string true "Search query"
+// @Param			page query int false "Page number, from 1"
+// @Param			type query string false "Comma separated types to search: movie, tv, episode, person (default all)"
+// @Param           available query bool false "Only available movies, tv shows and episodes"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
+// @Param 			adult query bool false "Include adult results"
+// @Produce		json
+// @Success		200	{object} searchResults
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/search [get]
+func searchAll(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	query := strings.TrimSpace(c.Query("query"))
+	page, err := optionalInt(c, "page")
+	if c.Query("page") == "" {
+		page = 1
+	}
+	if err != nil || page < 1 {
+		c.JSON(400, errorResponse{
+			Error: "page must be a number from 1",
+		})
+		return
+	}
+	available, err := strconv.ParseBool(c.Query("available"))
+	if err != nil {
+		available = false
+	}
+	adult, err := strconv.ParseBool(c.Query("adult"))
+	if err != nil {
+		adult = false
+	}
+	if query == "" {
+		c.JSON(400, errorResponse{
+			Error: "query is required",
+		})
+		return
+	}
+	var types []features.SearchResultType
+	for _, value := range strings.Split(c.Query("type"), ",") {
+		switch resultType := features.SearchResultType(strings.TrimSpace(value)); resultType {
+		case "":
+		case features.SearchResultMovie, features.SearchResultTvShow, features.SearchResultEpisode, features.SearchResultPerson:
+			types = append(types, resultType)
+		default:
+			c.JSON(400, errorResponse{
+				Error: "invalid type " + string(resultType) + ", expected movie, tv, episode or person",
+			})
+			return
+		}
+	}
+	result, err := mediaDiscover.Search(query, page, adult, available, queryLanguages(c), types)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, searchResults{
+		TotalPage:   result.TotalPage,
+		TotalResult: result.TotalResult,
+		Totals:      toSearchTotalsResponse(result.Totals),
+		Results:     toSearchResultsResponse(result.Results),
 	})
 }
 
+// @Summary		Suggest titles
+// @Description	Type-ahead suggestions: available movies, tv shows and episodes of the library having a word starting with q,
+// @Description	ignoring case and accents. With tmdb set, TMDB movies and tv shows complete the list up to the limit.
+// @Tags			Discover
+// @Param			q query string true "Beginning of the title"
+// @Param			limit query int false "Maximum number of suggestions (default 10, at most 50)"
+// @Param			tmdb query bool false "Complete with TMDB titles"
+// @Produce		json
+// @Success		200	{array} suggestionResponse
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/suggest [get]
+func suggest(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	query := strings.TrimSpace(c.Query("q"))
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	limit = min(limit, 50)
+	withTMDB, err := strconv.ParseBool(c.Query("tmdb"))
+	if err != nil {
+		withTMDB = false
+	}
+	if query == "" {
+		c.JSON(400, errorResponse{
+			Error: "q is required",
+		})
+		return
+	}
+	suggestions, err := mediaDiscover.Suggest(query, limit, withTMDB)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toSuggestionsResponse(suggestions))
+}
+
+// @Summary		Filter the library
+// @Description	Available movies or tv shows matching all the given filters, the values of a list being alternatives.
+// @Description	For tv shows, an episode must match the duration and language filters.
+// @Description	The facets count the results for every value of each filter, ignoring the filter itself.
+// @Tags			Discover
+// @Param			type query string false "Media type: movie (default) or tv"
+// @Param			genre query string false "Comma separated genre names"
+// @Param			yearMin query int false "Minimum release year"
+// @Param			yearMax query int false "Maximum release year"
+// @Param			durationMin query int false "Minimum duration in minutes"
+// @Param			durationMax query int false "Maximum duration in minutes"
+// @Param			audio query string false "Comma separated audio languages"
+// @Param			subtitle query string false "Comma separated subtitle languages"
+// @Param			ratingMin query number false "Minimum average rating"
+// @Param			ratingMax query number false "Maximum average rating"
+// @Param			sort query string false "Sort: name (default), rating, release or added"
+// @Param			order query string false "asc or desc, default asc for name and desc otherwise"
+// @Param			page query int false "Page number"
+// @Produce		json
+// @Success		200	{object} libraryResults
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/library [get]
+func filterLibrary(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	filter := repository.LibraryFilter{
+		Genres:            queryList(c, "genre"),
+		AudioLanguages:    queryList(c, "audio"),
+		SubtitleLanguages: queryList(c, "subtitle"),
+	}
+	var durationMin, durationMax int
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"yearMin", &filter.MinYear},
+		{"yearMax", &filter.MaxYear},
+		{"durationMin", &durationMin},
+		{"durationMax", &durationMax},
+	} {
+		if *param.value, err = optionalInt(c, param.name); err != nil {
+			c.JSON(400, errorResponse{
+				Error: "invalid " + param.name + ": " + err.Error(),
+			})
+			return
+		}
+	}
+	filter.MinDuration = time.Duration(durationMin) * time.Minute
+	filter.MaxDuration = time.Duration(durationMax) * time.Minute
+	if filter.MinRating, err = optionalFloat(c, "ratingMin"); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "invalid ratingMin: " + err.Error(),
+		})
+		return
+	}
+	if filter.MaxRating, err = optionalFloat(c, "ratingMax"); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "invalid ratingMax: " + err.Error(),
+		})
+		return
+	}
+
+	sort := repository.LibrarySort(c.DefaultQuery("sort", string(repository.LibrarySortName)))
+	switch sort {
+	case repository.LibrarySortName, repository.LibrarySortRating, repository.LibrarySortRelease, repository.LibrarySortAdded:
+	default:
+		c.JSON(400, errorResponse{
+			Error: "invalid sort " + string(sort) + ", expected name, rating, release or added",
+		})
+		return
+	}
+	var descending bool
+	switch c.Query("order") {
+	case "":
+		descending = sort != repository.LibrarySortName
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	default:
+		c.JSON(400, errorResponse{
+			Error: "invalid order " + c.Query("order") + ", expected asc or desc",
+		})
+		return
+	}
+
+	switch c.DefaultQuery("type", "movie") {
+	case "movie":
+		result, presence, facets, err := mediaDiscover.FilterLibraryMovies(filter, sort, descending, page)
+		if err != nil {
+			c.JSON(500, errorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(200, libraryResults{
+			Movies:      toMoviesResponse(result.Results, presence),
+			TotalPage:   result.TotalPage,
+			TotalResult: result.TotalResult,
+			Facets:      toLibraryFacetsResponse(facets),
+		})
+	case "tv":
+		result, presence, facets, err := mediaDiscover.FilterLibraryShows(filter, sort, descending, page)
+		if err != nil {
+			c.JSON(500, errorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(200, libraryResults{
+			TvShows:     toTVShowsResponse(result.Results, presence),
+			TotalPage:   result.TotalPage,
+			TotalResult: result.TotalResult,
+			Facets:      toLibraryFacetsResponse(facets),
+		})
+	default:
+		c.JSON(400, errorResponse{
+			Error: "invalid type " + c.Query("type") + ", expected movie or tv",
+		})
+	}
+}
+
 // @Summary		Search movies
 // @Description	Search movies by query
 // @Tags			Discover
 // @Tags			Movie
 // @Param			query query string true "Search query"
 // @Param			page query int false "Page number"
-// @Param           available query bool false "Only available movies"
+// @Param           available query bool false "Only available movies, ordered by relevance"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Param 			adult query bool false "Include adult movies"
 // @Produce		json
 // @Success		200	{object} movieResults
@@ -97,7 +357,7 @@ func searchMovie(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 		})
 		return
 	}
-	result, presence, err := mediaDiscover.SearchMovie(query, page, adult, available)
+	result, presence, err := mediaDiscover.SearchMovie(query, page, adult, available, queryLanguages(c))
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -117,7 +377,9 @@ func searchMovie(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 // @Tags			TvShow
 // @Param			query query string true "Search query"
 // @Param			page query int false "Page number"
-// @Param           available query bool false "Only available tv shows"
+// @Param           available query bool false "Only available tv shows, ordered by relevance"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Param 			adult query bool false "Include adult tv shows"
 // @Produce		json
 // @Success		200	{object} tvShowResults
@@ -144,7 +406,7 @@ func searchTv(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 		})
 		return
 	}
-	result, presence, err := mediaDiscover.SearchShow(query, page, adult, available)
+	result, presence, err := mediaDiscover.SearchShow(query, page, adult, available, queryLanguages(c))
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -206,6 +468,8 @@ func searchActor(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 // @Tags			Movie
 // @Param			page query int false "Page number"
 // @Param           available query bool false "Only available movies"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Produce		json
 // @Success		200	{object} movieResults
 // @Failure		500	{object} errorResponse
@@ -219,7 +483,7 @@ func getPopularMovies(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 	if err != nil {
 		available = false
 	}
-	result, presence, err := mediaDiscover.GetPopularMovies(page, available)
+	result, presence, err := mediaDiscover.GetPopularMovies(page, available, queryLanguages(c))
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -239,6 +503,8 @@ func getPopularMovies(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 // @Tags			TvShow
 // @Param			page query int false "Page number"
 // @Param           available query bool false "Only available tv shows"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Produce		json
 // @Success		200	{object} tvShowResults
 // @Failure		500	{object} errorResponse
@@ -252,7 +518,83 @@ func getPopularTvShows(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 	if err != nil {
 		available = false
 	}
-	result, presence, err := mediaDiscover.GetPopularShows(page, available)
+	result, presence, err := mediaDiscover.GetPopularShows(page, available, queryLanguages(c))
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, tvShowResults{
+		TotalPage:   result.TotalPage,
+		TotalResult: result.TotalResult,
+		Results:     toTVShowsResponse(result.Results, presence),
+	})
+}
+
+// @Summary		Get trending movies
+// @Description	Get the available movies ranked by their recent comments, ratings and viewers, the most recent activity
+// @Description	weighing the most. The ranking is refreshed every 15 minutes.
+// @Tags			Discover
+// @Tags			Movie
+// @Param			window query string false "Activity window: day, week (default) or month"
+// @Param			page query int false "Page number"
+// @Produce		json
+// @Success		200	{object} movieResults
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/movie/trending [get]
+func getTrendingMovies(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	window, err := features.ParseTrendingWindow(c.DefaultQuery("window", string(features.TrendingWeek)))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	result, presence, err := mediaDiscover.GetTrendingMovies(window, page)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, movieResults{
+		TotalPage:   result.TotalPage,
+		TotalResult: result.TotalResult,
+		Results:     toMoviesResponse(result.Results, presence),
+	})
+}
+
+// @Summary		Get trending tv shows
+// @Description	Get the available tv shows ranked by their recent comments, ratings and viewers, the most recent activity
+// @Description	weighing the most. The ranking is refreshed every 15 minutes.
+// @Tags			Discover
+// @Tags			TvShow
+// @Param			window query string false "Activity window: day, week (default) or month"
+// @Param			page query int false "Page number"
+// @Produce		json
+// @Success		200	{object} tvShowResults
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/tv/trending [get]
+func getTrendingTvShows(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	window, err := features.ParseTrendingWindow(c.DefaultQuery("window", string(features.TrendingWeek)))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	result, presence, err := mediaDiscover.GetTrendingTvShows(window, page)
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -271,6 +613,8 @@ func getPopularTvShows(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 // @Tags			Discover
 // @Tags			Movie
 // @Param           available query bool false "Only available movies"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Produce		json
 // @Success		200	{array} movieResponse
 // @Failure		500	{object} errorResponse
@@ -280,7 +624,7 @@ func getRecentMovies(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 	if err != nil {
 		available = false
 	}
-	result, presence, err := mediaDiscover.GetRecentMovies(available)
+	result, presence, err := mediaDiscover.GetRecentMovies(available, queryLanguages(c))
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -295,6 +639,8 @@ func getRecentMovies(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 // @Tags			Discover
 // @Tags			TvShow
 // @Param           available query bool false "Only available tv shows"
+// @Param			audio query string false "Comma separated audio languages, one of them is required (available only)"
+// @Param			subtitle query string false "Comma separated subtitle languages, one of them is required (available only)"
 // @Produce		json
 // @Success		200	{array} tvShowResponse
 // @Failure		500	{object} errorResponse
@@ -304,7 +650,7 @@ func getRecentTvShows(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
 	if err != nil {
 		available = false
 	}
-	result, presence, err := mediaDiscover.GetRecentShows(available)
+	result, presence, err := mediaDiscover.GetRecentShows(available, queryLanguages(c))
 	if err != nil {
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
@@ -573,6 +919,56 @@ func getTvShowsByNetwork(c *gin.Context, mediaDiscover *features.MediaDiscovery)
 	})
 }
 
+// @Summary		Get user's recommendations
+// @Description	Recommend available movies and tv shows the user did not rate yet, from the ratings of the user and of
+// @Description	the other users: titles rated alike by the same users as the ones the user liked come first. Each one
+// @Description	lists the liked titles it is the most similar to. The list is empty when the user rated nothing.
+// @Tags			Discover
+// @Param			user-id header string true "User ID"
+// @Param			type query string false "Comma separated types to recommend: movie, tv (default all)"
+// @Param			limit query int false "Number of recommendations, 20 by default and at most 100"
+// @Produce		json
+// @Success		200	{array} recommendationResponse
+// @Failure		400	{object} errorResponse
+// @Failure		500	{object} errorResponse
+// @Router			/discover/recommendations [get]
+func getUserRecommendations(c *gin.Context, mediaDiscover *features.MediaDiscovery) {
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{
+			Error: "user-id header is required",
+		})
+		return
+	}
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: "limit must be a number",
+		})
+		return
+	}
+	var types []repository.SavedMediaType
+	for _, value := range queryList(c, "type") {
+		switch mediaType := repository.SavedMediaType(value); mediaType {
+		case repository.SavedMovie, repository.SavedTvShow:
+			types = append(types, mediaType)
+		default:
+			c.JSON(400, errorResponse{
+				Error: "invalid type " + value + ", expected movie or tv",
+			})
+			return
+		}
+	}
+	result, err := mediaDiscover.GetRecommendations(userID, types, limit)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toRecommendationsResponse(result))
+}
+
 // @Summary		Get movie's recommendations
 // @Description	Get movie's recommendations
 // @Tags			Discover
@@ -4,51 +4,64 @@ import (
 	"errors"
 	"github.com/bingemate/media-service/internal/features"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"strconv"
 )
 
 func InitFileInfoController(engine *gin.RouterGroup, fileInfo *features.MediaFile) {
 	engine.GET("movie/:id", func(c *gin.Context) {
-		getMovieFileInfo(c, fileInfo)
+		getMovieFileInfo(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/search", func(c *gin.Context) {
-		searchMovies(c, fileInfo)
+		searchMovies(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("movie/count", func(c *gin.Context) {
-		countAvailableMovies(c, fileInfo)
+		countAvailableMovies(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movie/duration", func(c *gin.Context) {
-		countMoviesTotalDuration(c, fileInfo)
+		countMoviesTotalDuration(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("episode/:id", func(c *gin.Context) {
-		getEpisodeFileInfo(c, fileInfo)
+		getEpisodeFileInfo(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("episode/search", func(c *gin.Context) {
-		searchEpisodes(c, fileInfo)
+		searchEpisodes(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("episode/count", func(c *gin.Context) {
-		countAvailableEpisodes(c, fileInfo)
+		countAvailableEpisodes(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("episode/duration", func(c *gin.Context) {
-		countEpisodesTotalDuration(c, fileInfo)
+		countEpisodesTotalDuration(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/:id/available", func(c *gin.Context) {
-		getAvailableEpisodes(c, fileInfo)
+		getAvailableEpisodes(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("tv/count", func(c *gin.Context) {
-		countAvailableTvShows(c, fileInfo)
+		countAvailableTvShows(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.DELETE(":id", func(c *gin.Context) {
-		deleteFile(c, fileInfo)
+		deleteFile(c, fileInfo.WithContext(c.Request.Context()))
+	})
+	engine.GET("jobs/:id", func(c *gin.Context) {
+		getDeletionJob(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("size", func(c *gin.Context) {
-		getTotalSize(c, fileInfo)
+		getTotalSize(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("count", func(c *gin.Context) {
-		countFiles(c, fileInfo)
+		countFiles(c, fileInfo.WithContext(c.Request.Context()))
 	})
 	engine.GET("available", func(c *gin.Context) {
-		getAvailableSpace(c, fileInfo)
+		getAvailableSpace(c, fileInfo.WithContext(c.Request.Context()))
+	})
+	engine.GET("usage", func(c *gin.Context) {
+		getStorageUsage(c, fileInfo.WithContext(c.Request.Context()))
+	})
+	engine.GET("usage/folders", func(c *gin.Context) {
+		getFoldersUsage(c, fileInfo.WithContext(c.Request.Context()))
+	})
+	engine.GET("languages", func(c *gin.Context) {
+		getLanguages(c, fileInfo.WithContext(c.Request.Context()))
 	})
 }
 
@@ -155,11 +168,14 @@ func getAvailableEpisodes(c *gin.Context, mediaData *features.MediaFile) {
 }
 
 // @Summary Search tv show episodes files
-// @Description Search tv show episodes files
+// @Description Search tv show episodes files by episode or tv show name, ordered by relevance
 // @Tags File
-// @Param page query int false "Page number"
-// @Param limit query int false "Page limit"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 20 by default and at most 100"
 // @Param query query string true "Search query"
+// @Param audio query string false "Comma separated audio languages, one of them is required"
+// @Param subtitle query string false "Comma separated subtitle languages, one of them is required"
 // @Produce json
 // @Success 200 {object} episodeFilesResult
 // @Failure 400 {object} errorResponse
@@ -167,14 +183,7 @@ func getAvailableEpisodes(c *gin.Context, mediaData *features.MediaFile) {
 // @Failure 500 {object} errorResponse
 // @Router /file/episode/search [get]
 func searchEpisodes(c *gin.Context, mediaData *features.MediaFile) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil {
-		c.JSON(400, errorResponse{
-			Error: err.Error(),
-		})
-		return
-	}
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := queryPage(c)
 	if err != nil {
 		c.JSON(400, errorResponse{
 			Error: err.Error(),
@@ -182,26 +191,29 @@ func searchEpisodes(c *gin.Context, mediaData *features.MediaFile) {
 		return
 	}
 	query := c.Query("query")
-	result, total, err := mediaData.SearchEpisodeFiles(query, page, limit)
+	result, err := mediaData.SearchEpisodeFiles(query, queryLanguages(c), page)
 
 	if err != nil {
-		c.JSON(500, errorResponse{
-			Error: err.Error(),
-		})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, episodeFilesResult{
-		Results: toEpisodeFilesResponse(result),
-		Total:   total,
+		Results: toEpisodeFilesResponse(result.Items),
+		Total:   result.Total,
+		Next:    cursorToken(result.Next),
+		HasNext: result.Next != nil,
 	})
 }
 
 // @Summary Search movie files
-// @Description Search movie files
+// @Description Search movie files by name, ordered by relevance
 // @Tags File
-// @Param page query int false "Page number"
-// @Param limit query int false "Page limit"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 20 by default and at most 100"
 // @Param query query string true "Search query"
+// @Param audio query string false "Comma separated audio languages, one of them is required"
+// @Param subtitle query string false "Comma separated subtitle languages, one of them is required"
 // @Produce json
 // @Success 200 {object} movieFilesResult
 // @Failure 400 {object} errorResponse
@@ -209,61 +221,99 @@ func searchEpisodes(c *gin.Context, mediaData *features.MediaFile) {
 // @Failure 500 {object} errorResponse
 // @Router /file/movie/search [get]
 func searchMovies(c *gin.Context, mediaData *features.MediaFile) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	page, err := queryPage(c)
 	if err != nil {
 		c.JSON(400, errorResponse{
 			Error: err.Error(),
 		})
 		return
 	}
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	query := c.Query("query")
+	result, err := mediaData.SearchMovieFiles(query, queryLanguages(c), page)
+
 	if err != nil {
+		pageError(c, err)
+		return
+	}
+	c.JSON(200, movieFilesResult{
+		Results: toMovieFilesResponse(result.Items),
+		Total:   result.Total,
+		Next:    cursorToken(result.Next),
+		HasNext: result.Next != nil,
+	})
+}
+
+// @Summary Delete a file
+// @Description Mark a file pending deletion and delete it in the background: its objects are deleted from the storage,
+// @Description retrying on failure, then the file is removed. The returned job tells when it is done, see /file/jobs/{id}.
+// @Description The job already in progress is returned if the file is being deleted.
+// @Tags File
+// @Param id path string true "File ID"
+// @Produce json
+// @Success 202 {object} deletionJobResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id} [delete]
+func deleteFile(c *gin.Context, mediaData *features.MediaFile) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
 		c.JSON(400, errorResponse{
-			Error: err.Error(),
+			Error: "id must be a file ID",
 		})
 		return
 	}
-	query := c.Query("query")
-	result, total, err := mediaData.SearchMovieFiles(query, page, limit)
 
+	job, err := mediaData.DeleteMediaFile(id)
 	if err != nil {
+		if errors.Is(err, features.ErrMediaNotFound) {
+			c.JSON(404, errorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
 		})
 		return
 	}
-	c.JSON(200, movieFilesResult{
-		Results: toMovieFilesResponse(result),
-		Total:   total,
-	})
+	c.JSON(202, toDeletionJobResponse(job))
 }
 
-// @Summary Delete a file
-// @Description Delete a file
+// @Summary Get a deletion job
+// @Description Get the status of a file deletion: PENDING, RUNNING, DONE once the file is removed,
+// @Description or FAILED when its objects could not be deleted from the storage, the file being kept
 // @Tags File
-// @Param id path int true "File ID"
+// @Param id path string true "Job ID"
 // @Produce json
-// @Success 200 {string} string "OK"
+// @Success 200 {object} deletionJobResponse
 // @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
-// @Router /file/{id} [delete]
-func deleteFile(c *gin.Context, mediaData *features.MediaFile) {
+// @Router /file/jobs/{id} [get]
+func getDeletionJob(c *gin.Context, mediaData *features.MediaFile) {
 	id := c.Param("id")
-	if id == "" {
+	if _, err := uuid.Parse(id); err != nil {
 		c.JSON(400, errorResponse{
-			Error: "id is required",
+			Error: "id must be a job ID",
 		})
 		return
 	}
 
-	err := mediaData.DeleteMediaFile(id)
+	job, err := mediaData.GetDeletionJob(id)
 	if err != nil {
+		if errors.Is(err, features.ErrDeletionJobNotFound) {
+			c.JSON(404, errorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
 		c.JSON(500, errorResponse{
 			Error: err.Error(),
 		})
 		return
 	}
-	c.JSON(200, "OK")
+	c.JSON(200, toDeletionJobResponse(job))
 }
 
 // @Summary Get total size
@@ -284,6 +334,74 @@ func getTotalSize(c *gin.Context, mediaData *features.MediaFile) {
 	c.JSON(200, size)
 }
 
+// @Summary Get storage usage
+// @Description Get the storage used by the files, by movies and episodes, by tv show, by season and by year added,
+// @Description with the largest files and the available space of the movie and tv folders
+// @Tags File
+// @Param limit query int false "Number of tv shows, seasons and files listed, 20 by default and at most 100"
+// @Produce json
+// @Success 200 {object} storageUsageResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/usage [get]
+func getStorageUsage(c *gin.Context, mediaData *features.MediaFile) {
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: "limit must be a number",
+		})
+		return
+	}
+	report, err := mediaData.GetStorageUsage(limit)
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toStorageUsageResponse(report))
+}
+
+// @Summary Get folders usage
+// @Description Get the available space of the movie and tv folders, flagged LOW or CRITICAL
+// @Description when it is under the configured percentages
+// @Tags File
+// @Produce json
+// @Success 200 {array} folderUsageResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/usage/folders [get]
+func getFoldersUsage(c *gin.Context, mediaData *features.MediaFile) {
+	folders, err := mediaData.GetFoldersUsage()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, toFoldersUsageResponse(folders))
+}
+
+// @Summary Get languages
+// @Description Audio and subtitle languages present in the library, with the number of movies and episodes having each of them
+// @Tags File
+// @Produce json
+// @Success 200 {object} languagesResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/languages [get]
+func getLanguages(c *gin.Context, mediaData *features.MediaFile) {
+	audios, subtitles, err := mediaData.GetLanguages()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(200, languagesResponse{
+		Audios:    toLanguageCountsResponse(audios),
+		Subtitles: toLanguageCountsResponse(subtitles),
+	})
+}
+
 // @Summary Count files
 // @Description Count files
 // @Tags File
@@ -9,52 +9,52 @@ import (
 
 func InitMediaDataController(engine *gin.RouterGroup, mediaData *features.MediaData) {
 	engine.GET("/movie-tmdb/:id", func(c *gin.Context) {
-		getMovieByTMDB(c, mediaData)
+		getMovieByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movie-tmdb/:id/short", func(c *gin.Context) {
-		getMovieShortByTMDB(c, mediaData)
+		getMovieShortByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.POST("/movies-tmdb", func(c *gin.Context) {
-		getMoviesShortByTMDB(c, mediaData)
+		getMoviesShortByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-tmdb/:id", func(c *gin.Context) {
-		getTvShowByTMDB(c, mediaData)
+		getTvShowByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-tmdb/:id/short", func(c *gin.Context) {
-		getTvShowShortByTMDB(c, mediaData)
+		getTvShowShortByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.POST("/tvshows-tmdb", func(c *gin.Context) {
-		getTvShowsShortByTMDB(c, mediaData)
+		getTvShowsShortByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-episode-tmdb/:id/:season/:episode", func(c *gin.Context) {
-		getTvShowEpisodeByTMDB(c, mediaData)
+		getTvShowEpisodeByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-season-episodes-tmdb/:id/:season", func(c *gin.Context) {
-		getTvShowSeasonEpisodesByTMDB(c, mediaData)
+		getTvShowSeasonEpisodesByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-episodes-tmdb/:id", func(c *gin.Context) {
-		getTvShowEpisodesByTMDB(c, mediaData)
+		getTvShowEpisodesByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tvshow-episodes-tmdb/:id/ids", func(c *gin.Context) {
-		getTvShowEpisodesIdsByTMDB(c, mediaData)
+		getTvShowEpisodesIdsByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/episode-tmdb/:id", func(c *gin.Context) {
-		getEpisodeByTMDB(c, mediaData)
+		getEpisodeByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.POST("/episodes-tmdb", func(c *gin.Context) {
-		getEpisodesByTMDB(c, mediaData)
+		getEpisodesByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/base/movie/:id", func(c *gin.Context) {
-		getMovieBaseByTMDB(c, mediaData)
+		getMovieBaseByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/base/tv/:id", func(c *gin.Context) {
-		getTvShowBaseByTMDB(c, mediaData)
+		getTvShowBaseByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.GET("/base/episode/:id", func(c *gin.Context) {
-		getEpisodeBaseByTMDB(c, mediaData)
+		getEpisodeBaseByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 	engine.POST("/base/episodes", func(c *gin.Context) {
-		getEpisodesBaseByTMDB(c, mediaData)
+		getEpisodesBaseByTMDB(c, mediaData.WithContext(c.Request.Context()))
 	})
 }
 
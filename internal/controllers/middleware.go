@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const requestIDHeader = "X-Request-ID"
+const traceIDHeader = "X-Trace-ID"
+
+// mediaIDParams are the path parameters holding a media TMDB ID, depending on the route
+var mediaIDParams = []string{"id", "mediaID", "movie", "tv"}
+
+// requestContextMiddleware propagates the request ID (generating one if the client did not send it)
+// and attaches a logger carrying the request fields to the request context
+func requestContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header(requestIDHeader, requestID)
+
+		attrs := []any{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+		}
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", traceID))
+		}
+		if userID := requestUserID(c); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		for _, param := range mediaIDParams {
+			if mediaID := c.Param(param); mediaID != "" {
+				attrs = append(attrs, slog.String("media_id", mediaID))
+				break
+			}
+		}
+		ctx := logging.With(c.Request.Context(), attrs...)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// requestLoggerMiddleware logs every request once it has been handled
+func requestLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		} else if status >= 400 {
+			level = slog.LevelWarn
+		}
+		attrs := []any{
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request handled", attrs...)
+	}
+}
+
+// traceErrorMiddleware exposes the trace ID of the request in the X-Trace-ID header
+// and adds it to the body of JSON error responses
+func traceErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID := tracing.TraceID(c.Request.Context())
+		if traceID == "" {
+			c.Next()
+			return
+		}
+		c.Header(traceIDHeader, traceID)
+		c.Writer = &traceErrorWriter{ResponseWriter: c.Writer, traceID: traceID}
+		c.Next()
+	}
+}
+
+// traceErrorWriter adds the traceId field to JSON object bodies written with an error status
+type traceErrorWriter struct {
+	gin.ResponseWriter
+	traceID string
+}
+
+func (w *traceErrorWriter) Write(data []byte) (int, error) {
+	if w.Status() < 400 {
+		return w.ResponseWriter.Write(data)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	body["traceId"] = w.traceID
+	enriched, err := json.Marshal(body)
+	if err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(enriched); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *traceErrorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// adminMiddleware answers 403 to the requests of users without the bingemate-admin role
+func adminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.Contains(c.GetHeader("roles"), "bingemate-admin") {
+			c.AbortWithStatusJSON(403, errorResponse{
+				Error: "admin role is required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestUserID returns the ID of the user making the request, from the user-id header or the path
+func requestUserID(c *gin.Context) string {
+	if userID := c.GetHeader("user-id"); userID != "" {
+		return userID
+	}
+	if userID := c.Param("userID"); userID != "" {
+		return userID
+	}
+	return c.Param("user-id")
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
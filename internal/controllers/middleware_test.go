@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Group("/admin", adminMiddleware()).GET("/ping", func(c *gin.Context) {
+		c.Status(204)
+	})
+	tests := []struct {
+		roles string
+		want  int
+	}{
+		{"", 403},
+		{"bingemate-user", 403},
+		{"bingemate-user,bingemate-admin", 204},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/admin/ping", nil)
+		request.Header.Set("roles", test.roles)
+		engine.ServeHTTP(recorder, request)
+		if recorder.Code != test.want {
+			t.Errorf("roles %q status = %d, want %d", test.roles, recorder.Code, test.want)
+		}
+	}
+}
+
+func TestTraceErrorWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Writer = &traceErrorWriter{ResponseWriter: c.Writer, traceID: "4bf92f3577b34da6a3ce929d0e0e4736"}
+		c.Next()
+	})
+	engine.GET("/write", func(c *gin.Context) {
+		c.Status(500)
+		_, _ = c.Writer.Write([]byte(`{"error":"failed"}`))
+	})
+	engine.GET("/write-string", func(c *gin.Context) {
+		c.Status(500)
+		_, _ = c.Writer.WriteString(`{"error":"failed"}`)
+	})
+	engine.GET("/string", func(c *gin.Context) {
+		c.String(500, `{"error":"failed"}`)
+	})
+	for _, path := range []string{"/write", "/write-string", "/string"} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		var body map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s body %q: %v", path, recorder.Body.String(), err)
+		}
+		if body["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || body["error"] != "failed" {
+			t.Errorf("%s body = %v, want the error with its trace ID", path, body)
+		}
+	}
+}
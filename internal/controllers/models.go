@@ -3,12 +3,15 @@ package controllers
 import (
 	"github.com/bingemate/media-go-pkg/repository"
 	"github.com/bingemate/media-go-pkg/tmdb"
-	"sort"
+	"github.com/bingemate/media-service/internal/features"
+	mediaRepository "github.com/bingemate/media-service/internal/repository"
+	"math"
 	"time"
 )
 
 type errorResponse struct {
-	Error string `json:"error" example:"error message"`
+	Error   string `json:"error" example:"error message"`
+	TraceID string `json:"traceId,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
 }
 
 type genre struct {
@@ -122,6 +125,8 @@ type episodeFileResponse struct {
 type episodeFilesResult struct {
 	Results []*episodeFileResponse `json:"results"`
 	Total   int                    `json:"total"`
+	Next    string                 `json:"next,omitempty" example:"eyJyIjowLjUsImMiOiIyMDIzLTA1LTA3VDIwOjMxOjI4WiIsImkiOiIxMiJ9"`
+	HasNext bool                   `json:"hasNext" example:"true"`
 }
 
 type movieFileResponse struct {
@@ -134,6 +139,8 @@ type movieFileResponse struct {
 type movieFilesResult struct {
 	Results []*movieFileResponse `json:"results"`
 	Total   int                  `json:"total"`
+	Next    string               `json:"next,omitempty" example:"eyJyIjowLjUsImMiOiIyMDIzLTA1LTA3VDIwOjMxOjI4WiIsImkiOiIxMiJ9"`
+	HasNext bool                 `json:"hasNext" example:"true"`
 }
 
 type mediaFileResponse struct {
@@ -153,6 +160,7 @@ type audioResponse struct {
 }
 
 type subtitleResponse struct {
+	ID       string `json:"id" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
 	Language string `json:"language" example:"fre"`
 	Filename string `json:"filename" example:"subtitle_1.vtt"`
 }
@@ -173,6 +181,8 @@ type commentResponse struct {
 type commentResults struct {
 	Results     []*commentResponse `json:"results"`
 	TotalResult int                `json:"totalResult" example:"1412"`
+	Next        string             `json:"next,omitempty" example:"eyJjIjoiMjAyMy0wNS0wN1QyMDozMToyOFoiLCJpIjoiMTIifQ"`
+	HasNext     bool               `json:"hasNext" example:"true"`
 }
 
 type commentHistoryReponse struct {
@@ -180,6 +190,13 @@ type commentHistoryReponse struct {
 	Count int    `json:"count" example:"12"`
 }
 
+type commentHistoryResults struct {
+	Results     []*commentHistoryReponse `json:"results"`
+	TotalResult int                      `json:"totalResult" example:"21"`
+	Next        string                   `json:"next,omitempty" example:"eyJjIjoiMjAyMy0wNS0wN1QwMDowMDowMFoiLCJpIjoiMjAyMy0wNS0wNyJ9"`
+	HasNext     bool                     `json:"hasNext" example:"true"`
+}
+
 type ratingRequest struct {
 	Rating int `json:"rating" example:"5"`
 }
@@ -195,6 +212,8 @@ type ratingResponse struct {
 type ratingResults struct {
 	Results     []*ratingResponse `json:"results"`
 	TotalResult int               `json:"totalResult" example:"14"`
+	Next        string            `json:"next,omitempty" example:"eyJjIjoiMjAyMy0wNS0wN1QyMDozMToyOFoiLCJpIjoiMTIifQ"`
+	HasNext     bool              `json:"hasNext" example:"true"`
 }
 
 type tvShowResults struct {
@@ -219,6 +238,330 @@ type actorResults struct {
 	TotalResult int      `json:"totalResult" example:"1412"`
 }
 
+type searchResultResponse struct {
+	Type    string             `json:"type" enums:"movie,tv,episode,person" example:"movie"`
+	Present bool               `json:"present"`
+	Movie   *movieResponse     `json:"movie,omitempty"`
+	TvShow  *tvShowResponse    `json:"tvShow,omitempty"`
+	Episode *tvEpisodeResponse `json:"episode,omitempty"`
+	Person  *actor             `json:"person,omitempty"`
+}
+
+type searchTotal struct {
+	TotalPage   int `json:"totalPage" example:"71"`
+	TotalResult int `json:"totalResult" example:"1412"`
+}
+
+type searchResults struct {
+	Results     []*searchResultResponse `json:"results"`
+	TotalPage   int                     `json:"totalPage" example:"71"`
+	TotalResult int                     `json:"totalResult" example:"1412"`
+	Totals      map[string]searchTotal  `json:"totals"`
+}
+
+type suggestionResponse struct {
+	Type     string `json:"type" enums:"movie,tv,episode" example:"movie"`
+	ID       int    `json:"id" example:"11"`
+	TvShowID int    `json:"tvShowId,omitempty" example:"1399"`
+	Name     string `json:"name" example:"Star Wars"`
+	Source   string `json:"source" enums:"library,tmdb" example:"library"`
+}
+
+type facetValue struct {
+	Value string `json:"value" example:"Action"`
+	Count int    `json:"count" example:"12"`
+}
+
+type libraryFacets struct {
+	Genres            []facetValue `json:"genres"`
+	Years             []facetValue `json:"years"`
+	Durations         []facetValue `json:"durations"`
+	AudioLanguages    []facetValue `json:"audioLanguages"`
+	SubtitleLanguages []facetValue `json:"subtitleLanguages"`
+	Ratings           []facetValue `json:"ratings"`
+}
+
+type libraryResults struct {
+	Movies      []*movieResponse  `json:"movies,omitempty"`
+	TvShows     []*tvShowResponse `json:"tvShows,omitempty"`
+	TotalPage   int               `json:"totalPage" example:"3"`
+	TotalResult int               `json:"totalResult" example:"52"`
+	Facets      libraryFacets     `json:"facets"`
+}
+
+type languageCountResponse struct {
+	Language string `json:"language" example:"fre"`
+	Movies   int    `json:"movies" example:"120"`
+	Episodes int    `json:"episodes" example:"1345"`
+}
+
+type languagesResponse struct {
+	Audios    []languageCountResponse `json:"audios"`
+	Subtitles []languageCountResponse `json:"subtitles"`
+}
+
+type progressRequest struct {
+	Position float64 `json:"position" example:"1832.5"`
+}
+
+type watchProgressResponse struct {
+	Position  float64    `json:"position" example:"1832.5"`
+	Duration  float64    `json:"duration" example:"6840"`
+	Watched   bool       `json:"watched" example:"false"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2023-05-07T20:31:28.327382+02:00"`
+}
+
+type continueWatchingResponse struct {
+	Type    string               `json:"type" example:"episode"`
+	Movie   *movieFileResponse   `json:"movie,omitempty"`
+	Episode *episodeFileResponse `json:"episode,omitempty"`
+	watchProgressResponse
+}
+
+type viewingDayResponse struct {
+	Date  string  `json:"date" example:"2023-05-07"`
+	Hours float64 `json:"hours" example:"2.5"`
+}
+
+type genreViewingResponse struct {
+	Name   string  `json:"name" example:"Drama"`
+	Hours  float64 `json:"hours" example:"42.75"`
+	Titles int     `json:"titles" example:"12"`
+}
+
+type tvShowViewingResponse struct {
+	ID       int     `json:"id" example:"1399"`
+	Name     string  `json:"name" example:"Game of Thrones"`
+	Hours    float64 `json:"hours" example:"61.2"`
+	Episodes int     `json:"episodes" example:"73"`
+}
+
+type viewingStatsResponse struct {
+	Start            string                  `json:"start" example:"2023-01-01"`
+	End              string                  `json:"end" example:"2023-12-31"`
+	Hours            float64                 `json:"hours" example:"312.4"`
+	MoviesFinished   int                     `json:"moviesFinished" example:"48"`
+	EpisodesFinished int                     `json:"episodesFinished" example:"260"`
+	TvShowsWatched   int                     `json:"tvShowsWatched" example:"14"`
+	Days             []viewingDayResponse    `json:"days"`
+	TopGenres        []genreViewingResponse  `json:"topGenres"`
+	TopTvShows       []tvShowViewingResponse `json:"topTvShows"`
+}
+
+type recommendedTitleResponse struct {
+	Type string `json:"type" example:"movie"`
+	ID   int    `json:"id" example:"603"`
+	Name string `json:"name" example:"The Matrix"`
+}
+
+type recommendationResponse struct {
+	recommendedTitleResponse
+	Score   float64                    `json:"score" example:"1.25"`
+	Because []recommendedTitleResponse `json:"because"`
+}
+
+type deletionJobResponse struct {
+	ID          string    `json:"id" example:"0b9f0a1e-3f1c-4c1e-9d0a-2f4b8c6d7e8f"`
+	MediaFileID string    `json:"mediaFileId" example:"5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"`
+	Status      string    `json:"status" example:"FAILED"`
+	Attempts    int       `json:"attempts" example:"5"`
+	Error       string    `json:"error,omitempty" example:"RequestError: send request failed"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-05-07T20:31:28.327382+02:00"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2023-05-07T20:31:28.327382+02:00"`
+}
+
+type missingMediaResponse struct {
+	MediaID     int    `json:"mediaId" example:"603"`
+	MediaFileID string `json:"mediaFileId" example:"5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"`
+	Prefix      string `json:"prefix" example:"movies/603"`
+}
+
+type missingFileResponse struct {
+	MediaID     int    `json:"mediaId" example:"603"`
+	MediaFileID string `json:"mediaFileId" example:"5c1f7e2a-8b3d-4e6f-a1b2-c3d4e5f6a7b8"`
+	Kind        string `json:"kind" example:"subtitle"`
+	ID          string `json:"id" example:"9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"`
+	Key         string `json:"key" example:"movies/603/subtitle_3.vtt"`
+}
+
+type reconciliationResponse struct {
+	CheckedMovies    int                    `json:"checkedMovies" example:"412"`
+	CheckedEpisodes  int                    `json:"checkedEpisodes" example:"5230"`
+	MissingMedia     []missingMediaResponse `json:"missingMedia"`
+	MissingFiles     []missingFileResponse  `json:"missingFiles"`
+	OrphanedPrefixes []string               `json:"orphanedPrefixes" example:"tv-shows/1234567"`
+	OrphanedObjects  []string               `json:"orphanedObjects" example:"movies/603/audio_2.m3u8"`
+	Consistent       bool                   `json:"consistent" example:"false"`
+	Repaired         bool                   `json:"repaired" example:"true"`
+}
+
+type playbackTrackResponse struct {
+	Language string `json:"language" example:"fre"`
+	URL      string `json:"url" example:"/media-service/playback/playlist/movies/603/audio_1.m3u8?expires=1683491488&signature=3q2-7w"`
+}
+
+type playbackURLsResponse struct {
+	ExpiresAt time.Time               `json:"expiresAt" example:"2023-05-08T02:31:28+02:00"`
+	Manifest  string                  `json:"manifest" example:"/media-service/playback/playlist/movies/603/index.m3u8?expires=1683491488&signature=3q2-7w"`
+	Audios    []playbackTrackResponse `json:"audios"`
+	Subtitles []playbackTrackResponse `json:"subtitles"`
+}
+
+type storageUsageResponse struct {
+	Movies       usageResponse         `json:"movies"`
+	Episodes     usageResponse         `json:"episodes"`
+	TvShows      []tvShowUsageResponse `json:"tvShows"`
+	Seasons      []seasonUsageResponse `json:"seasons"`
+	Years        []yearUsageResponse   `json:"years"`
+	LargestFiles []fileUsageResponse   `json:"largestFiles"`
+	Folders      []folderUsageResponse `json:"folders"`
+}
+
+type usageResponse struct {
+	Files int64 `json:"files" example:"412"`
+	Size  int64 `json:"size" example:"1099511627776"`
+}
+
+type tvShowUsageResponse struct {
+	TvShowID int    `json:"tvShowId" example:"1399"`
+	Name     string `json:"name" example:"Game of Thrones"`
+	Files    int64  `json:"files" example:"73"`
+	Size     int64  `json:"size" example:"107374182400"`
+}
+
+type seasonUsageResponse struct {
+	TvShowID int    `json:"tvShowId" example:"1399"`
+	Name     string `json:"name" example:"Game of Thrones"`
+	Season   int    `json:"season" example:"8"`
+	Files    int64  `json:"files" example:"6"`
+	Size     int64  `json:"size" example:"12884901888"`
+}
+
+type yearUsageResponse struct {
+	Year  int    `json:"year" example:"2023"`
+	Type  string `json:"type" example:"movie"`
+	Files int64  `json:"files" example:"120"`
+	Size  int64  `json:"size" example:"322122547200"`
+}
+
+type fileUsageResponse struct {
+	MediaFileID string    `json:"mediaFileId" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
+	Type        string    `json:"type" example:"episode"`
+	MediaID     int       `json:"mediaId" example:"63056"`
+	TvShowID    int       `json:"tvShowId,omitempty" example:"1399"`
+	Name        string    `json:"name" example:"Game of Thrones"`
+	Season      int       `json:"season,omitempty" example:"1"`
+	Episode     int       `json:"episode,omitempty" example:"1"`
+	Size        int64     `json:"size" example:"4294967296"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-05-07T20:31:28.327382+02:00"`
+}
+
+type folderUsageResponse struct {
+	Type             string  `json:"type" example:"tv"`
+	Total            uint64  `json:"total" example:"4398046511104"`
+	Available        uint64  `json:"available" example:"219902325555"`
+	AvailablePercent float64 `json:"availablePercent" example:"5"`
+	Status           string  `json:"status" example:"LOW"`
+}
+
+type retentionPolicyResponse struct {
+	UnwatchedDays int     `json:"unwatchedDays" example:"180"`
+	KeepSeasons   int     `json:"keepSeasons" example:"2"`
+	ProtectRating float64 `json:"protectRating" example:"4.5"`
+	Delete        bool    `json:"delete" example:"false"`
+	Interval      string  `json:"interval" example:"24h0m0s"`
+}
+
+type retentionCandidateResponse struct {
+	MediaFileID string     `json:"mediaFileId" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
+	Type        string     `json:"type" example:"episode"`
+	MediaID     int        `json:"mediaId" example:"63056"`
+	TvShowID    int        `json:"tvShowId,omitempty" example:"1399"`
+	Name        string     `json:"name" example:"Game of Thrones"`
+	Season      int        `json:"season,omitempty" example:"1"`
+	Episode     int        `json:"episode,omitempty" example:"1"`
+	Size        int64      `json:"size" example:"4294967296"`
+	AddedAt     time.Time  `json:"addedAt" example:"2023-05-07T20:31:28.327382+02:00"`
+	LastViewed  *time.Time `json:"lastViewed,omitempty" example:"2023-06-01T00:00:00Z"`
+	Rating      float64    `json:"rating" example:"3.5"`
+	Reasons     []string   `json:"reasons" example:"UNWATCHED,OLD_SEASON"`
+}
+
+type retentionReportResponse struct {
+	EvaluatedAt     time.Time                    `json:"evaluatedAt" example:"2023-05-07T20:31:28.327382+02:00"`
+	Policy          retentionPolicyResponse      `json:"policy"`
+	CheckedFiles    int                          `json:"checkedFiles" example:"5642"`
+	Candidates      []retentionCandidateResponse `json:"candidates"`
+	ReclaimableSize int64                        `json:"reclaimableSize" example:"536870912000"`
+	Protected       int                          `json:"protected" example:"12"`
+	Pending         int                          `json:"pending" example:"0"`
+	Deleted         bool                         `json:"deleted" example:"false"`
+	Jobs            []deletionJobResponse        `json:"jobs"`
+	Errors          []string                     `json:"errors"`
+}
+
+type duplicateFileResponse struct {
+	MediaFileID string    `json:"mediaFileId" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
+	AddedAt     time.Time `json:"addedAt" example:"2023-05-07T20:31:28.327382+02:00"`
+	Linked      bool      `json:"linked" example:"true"`
+}
+
+type duplicateGroupResponse struct {
+	Type            string                  `json:"type,omitempty" example:"movie"`
+	MediaID         int                     `json:"mediaId,omitempty" example:"603"`
+	Name            string                  `json:"name,omitempty" example:"The Matrix"`
+	Season          int                     `json:"season,omitempty" example:"1"`
+	Episode         int                     `json:"episode,omitempty" example:"1"`
+	Filename        string                  `json:"filename" example:"index.m3u8"`
+	Size            int64                   `json:"size" example:"4294967296"`
+	Duration        float64                 `json:"duration" example:"8160.5"`
+	ReclaimableSize int64                   `json:"reclaimableSize" example:"4294967296"`
+	Files           []duplicateFileResponse `json:"files"`
+}
+
+type duplicateReportResponse struct {
+	ScannedAt       time.Time                `json:"scannedAt" example:"2023-05-07T20:31:28.327382+02:00"`
+	Groups          []duplicateGroupResponse `json:"groups"`
+	ReclaimableSize int64                    `json:"reclaimableSize" example:"8589934592"`
+	Pending         int                      `json:"pending" example:"0"`
+}
+
+type duplicateResolveRequest struct {
+	Keep string `json:"keep" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
+}
+
+type duplicateResolutionResponse struct {
+	Group    duplicateGroupResponse `json:"group"`
+	Keep     string                 `json:"keep" example:"eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"`
+	Relinked bool                   `json:"relinked" example:"true"`
+	Jobs     []deletionJobResponse  `json:"jobs"`
+	Errors   []string               `json:"errors"`
+}
+
+type watchListStatusRequest struct {
+	Status string `json:"status" example:"PLAN_TO_WATCH"`
+}
+
+type watchListItemResponse struct {
+	MediaID int    `json:"mediaId" example:"550"`
+	Status  string `json:"status" example:"WATCHING"`
+}
+
+type watchListImportItem struct {
+	MediaID int    `json:"mediaId" example:"550"`
+	Status  string `json:"status" example:"FINISHED"`
+}
+
+type watchListImportRequest struct {
+	Movies  []watchListImportItem `json:"movies"`
+	TvShows []watchListImportItem `json:"tvShows"`
+}
+
+type watchListImportResponse struct {
+	Movies  int `json:"movies" example:"12"`
+	TvShows int `json:"tvShows" example:"3"`
+}
+
 type idsRequest struct {
 	IDs []int `json:"ids"`
 }
@@ -456,16 +799,29 @@ func toMediaFileResponse(mediaFile *repository.MediaFile) *mediaFileResponse {
 		Subtitles: func() []subtitleResponse {
 			var subtitles = make([]subtitleResponse, len(mediaFile.Subtitles))
 			for i, subtitle := range mediaFile.Subtitles {
-				subtitles[i] = subtitleResponse{
-					Filename: subtitle.Filename,
-					Language: subtitle.Language,
-				}
+				subtitles[i] = toSubtitleResponse(subtitle)
 			}
 			return subtitles
 		}(),
 	}
 }
 
+func toSubtitleResponse(subtitle repository.Subtitle) subtitleResponse {
+	return subtitleResponse{
+		ID:       subtitle.ID,
+		Filename: subtitle.Filename,
+		Language: subtitle.Language,
+	}
+}
+
+func toSubtitlesResponse(subtitles []repository.Subtitle) []subtitleResponse {
+	response := make([]subtitleResponse, len(subtitles))
+	for i, subtitle := range subtitles {
+		response[i] = toSubtitleResponse(subtitle)
+	}
+	return response
+}
+
 func toGenreResponse(tmdbGenre *tmdb.Genre) *genre {
 	return &genre{
 		ID:   tmdbGenre.ID,
@@ -506,6 +862,96 @@ func toActorsResponse(tmdbActors []*tmdb.Actor) []*actor {
 	return actors
 }
 
+func toSearchResultResponse(result *features.SearchResult) *searchResultResponse {
+	response := &searchResultResponse{
+		Type:    string(result.Type),
+		Present: result.Present,
+	}
+	switch result.Type {
+	case features.SearchResultMovie:
+		response.Movie = toMovieResponse(result.Movie, result.Present)
+	case features.SearchResultTvShow:
+		response.TvShow = toTVShowResponse(result.TvShow, result.Present)
+	case features.SearchResultEpisode:
+		response.Episode = toTVEpisodeResponse(result.Episode, result.Present)
+	case features.SearchResultPerson:
+		response.Person = toActorResponse(result.Person)
+	}
+	return response
+}
+
+func toSearchTotalsResponse(totals map[features.SearchResultType]features.SearchTotal) map[string]searchTotal {
+	responses := make(map[string]searchTotal, len(totals))
+	for resultType, total := range totals {
+		responses[string(resultType)] = searchTotal{total.TotalPage, total.TotalResult}
+	}
+	return responses
+}
+
+func toSearchResultsResponse(results []*features.SearchResult) []*searchResultResponse {
+	var responses = make([]*searchResultResponse, len(results))
+	for i, result := range results {
+		responses[i] = toSearchResultResponse(result)
+	}
+	return responses
+}
+
+func toSuggestionsResponse(suggestions []*features.Suggestion) []*suggestionResponse {
+	var responses = make([]*suggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		responses[i] = &suggestionResponse{
+			Type:     suggestion.Type,
+			ID:       suggestion.ID,
+			TvShowID: suggestion.TvShowID,
+			Name:     suggestion.Name,
+			Source:   string(suggestion.Source),
+		}
+	}
+	return responses
+}
+
+func toFacetValuesResponse(values []mediaRepository.FacetValue) []facetValue {
+	var facets = make([]facetValue, len(values))
+	for i, value := range values {
+		facets[i] = facetValue{
+			Value: value.Value,
+			Count: value.Count,
+		}
+	}
+	return facets
+}
+
+func toLibraryFacetsResponse(facets *mediaRepository.LibraryFacets) libraryFacets {
+	return libraryFacets{
+		Genres:            toFacetValuesResponse(facets.Genres),
+		Years:             toFacetValuesResponse(facets.Years),
+		Durations:         toFacetValuesResponse(facets.Durations),
+		AudioLanguages:    toFacetValuesResponse(facets.AudioLanguages),
+		SubtitleLanguages: toFacetValuesResponse(facets.SubtitleLanguages),
+		Ratings:           toFacetValuesResponse(facets.Ratings),
+	}
+}
+
+func toLanguageCountsResponse(languages []mediaRepository.LanguageCount) []languageCountResponse {
+	var responses = make([]languageCountResponse, len(languages))
+	for i, language := range languages {
+		responses[i] = languageCountResponse{
+			Language: language.Language,
+			Movies:   language.Movies,
+			Episodes: language.Episodes,
+		}
+	}
+	return responses
+}
+
+// cursorToken returns the opaque token of the cursor of a next page, empty on the last page
+func cursorToken(cursor *mediaRepository.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.String()
+}
+
 func toMovieCommentResponse(comment *repository.MovieComment) *commentResponse {
 	return &commentResponse{
 		ID:        comment.ID,
@@ -580,39 +1026,14 @@ func toTVShowRatingsResponse(ratings []*repository.TvShowRating) []*ratingRespon
 	return ratingsResponse
 }
 
-func toCommentHistories(movieComments []*repository.MovieComment, tvShowComments []*repository.TvShowComment) []*commentHistoryReponse {
-	var commentMap = make(map[string]*commentHistoryReponse)
-	for _, comment := range movieComments {
-		date := comment.CreatedAt.Format("2006-01-02")
-		if _, ok := commentMap[date]; !ok {
-			commentMap[date] = &commentHistoryReponse{
-				Date:  date,
-				Count: 1,
-			}
-		} else {
-			commentMap[date].Count++
-		}
-	}
-	for _, comment := range tvShowComments {
-		date := comment.CreatedAt.Format("2006-01-02")
-		if _, ok := commentMap[date]; !ok {
-			commentMap[date] = &commentHistoryReponse{
-				Date:  date,
-				Count: 1,
-			}
-		} else {
-			commentMap[date].Count++
+func toCommentHistories(days []*mediaRepository.CommentDay) []*commentHistoryReponse {
+	var commentHistories = make([]*commentHistoryReponse, len(days))
+	for i, day := range days {
+		commentHistories[i] = &commentHistoryReponse{
+			Date:  day.Day,
+			Count: day.Count,
 		}
 	}
-	var commentHistories = make([]*commentHistoryReponse, len(commentMap))
-	i := 0
-	for _, commentHistory := range commentMap {
-		commentHistories[i] = commentHistory
-		i++
-	}
-	sort.Slice(commentHistories, func(i, j int) bool {
-		return commentHistories[i].Date < commentHistories[j].Date
-	})
 	return commentHistories
 }
 
@@ -653,3 +1074,332 @@ func toMovieFilesResponse(movies []*repository.Movie) []*movieFileResponse {
 	}
 	return moviesResponse
 }
+
+func toWatchProgressResponse(progress *features.WatchProgress) *watchProgressResponse {
+	return &watchProgressResponse{
+		Position:  progress.Position,
+		Duration:  progress.Duration,
+		Watched:   progress.Watched,
+		UpdatedAt: progress.UpdatedAt,
+	}
+}
+
+func toContinueWatchingResponse(item *features.ContinueWatchingItem) *continueWatchingResponse {
+	response := &continueWatchingResponse{
+		Type:                  string(item.Type),
+		watchProgressResponse: *toWatchProgressResponse(&item.WatchProgress),
+	}
+	if item.Movie != nil {
+		response.Movie = toMovieFileResponse(item.Movie)
+	}
+	if item.Episode != nil {
+		response.Episode = toEpisodeFileResponse(item.Episode)
+	}
+	return response
+}
+
+func toContinueWatchingResponses(items []*features.ContinueWatchingItem) []*continueWatchingResponse {
+	var responses = make([]*continueWatchingResponse, len(items))
+	for i, item := range items {
+		responses[i] = toContinueWatchingResponse(item)
+	}
+	return responses
+}
+
+func toMovieWatchListItemResponse(item *repository.MovieWatchListItem) *watchListItemResponse {
+	return &watchListItemResponse{
+		MediaID: item.MovieID,
+		Status:  string(item.Status),
+	}
+}
+
+func toMovieWatchListResponse(items []*repository.MovieWatchListItem) []*watchListItemResponse {
+	var responses = make([]*watchListItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = toMovieWatchListItemResponse(item)
+	}
+	return responses
+}
+
+func toTvShowWatchListItemResponse(item *repository.TvShowWatchListItem) *watchListItemResponse {
+	return &watchListItemResponse{
+		MediaID: item.TvShowID,
+		Status:  string(item.Status),
+	}
+}
+
+func toTvShowWatchListResponse(items []*repository.TvShowWatchListItem) []*watchListItemResponse {
+	var responses = make([]*watchListItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = toTvShowWatchListItemResponse(item)
+	}
+	return responses
+}
+
+func toWatchListEntries(items []watchListImportItem) []features.WatchListEntry {
+	var entries = make([]features.WatchListEntry, len(items))
+	for i, item := range items {
+		entries[i] = features.WatchListEntry{MediaID: item.MediaID, Status: item.Status}
+	}
+	return entries
+}
+
+// toHours converts seconds to hours, rounded to the hundredth
+func toHours(seconds float64) float64 {
+	return math.Round(seconds/36) / 100
+}
+
+func toViewingStatsResponse(stats *features.ViewingStats) *viewingStatsResponse {
+	response := &viewingStatsResponse{
+		Start:            stats.Start.Format("2006-01-02"),
+		End:              stats.End.Format("2006-01-02"),
+		Hours:            toHours(stats.Totals.Seconds),
+		MoviesFinished:   stats.Totals.MoviesFinished,
+		EpisodesFinished: stats.Totals.EpisodesFinished,
+		TvShowsWatched:   stats.Totals.TvShowsWatched,
+		Days:             make([]viewingDayResponse, len(stats.Days)),
+		TopGenres:        make([]genreViewingResponse, len(stats.Genres)),
+		TopTvShows:       make([]tvShowViewingResponse, len(stats.TvShows)),
+	}
+	for i, day := range stats.Days {
+		response.Days[i] = viewingDayResponse{Date: day.Day.Format("2006-01-02"), Hours: toHours(day.Seconds)}
+	}
+	for i, genre := range stats.Genres {
+		response.TopGenres[i] = genreViewingResponse{Name: genre.Name, Hours: toHours(genre.Seconds), Titles: genre.Titles}
+	}
+	for i, tvShow := range stats.TvShows {
+		response.TopTvShows[i] = tvShowViewingResponse{ID: tvShow.TvShowID, Name: tvShow.Name, Hours: toHours(tvShow.Seconds), Episodes: tvShow.Episodes}
+	}
+	return response
+}
+
+func toRecommendedTitleResponse(media mediaRepository.SavedMedia) recommendedTitleResponse {
+	return recommendedTitleResponse{
+		Type: string(media.Type),
+		ID:   media.ID,
+		Name: media.Name,
+	}
+}
+
+func toRecommendationsResponse(recommendations []*features.Recommendation) []*recommendationResponse {
+	var responses = make([]*recommendationResponse, len(recommendations))
+	for i, recommendation := range recommendations {
+		responses[i] = &recommendationResponse{
+			recommendedTitleResponse: toRecommendedTitleResponse(recommendation.SavedMedia),
+			Score:                    math.Round(recommendation.Score*100) / 100,
+			Because:                  make([]recommendedTitleResponse, len(recommendation.Because)),
+		}
+		for j, reason := range recommendation.Because {
+			responses[i].Because[j] = toRecommendedTitleResponse(reason)
+		}
+	}
+	return responses
+}
+
+func toDeletionJobResponse(job *mediaRepository.DeletionJob) *deletionJobResponse {
+	return &deletionJobResponse{
+		ID:          job.ID,
+		MediaFileID: job.MediaFileID,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+func toReconciliationResponse(report *features.ReconciliationReport) *reconciliationResponse {
+	response := &reconciliationResponse{
+		CheckedMovies:    report.CheckedMovies,
+		CheckedEpisodes:  report.CheckedEpisodes,
+		MissingMedia:     make([]missingMediaResponse, len(report.MissingObjects)),
+		MissingFiles:     make([]missingFileResponse, len(report.MissingFiles)),
+		OrphanedPrefixes: append([]string{}, report.OrphanedPrefixes...),
+		OrphanedObjects:  append([]string{}, report.OrphanedObjects...),
+		Consistent:       report.Consistent(),
+		Repaired:         report.Repaired,
+	}
+	for i, missing := range report.MissingObjects {
+		response.MissingMedia[i] = missingMediaResponse{
+			MediaID:     missing.MediaID,
+			MediaFileID: missing.MediaFileID,
+			Prefix:      missing.Prefix,
+		}
+	}
+	for i, missing := range report.MissingFiles {
+		response.MissingFiles[i] = missingFileResponse{
+			MediaID:     missing.MediaID,
+			MediaFileID: missing.MediaFileID,
+			Kind:        string(missing.Kind),
+			ID:          missing.ID,
+			Key:         missing.Key,
+		}
+	}
+	return response
+}
+
+func toPlaybackURLsResponse(urls *features.PlaybackURLs) *playbackURLsResponse {
+	response := &playbackURLsResponse{
+		ExpiresAt: urls.ExpiresAt,
+		Manifest:  urls.Manifest,
+		Audios:    make([]playbackTrackResponse, len(urls.Audios)),
+		Subtitles: make([]playbackTrackResponse, len(urls.Subtitles)),
+	}
+	for i, audio := range urls.Audios {
+		response.Audios[i] = playbackTrackResponse{Language: audio.Language, URL: audio.URL}
+	}
+	for i, subtitle := range urls.Subtitles {
+		response.Subtitles[i] = playbackTrackResponse{Language: subtitle.Language, URL: subtitle.URL}
+	}
+	return response
+}
+
+func toStorageUsageResponse(report *features.StorageUsageReport) *storageUsageResponse {
+	response := &storageUsageResponse{
+		Movies:       usageResponse{Files: report.Movies.Files, Size: report.Movies.Size},
+		Episodes:     usageResponse{Files: report.Episodes.Files, Size: report.Episodes.Size},
+		TvShows:      make([]tvShowUsageResponse, len(report.TvShows)),
+		Seasons:      make([]seasonUsageResponse, len(report.Seasons)),
+		Years:        make([]yearUsageResponse, len(report.Years)),
+		LargestFiles: make([]fileUsageResponse, len(report.LargestFiles)),
+		Folders:      toFoldersUsageResponse(report.Folders),
+	}
+	for i, show := range report.TvShows {
+		response.TvShows[i] = tvShowUsageResponse{TvShowID: show.TvShowID, Name: show.Name, Files: show.Files, Size: show.Size}
+	}
+	for i, season := range report.Seasons {
+		response.Seasons[i] = seasonUsageResponse{
+			TvShowID: season.TvShowID,
+			Name:     season.Name,
+			Season:   season.Season,
+			Files:    season.Files,
+			Size:     season.Size,
+		}
+	}
+	for i, year := range report.Years {
+		response.Years[i] = yearUsageResponse{Year: year.Year, Type: string(year.Type), Files: year.Files, Size: year.Size}
+	}
+	for i, file := range report.LargestFiles {
+		response.LargestFiles[i] = fileUsageResponse{
+			MediaFileID: file.MediaFileID,
+			Type:        string(file.Type),
+			MediaID:     file.MediaID,
+			TvShowID:    file.TvShowID,
+			Name:        file.Name,
+			Season:      file.Season,
+			Episode:     file.Episode,
+			Size:        file.Size,
+			CreatedAt:   file.CreatedAt,
+		}
+	}
+	return response
+}
+
+func toFoldersUsageResponse(folders []features.FolderUsage) []folderUsageResponse {
+	response := make([]folderUsageResponse, len(folders))
+	for i, folder := range folders {
+		response[i] = folderUsageResponse{
+			Type:             string(folder.Type),
+			Total:            folder.Total,
+			Available:        folder.Available,
+			AvailablePercent: math.Round(folder.AvailablePercent*100) / 100,
+			Status:           string(folder.Status),
+		}
+	}
+	return response
+}
+
+func toRetentionReportResponse(report *features.RetentionReport) *retentionReportResponse {
+	response := &retentionReportResponse{
+		EvaluatedAt: report.EvaluatedAt,
+		Policy: retentionPolicyResponse{
+			UnwatchedDays: report.Policy.UnwatchedDays,
+			KeepSeasons:   report.Policy.KeepSeasons,
+			ProtectRating: report.Policy.ProtectRating,
+			Delete:        report.Policy.Delete,
+			Interval:      report.Policy.Interval.String(),
+		},
+		CheckedFiles:    report.CheckedFiles,
+		Candidates:      make([]retentionCandidateResponse, len(report.Candidates)),
+		ReclaimableSize: report.ReclaimableSize,
+		Protected:       report.Protected,
+		Pending:         report.Pending,
+		Deleted:         report.Deleted,
+		Jobs:            make([]deletionJobResponse, len(report.Jobs)),
+		Errors:          append([]string{}, report.Errors...),
+	}
+	for i, candidate := range report.Candidates {
+		reasons := make([]string, len(candidate.Reasons))
+		for j, reason := range candidate.Reasons {
+			reasons[j] = string(reason)
+		}
+		response.Candidates[i] = retentionCandidateResponse{
+			MediaFileID: candidate.MediaFileID,
+			Type:        string(candidate.Type),
+			MediaID:     candidate.MediaID,
+			TvShowID:    candidate.TvShowID,
+			Name:        candidate.Name,
+			Season:      candidate.Season,
+			Episode:     candidate.Episode,
+			Size:        candidate.Size,
+			AddedAt:     candidate.AddedAt,
+			LastViewed:  candidate.LastViewed,
+			Rating:      math.Round(candidate.Rating*100) / 100,
+			Reasons:     reasons,
+		}
+	}
+	for i, job := range report.Jobs {
+		response.Jobs[i] = *toDeletionJobResponse(job)
+	}
+	return response
+}
+
+func toDuplicateReportResponse(report *features.DuplicateReport) *duplicateReportResponse {
+	response := &duplicateReportResponse{
+		ScannedAt:       report.ScannedAt,
+		Groups:          make([]duplicateGroupResponse, len(report.Groups)),
+		ReclaimableSize: report.ReclaimableSize,
+		Pending:         report.Pending,
+	}
+	for i, group := range report.Groups {
+		response.Groups[i] = toDuplicateGroupResponse(group)
+	}
+	return response
+}
+
+func toDuplicateGroupResponse(group features.DuplicateGroup) duplicateGroupResponse {
+	files := make([]duplicateFileResponse, len(group.Files))
+	for i, file := range group.Files {
+		files[i] = duplicateFileResponse{
+			MediaFileID: file.MediaFileID,
+			AddedAt:     file.AddedAt,
+			Linked:      file.MediaFileID == group.LinkedFileID,
+		}
+	}
+	return duplicateGroupResponse{
+		Type:            string(group.Type),
+		MediaID:         group.MediaID,
+		Name:            group.Name,
+		Season:          group.Season,
+		Episode:         group.Episode,
+		Filename:        group.Filename,
+		Size:            group.Size,
+		Duration:        group.Duration,
+		ReclaimableSize: group.ReclaimableSize(),
+		Files:           files,
+	}
+}
+
+func toDuplicateResolutionResponse(resolution *features.DuplicateResolution) *duplicateResolutionResponse {
+	response := &duplicateResolutionResponse{
+		Group:    toDuplicateGroupResponse(resolution.Group),
+		Keep:     resolution.Keep,
+		Relinked: resolution.Relinked,
+		Jobs:     make([]deletionJobResponse, len(resolution.Jobs)),
+		Errors:   append([]string{}, resolution.Errors...),
+	}
+	for i, job := range resolution.Jobs {
+		response.Jobs[i] = *toDeletionJobResponse(job)
+	}
+	return response
+}
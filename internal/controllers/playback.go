@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+func InitPlaybackController(engine *gin.RouterGroup, playback *features.Playback) {
+	engine.GET("movie/:mediaID", func(c *gin.Context) {
+		getMoviePlaybackURLs(c, playback.WithContext(c.Request.Context()))
+	})
+	engine.GET("episode/:mediaID", func(c *gin.Context) {
+		getEpisodePlaybackURLs(c, playback.WithContext(c.Request.Context()))
+	})
+	engine.GET("movie/:mediaID/master.m3u8", func(c *gin.Context) {
+		getMovieMasterPlaylist(c, playback.WithContext(c.Request.Context()))
+	})
+	engine.GET("episode/:mediaID/master.m3u8", func(c *gin.Context) {
+		getEpisodeMasterPlaylist(c, playback.WithContext(c.Request.Context()))
+	})
+	engine.GET("playlist/*key", func(c *gin.Context) {
+		getPlaylist(c, playback.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary Get movie playback URLs
+// @Description Get time-limited URLs reading the video manifest, the audio playlists and the subtitles of a movie,
+// @Description which do not need access to the bucket. They expire at expiresAt.
+// @Tags Playback
+// @Param mediaID path int true "Movie ID"
+// @Produce json
+// @Success 200 {object} playbackURLsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /playback/movie/{mediaID} [get]
+func getMoviePlaybackURLs(c *gin.Context, playback *features.Playback) {
+	mediaID, err := strconv.Atoi(c.Param("mediaID"))
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "mediaID must be a number"})
+		return
+	}
+	urls, err := playback.GetMoviePlaybackURLs(mediaID)
+	if err != nil {
+		playbackError(c, err)
+		return
+	}
+	c.JSON(200, toPlaybackURLsResponse(urls))
+}
+
+// @Summary Get episode playback URLs
+// @Description Get time-limited URLs reading the video manifest, the audio playlists and the subtitles of an episode,
+// @Description which do not need access to the bucket. They expire at expiresAt.
+// @Tags Playback
+// @Param mediaID path int true "Episode ID"
+// @Produce json
+// @Success 200 {object} playbackURLsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /playback/episode/{mediaID} [get]
+func getEpisodePlaybackURLs(c *gin.Context, playback *features.Playback) {
+	mediaID, err := strconv.Atoi(c.Param("mediaID"))
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "mediaID must be a number"})
+		return
+	}
+	urls, err := playback.GetEpisodePlaybackURLs(mediaID)
+	if err != nil {
+		playbackError(c, err)
+		return
+	}
+	c.JSON(200, toPlaybackURLsResponse(urls))
+}
+
+// @Summary Get movie master playlist
+// @Description Get an HLS master playlist of a movie, with a rendition per audio language and per WebVTT subtitle,
+// @Description which any HLS player can read without access to the bucket. Its URLs expire like the playback URLs.
+// @Description The audio in the preferred language is the default one, else the subtitle in it.
+// @Description Its BANDWIDTH and CODECS are assumed from the transcoder settings, not read from the file:
+// @Description H.264 High 4.0 video capped at 3 Mb/s and AAC-LC audio at 160 kb/s.
+// @Tags Playback
+// @Param mediaID path int true "Movie ID"
+// @Param language query string false "Preferred language (ISO 639 or BCP 47)"
+// @Produce application/vnd.apple.mpegurl
+// @Success 200 {string} string "HLS master playlist"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /playback/movie/{mediaID}/master.m3u8 [get]
+func getMovieMasterPlaylist(c *gin.Context, playback *features.Playback) {
+	mediaID, err := strconv.Atoi(c.Param("mediaID"))
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "mediaID must be a number"})
+		return
+	}
+	playlist, err := playback.GetMovieMasterPlaylist(mediaID, c.Query("language"))
+	if err != nil {
+		playbackError(c, err)
+		return
+	}
+	c.Data(200, "application/vnd.apple.mpegurl", playlist)
+}
+
+// @Summary Get episode master playlist
+// @Description Get an HLS master playlist of an episode, with a rendition per audio language and per WebVTT subtitle,
+// @Description which any HLS player can read without access to the bucket. Its URLs expire like the playback URLs.
+// @Description The audio in the preferred language is the default one, else the subtitle in it.
+// @Description Its BANDWIDTH and CODECS are assumed from the transcoder settings, not read from the file:
+// @Description H.264 High 4.0 video capped at 3 Mb/s and AAC-LC audio at 160 kb/s.
+// @Tags Playback
+// @Param mediaID path int true "Episode ID"
+// @Param language query string false "Preferred language (ISO 639 or BCP 47)"
+// @Produce application/vnd.apple.mpegurl
+// @Success 200 {string} string "HLS master playlist"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /playback/episode/{mediaID}/master.m3u8 [get]
+func getEpisodeMasterPlaylist(c *gin.Context, playback *features.Playback) {
+	mediaID, err := strconv.Atoi(c.Param("mediaID"))
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "mediaID must be a number"})
+		return
+	}
+	playlist, err := playback.GetEpisodeMasterPlaylist(mediaID, c.Query("language"))
+	if err != nil {
+		playbackError(c, err)
+		return
+	}
+	c.Data(200, "application/vnd.apple.mpegurl", playlist)
+}
+
+// @Summary Get a signed playlist
+// @Description Get an HLS playlist of the bucket whose segments are presigned URLs. Its URL is returned by the
+// @Description playback endpoints and is valid until its expiry time. A subtitle key followed by .m3u8 gives a
+// @Description playlist made of the subtitle.
+// @Tags Playback
+// @Param key path string true "Playlist key in the bucket"
+// @Param expires query int true "Expiry time (Unix seconds)"
+// @Param signature query string true "Signature"
+// @Produce application/vnd.apple.mpegurl
+// @Success 200 {string} string "HLS playlist"
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /playback/playlist/{key} [get]
+func getPlaylist(c *gin.Context, playback *features.Playback) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "expires must be a number"})
+		return
+	}
+	playlist, err := playback.GetPlaylist(strings.TrimPrefix(c.Param("key"), "/"), expires, c.Query("signature"))
+	if err != nil {
+		playbackError(c, err)
+		return
+	}
+	c.Data(200, "application/vnd.apple.mpegurl", playlist)
+}
+
+func playbackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, features.ErrMediaNotFound):
+		c.JSON(404, errorResponse{Error: err.Error()})
+	case errors.Is(err, features.ErrInvalidSignature):
+		c.JSON(403, errorResponse{Error: err.Error()})
+	default:
+		c.JSON(500, errorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+func InitProgressController(engine *gin.RouterGroup, progressService *features.WatchProgressService) {
+	engine.GET("movie/:mediaID", func(c *gin.Context) {
+		getMovieProgress(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.PUT("movie/:mediaID", func(c *gin.Context) {
+		saveMovieProgress(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.GET("episode/:mediaID", func(c *gin.Context) {
+		getEpisodeProgress(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.PUT("episode/:mediaID", func(c *gin.Context) {
+		saveEpisodeProgress(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.GET("tv/:mediaID/next", func(c *gin.Context) {
+		getNextEpisode(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.GET("continue-watching", func(c *gin.Context) {
+		getContinueWatching(c, progressService.WithContext(c.Request.Context()))
+	})
+	engine.GET("stats/:userID", func(c *gin.Context) {
+		getViewingStats(c, progressService.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary Get movie progress
+// @Description Get the playback position of the user in a movie, 0 if the user never played it
+// @Tags Progress
+// @Param mediaID path int true "Movie ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 200 {object} watchProgressResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/movie/{mediaID} [get]
+func getMovieProgress(c *gin.Context, progressService *features.WatchProgressService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	progress, err := progressService.GetMovieProgress(userID, mediaID)
+	if err != nil {
+		progressError(c, err)
+		return
+	}
+	c.JSON(200, toWatchProgressResponse(progress))
+}
+
+// @Summary Save movie progress
+// @Description Save the playback position of the user in a movie, which is marked watched once 90% of it is reached
+// @Tags Progress
+// @Param mediaID path int true "Movie ID"
+// @Param user-id header string true "User ID"
+// @Param progress body progressRequest true "Position"
+// @Produce json
+// @Success 200 {object} watchProgressResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/movie/{mediaID} [put]
+func saveMovieProgress(c *gin.Context, progressService *features.WatchProgressService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	position, ok := bindPosition(c)
+	if !ok {
+		return
+	}
+	progress, err := progressService.SaveMovieProgress(userID, mediaID, position)
+	if err != nil {
+		progressError(c, err)
+		return
+	}
+	c.JSON(200, toWatchProgressResponse(progress))
+}
+
+// @Summary Get episode progress
+// @Description Get the playback position of the user in an episode, 0 if the user never played it
+// @Tags Progress
+// @Param mediaID path int true "Episode ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 200 {object} watchProgressResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/episode/{mediaID} [get]
+func getEpisodeProgress(c *gin.Context, progressService *features.WatchProgressService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	progress, err := progressService.GetEpisodeProgress(userID, mediaID)
+	if err != nil {
+		progressError(c, err)
+		return
+	}
+	c.JSON(200, toWatchProgressResponse(progress))
+}
+
+// @Summary Save episode progress
+// @Description Save the playback position of the user in an episode, which is marked watched once 90% of it is reached
+// @Tags Progress
+// @Param mediaID path int true "Episode ID"
+// @Param user-id header string true "User ID"
+// @Param progress body progressRequest true "Position"
+// @Produce json
+// @Success 200 {object} watchProgressResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/episode/{mediaID} [put]
+func saveEpisodeProgress(c *gin.Context, progressService *features.WatchProgressService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	position, ok := bindPosition(c)
+	if !ok {
+		return
+	}
+	progress, err := progressService.SaveEpisodeProgress(userID, mediaID, position)
+	if err != nil {
+		progressError(c, err)
+		return
+	}
+	c.JSON(200, toWatchProgressResponse(progress))
+}
+
+// @Summary Get next episode
+// @Description Get the available episode of a tv show the user should play next: the last played episode if it is unfinished,
+// @Description else the first unwatched episode following it, ordered by season and episode number
+// @Tags Progress
+// @Param mediaID path int true "TV Show ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 200 {object} continueWatchingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/tv/{mediaID}/next [get]
+func getNextEpisode(c *gin.Context, progressService *features.WatchProgressService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	next, err := progressService.GetNextEpisode(userID, mediaID)
+	if err != nil {
+		c.JSON(500, errorResponse{Error: err.Error()})
+		return
+	}
+	if next == nil {
+		c.JSON(404, errorResponse{Error: "no episode left to watch"})
+		return
+	}
+	c.JSON(200, toContinueWatchingResponse(next))
+}
+
+// @Summary Continue watching
+// @Description Get the unfinished movies and the next episodes of the tv shows the user started, the most recently played first
+// @Tags Progress
+// @Param user-id header string true "User ID"
+// @Param limit query int false "Number of items, 20 by default and at most 100"
+// @Produce json
+// @Success 200 {array} continueWatchingResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/continue-watching [get]
+func getContinueWatching(c *gin.Context, progressService *features.WatchProgressService) {
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "user-id header is required"})
+		return
+	}
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "limit must be a number"})
+		return
+	}
+	items, err := progressService.GetContinueWatching(userID, limit)
+	if err != nil {
+		c.JSON(500, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(200, toContinueWatchingResponses(items))
+}
+
+// @Summary Get viewing stats
+// @Description Get the time spent watching, the titles finished, the top genres and the most watched tv shows of a user
+// @Description between two days, both included. The current year is used by default.
+// @Tags Progress
+// @Param userID path string true "User ID"
+// @Param start query string false "Start date (YYYY-MM-DD)"
+// @Param end query string false "End date (YYYY-MM-DD)"
+// @Param limit query int false "Number of top genres and tv shows, 5 by default and at most 20"
+// @Produce json
+// @Success 200 {object} viewingStatsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /progress/stats/{userID} [get]
+func getViewingStats(c *gin.Context, progressService *features.WatchProgressService) {
+	userID := c.Param("userID")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "userID is required"})
+		return
+	}
+	now := time.Now()
+	start := c.DefaultQuery("start", time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	end := c.DefaultQuery("end", time.Date(now.Year(), 12, 31, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "limit must be a number"})
+		return
+	}
+
+	stats, err := progressService.GetViewingStats(userID, start, end, limit)
+	if err != nil {
+		if errors.Is(err, features.ErrInvalidDate) {
+			c.JSON(400, errorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(500, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(200, toViewingStatsResponse(stats))
+}
+
+func bindPosition(c *gin.Context) (float64, bool) {
+	var request progressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return 0, false
+	}
+	if request.Position < 0 {
+		c.JSON(400, errorResponse{Error: "position must not be negative"})
+		return 0, false
+	}
+	return request.Position, true
+}
+
+func progressError(c *gin.Context, err error) {
+	if errors.Is(err, features.ErrMediaNotFound) {
+		c.JSON(404, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(500, errorResponse{Error: err.Error()})
+}
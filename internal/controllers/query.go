@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+// queryList returns the comma separated values of a query parameter, which can also be repeated
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// queryLanguages returns the language filter of the audio and subtitle query parameters
+func queryLanguages(c *gin.Context) repository.LanguageFilter {
+	return repository.LanguageFilter{
+		Audios:    queryList(c, "audio"),
+		Subtitles: queryList(c, "subtitle"),
+	}
+}
+
+// optionalInt parses an integer query parameter, returning 0 when it is missing
+func optionalInt(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// optionalFloat parses a decimal query parameter, returning 0 when it is missing
+func optionalFloat(c *gin.Context, name string) (float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// queryPage parses the cursor, page and limit query parameters of a paginated list, the page being ignored with
+// a cursor, and returning a 0 limit when it is missing
+func queryPage(c *gin.Context) (repository.Page, error) {
+	cursor, err := repository.ParseCursor(c.Query("cursor"))
+	if err != nil {
+		return repository.Page{}, err
+	}
+	number, err := optionalInt(c, "page")
+	if err != nil {
+		return repository.Page{}, errors.New("page must be a number")
+	}
+	if c.Query("page") != "" && number < 1 {
+		return repository.Page{}, errors.New("page must be at least 1")
+	}
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		return repository.Page{}, errors.New("limit must be a number")
+	}
+	return repository.Page{Cursor: cursor, Number: number, Limit: limit}, nil
+}
+
+// pageError responds to the error of a cursor paginated list, a cursor not matching the list being a client error
+func pageError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(500, errorResponse{Error: err.Error()})
+}
+
+// mediaUserParams returns the mediaID path parameter and the user-id header, writing the error response when they are invalid
+func mediaUserParams(c *gin.Context) (int, string, bool) {
+	mediaID, err := strconv.Atoi(c.Param("mediaID"))
+	if err != nil {
+		c.JSON(400, errorResponse{Error: "mediaID must be a number"})
+		return 0, "", false
+	}
+	if mediaID <= 0 {
+		c.JSON(400, errorResponse{Error: "mediaID must be a positive number"})
+		return 0, "", false
+	}
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "user-id header is required"})
+		return 0, "", false
+	}
+	return mediaID, userID, true
+}
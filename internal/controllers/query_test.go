@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/gin-gonic/gin"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPageError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{repository.ErrInvalidCursor, 400},
+		{fmt.Errorf("wrapped: %w", repository.ErrInvalidCursor), 400},
+		{errors.New("connection refused"), 500},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		pageError(c, test.err)
+		if recorder.Code != test.want {
+			t.Errorf("pageError(%v) status = %d, want %d", test.err, recorder.Code, test.want)
+		}
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query      string
+		wantNumber int
+		wantLimit  int
+		wantErr    bool
+	}{
+		{"", 0, 0, false},
+		{"?limit=20", 0, 20, false},
+		{"?page=3&limit=20", 3, 20, false},
+		{"?limit=twenty", 0, 0, true},
+		{"?page=three", 0, 0, true},
+		{"?page=0", 0, 0, true},
+		{"?cursor=not-a-cursor", 0, 0, true},
+	}
+	for _, test := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/comment/history"+test.query, nil)
+		page, err := queryPage(c)
+		if (err != nil) != test.wantErr || page.Number != test.wantNumber || page.Limit != test.wantLimit {
+			t.Errorf("queryPage(%q) = %+v, %v", test.query, page, err)
+		}
+	}
+}
@@ -8,34 +8,34 @@ import (
 
 func InitRatingController(engine *gin.RouterGroup, ratingService *features.RatingService) {
 	engine.GET("/movie/:mediaID", func(c *gin.Context) {
-		getMovieRating(c, ratingService)
+		getMovieRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movie/:mediaID/own", func(c *gin.Context) {
-		getUserMovieRating(c, ratingService)
+		getUserMovieRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tv/:mediaID", func(c *gin.Context) {
-		getTVShowRating(c, ratingService)
+		getTVShowRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tv/:mediaID/own", func(c *gin.Context) {
-		getUserTVShowRating(c, ratingService)
+		getUserTVShowRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/movie/user/:userID", func(c *gin.Context) {
-		getUserMovieRatings(c, ratingService)
+		getUserMovieRatings(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.POST("/movie/:mediaID", func(c *gin.Context) {
-		saveMovieRating(c, ratingService)
+		saveMovieRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/tv/user/:userID", func(c *gin.Context) {
-		getUserTVShowRatings(c, ratingService)
+		getUserTVShowRatings(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.POST("/tv/:mediaID", func(c *gin.Context) {
-		saveTVShowRating(c, ratingService)
+		saveTVShowRating(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/user/count/:userID", func(c *gin.Context) {
-		getUserRatingCount(c, ratingService)
+		getUserRatingCount(c, ratingService.WithContext(c.Request.Context()))
 	})
 	engine.GET("/count", func(c *gin.Context) {
-		getRatingCount(c, ratingService)
+		getRatingCount(c, ratingService.WithContext(c.Request.Context()))
 	})
 }
 
@@ -43,7 +43,9 @@ func InitRatingController(engine *gin.RouterGroup, ratingService *features.Ratin
 // @Description Get movie's rating
 // @Tags Rating
 // @Param mediaID path int true "Movie ID"
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 10 by default and at most 100"
 // @Produce json
 // @Success 200 {object} ratingResults
 // @Failure 400 {object} errorResponse
@@ -59,19 +61,22 @@ func getMovieRating(c *gin.Context, ratingService *features.RatingService) {
 		c.JSON(400, errorResponse{Error: "mediaID must be a positive number"})
 		return
 	}
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 
-	ratings, count, err := ratingService.GetMovieRatings(mediaID, page)
+	ratings, err := ratingService.GetMovieRatings(mediaID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, ratingResults{
-		Results:     toMovieRatingsResponse(ratings),
-		TotalResult: count,
+		Results:     toMovieRatingsResponse(ratings.Items),
+		TotalResult: ratings.Total,
+		Next:        cursorToken(ratings.Next),
+		HasNext:     ratings.Next != nil,
 	})
 }
 
@@ -79,7 +84,9 @@ func getMovieRating(c *gin.Context, ratingService *features.RatingService) {
 // @Description Get tv show's rating
 // @Tags Rating
 // @Param mediaID path int true "TV Show ID"
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 10 by default and at most 100"
 // @Produce json
 // @Success 200 {object} ratingResults
 // @Failure 400 {object} errorResponse
@@ -95,19 +102,22 @@ func getTVShowRating(c *gin.Context, ratingService *features.RatingService) {
 		c.JSON(400, errorResponse{Error: "mediaID must be a positive number"})
 		return
 	}
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 
-	ratings, count, err := ratingService.GetTvShowRatings(mediaID, page)
+	ratings, err := ratingService.GetTvShowRatings(mediaID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, ratingResults{
-		Results:     toTVShowRatingsResponse(ratings),
-		TotalResult: count,
+		Results:     toTVShowRatingsResponse(ratings.Items),
+		TotalResult: ratings.Total,
+		Next:        cursorToken(ratings.Next),
+		HasNext:     ratings.Next != nil,
 	})
 }
 
@@ -183,7 +193,9 @@ func getUserTVShowRating(c *gin.Context, ratingService *features.RatingService)
 // @Description Get user's ratings
 // @Tags Rating
 // @Param userID path string true "User ID"
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 10 by default and at most 100"
 // @Produce json
 // @Success 200 {object} ratingResults
 // @Failure 400 {object} errorResponse
@@ -195,19 +207,22 @@ func getUserMovieRatings(c *gin.Context, ratingService *features.RatingService)
 		c.JSON(400, errorResponse{Error: "userID is required"})
 		return
 	}
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 
-	ratings, count, err := ratingService.GetUsersMovieRatings(userID, page)
+	ratings, err := ratingService.GetUsersMovieRatings(userID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, ratingResults{
-		Results:     toMovieRatingsResponse(ratings),
-		TotalResult: count,
+		Results:     toMovieRatingsResponse(ratings.Items),
+		TotalResult: ratings.Total,
+		Next:        cursorToken(ratings.Next),
+		HasNext:     ratings.Next != nil,
 	})
 }
 
@@ -215,7 +230,9 @@ func getUserMovieRatings(c *gin.Context, ratingService *features.RatingService)
 // @Description Get user's ratings
 // @Tags Rating
 // @Param userID path string true "User ID"
-// @Param page query int false "Page number"
+// @Param cursor query string false "Cursor of the page, returned as next by the previous page"
+// @Param page query int false "Page number, ignored with a cursor"
+// @Param limit query int false "Page size, 10 by default and at most 100"
 // @Produce json
 // @Success 200 {object} ratingResults
 // @Failure 400 {object} errorResponse
@@ -227,19 +244,22 @@ func getUserTVShowRatings(c *gin.Context, ratingService *features.RatingService)
 		c.JSON(400, errorResponse{Error: "userID is required"})
 		return
 	}
-	page, err := strconv.Atoi(c.Query("page"))
+	page, err := queryPage(c)
 	if err != nil {
-		page = 1
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
 	}
 
-	ratings, count, err := ratingService.GetUsersTvShowRatings(userID, page)
+	ratings, err := ratingService.GetUsersTvShowRatings(userID, page)
 	if err != nil {
-		c.JSON(500, errorResponse{Error: err.Error()})
+		pageError(c, err)
 		return
 	}
 	c.JSON(200, ratingResults{
-		Results:     toTVShowRatingsResponse(ratings),
-		TotalResult: count,
+		Results:     toTVShowRatingsResponse(ratings.Items),
+		TotalResult: ratings.Total,
+		Next:        cursorToken(ratings.Next),
+		HasNext:     ratings.Next != nil,
 	})
 }
 
@@ -1,36 +1,94 @@
 package controllers
 
 import (
+	"context"
+	"fmt"
 	objectstorage "github.com/bingemate/media-go-pkg/object-storage"
 	"github.com/bingemate/media-go-pkg/tmdb"
 	"github.com/bingemate/media-service/initializers"
 	"github.com/bingemate/media-service/internal/features"
 	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
-func InitRouter(engine *gin.Engine, db *gorm.DB, env initializers.Env) {
+// InitRouter registers the routes of the API on engine and starts the background jobs until ctx is done.
+// An error is returned if a service could not be initialized.
+func InitRouter(ctx context.Context, engine *gin.Engine, db *gorm.DB, env initializers.Env) error {
+	engine.Use(
+		otelgin.Middleware(env.TracingServiceName),
+		requestContextMiddleware(),
+		requestLoggerMiddleware(),
+		traceErrorMiddleware(),
+	)
 	var mediaServiceGroup = engine.Group("/media-service")
 	var mediaClient = tmdb.NewRedisMediaClient(env.TMDBApiKey, env.RedisHost, env.RedisPassword)
 	var mediaRepository = repository.NewMediaRepository(db)
 	var mediaData = features.NewMediaData(mediaClient, mediaRepository)
-	objectStorage, err := objectstorage.NewObjectStorage(env.S3AccessKeyId, env.S3SecretAccessKey, env.S3Endpoint, "fr-par", env.S3BucketName)
+	objectStorage, err := objectstorage.NewObjectStorage(env.S3AccessKeyId, env.S3SecretAccessKey, env.S3Endpoint, env.S3Region, env.S3BucketName)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error connecting to the object storage: %w", err)
 	}
-	var mediaFile = features.NewMediaFile(env.MovieTargetFolder, env.TvTargetFolder, mediaRepository, objectStorage)
-	var mediaDiscover = features.NewMediaDiscovery(mediaClient, mediaRepository)
+	var mediaFile = features.NewMediaFile(env.MovieTargetFolder, env.TvTargetFolder, mediaRepository, objectStorage, features.StorageThresholds{
+		LowPercent:      env.StorageLowPercent,
+		CriticalPercent: env.StorageCriticalPercent,
+	})
+	if err := mediaFile.ResumeDeletionJobs(ctx); err != nil {
+		return fmt.Errorf("error resuming the deletion jobs: %w", err)
+	}
+	mediaFile.StartStorageCheck(ctx, env.StorageCheckInterval)
+	var retention = features.NewRetention(mediaRepository, mediaFile, features.RetentionPolicy{
+		UnwatchedDays: env.RetentionUnwatchedDays,
+		KeepSeasons:   env.RetentionKeepSeasons,
+		ProtectRating: env.RetentionProtectRating,
+		Delete:        env.RetentionDelete,
+		Interval:      env.RetentionInterval,
+	})
+	retention.Start(ctx)
+	var duplicates = features.NewDuplicates(mediaRepository, mediaFile, env.DuplicatesInterval)
+	duplicates.Start(ctx)
+	bucket, err := storage.NewBucket(env.S3AccessKeyId, env.S3SecretAccessKey, env.S3Endpoint, env.S3Region, env.S3BucketName)
+	if err != nil {
+		return fmt.Errorf("error connecting to the bucket: %w", err)
+	}
+	var subtitles = features.NewSubtitles(mediaRepository, bucket)
+	var storageReconciliation = features.NewStorageReconciliation(mediaRepository, bucket)
+	var playbackGroup = mediaServiceGroup.Group("/playback")
+	var playback = features.NewPlayback(mediaRepository, bucket, env.PlaybackSigningKey, playbackGroup.BasePath()+"/playlist/", env.S3URLExpiry)
+	var suggestionIndex = features.NewSuggestionIndex(mediaRepository)
+	if err := suggestionIndex.Load(); err != nil {
+		return fmt.Errorf("error loading the suggestion index: %w", err)
+	}
+	var trendingIndex = features.NewTrendingIndex(mediaRepository)
+	if err := trendingIndex.Start(ctx); err != nil {
+		return fmt.Errorf("error loading the trending index: %w", err)
+	}
+	var recommendationIndex = features.NewRecommendationIndex(mediaRepository)
+	if err := recommendationIndex.Start(ctx); err != nil {
+		return fmt.Errorf("error loading the recommendation index: %w", err)
+	}
+	var mediaDiscover = features.NewMediaDiscovery(mediaClient, mediaRepository, suggestionIndex, trendingIndex, recommendationIndex)
 	var mediaAssetData = features.NewMediaAssetsData(mediaClient)
 	var mediaCalendar = features.NewCalendarService(mediaClient, mediaRepository)
 	var commentService = features.NewCommentService(mediaRepository)
 	var ratingService = features.NewRatingService(mediaRepository)
+	var progressService = features.NewWatchProgressService(mediaRepository)
+	var watchListService = features.NewWatchListService(mediaRepository)
 	InitMediaDataController(mediaServiceGroup.Group("/media"), mediaData)
-	InitFileInfoController(mediaServiceGroup.Group("/file"), mediaFile)
+	var fileGroup = mediaServiceGroup.Group("/file")
+	InitFileInfoController(fileGroup, mediaFile)
+	InitSubtitleController(fileGroup, subtitles, env.SubtitleUserUpload)
 	InitDiscoverController(mediaServiceGroup.Group("/discover"), mediaDiscover)
 	InitCalendarController(mediaServiceGroup.Group("/calendar"), mediaCalendar)
 	InitMediaAssetsController(mediaServiceGroup.Group("/assets"), mediaAssetData)
 	InitCommentController(mediaServiceGroup.Group("/comment"), commentService)
 	InitRatingController(mediaServiceGroup.Group("/rating"), ratingService)
+	InitProgressController(mediaServiceGroup.Group("/progress"), progressService)
+	InitWatchListController(mediaServiceGroup.Group("/watchlist"), watchListService)
+	InitPlaybackController(playbackGroup, playback)
+	InitAdminController(mediaServiceGroup.Group("/admin"), storageReconciliation, retention, duplicates)
 	InitPingController(mediaServiceGroup.Group("/ping"))
+	return nil
 }
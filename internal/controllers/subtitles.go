@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// maxSubtitleSize is the size of the largest subtitle file accepted, in bytes
+const maxSubtitleSize = 5 << 20
+
+// InitSubtitleController registers the subtitle routes of the media files, userUpload letting the users
+// upload subtitles, which only the admins can do otherwise. Replacing and deleting is reserved to the admins.
+func InitSubtitleController(engine *gin.RouterGroup, subtitles *features.Subtitles, userUpload bool) {
+	engine.GET(":id/subtitles", func(c *gin.Context) {
+		getSubtitles(c, subtitles.WithContext(c.Request.Context()))
+	})
+	engine.POST(":id/subtitles", func(c *gin.Context) {
+		uploadSubtitle(c, subtitles.WithContext(c.Request.Context()), userUpload)
+	})
+	engine.GET(":id/subtitles/:subtitleID/content", func(c *gin.Context) {
+		getSubtitleContent(c, subtitles.WithContext(c.Request.Context()))
+	})
+	engine.PUT(":id/subtitles/:subtitleID", adminMiddleware(), func(c *gin.Context) {
+		replaceSubtitle(c, subtitles.WithContext(c.Request.Context()))
+	})
+	engine.DELETE(":id/subtitles/:subtitleID", adminMiddleware(), func(c *gin.Context) {
+		deleteSubtitle(c, subtitles.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary Get file subtitles
+// @Description Get the subtitles of a file
+// @Tags File
+// @Param id path string true "File ID"
+// @Produce json
+// @Success 200 {array} subtitleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id}/subtitles [get]
+func getSubtitles(c *gin.Context, subtitles *features.Subtitles) {
+	fileID := c.Param("id")
+	if _, err := uuid.Parse(fileID); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "id must be a file ID",
+		})
+		return
+	}
+	result, err := subtitles.GetSubtitles(fileID)
+	if err != nil {
+		subtitleError(c, err)
+		return
+	}
+	c.JSON(200, toSubtitlesResponse(result))
+}
+
+// @Summary Get subtitle content
+// @Description Get a subtitle of a file converted to SRT or WebVTT, optionally shifted to fix its synchronization:
+// @Description the times are scaled by the source framerate over the target framerate, then moved by the offset.
+// @Description The cues ending before the start are removed.
+// @Tags File
+// @Param id path string true "File ID"
+// @Param subtitleID path string true "Subtitle ID"
+// @Param format query string false "Output format, srt or vtt (default)"
+// @Param offset query int false "Offset in milliseconds, negative to show the subtitles earlier"
+// @Param framerate query string false "Framerate the subtitle was made for and framerate of the video" example(23.976:25)
+// @Produce text/vtt
+// @Produce application/x-subrip
+// @Success 200 {string} string "Subtitle file"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id}/subtitles/{subtitleID}/content [get]
+func getSubtitleContent(c *gin.Context, subtitles *features.Subtitles) {
+	fileID, subtitleID, ok := subtitleParams(c)
+	if !ok {
+		return
+	}
+	format, err := features.ParseSubtitleFormat(c.DefaultQuery("format", "vtt"))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	shift, err := features.ParseSubtitleShift(c.Query("offset"), c.Query("framerate"))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	content, err := subtitles.GetSubtitleContent(fileID, subtitleID, format, shift)
+	if err != nil {
+		subtitleError(c, err)
+		return
+	}
+	contentType := "text/vtt; charset=utf-8"
+	if format == features.SubtitleSRT {
+		contentType = "application/x-subrip; charset=utf-8"
+	}
+	c.Data(200, contentType, content)
+}
+
+// @Summary Upload a subtitle
+// @Description Add a subtitle to a file. SRT, ASS, SSA and WebVTT files are accepted, the format being given or
+// @Description taken from the file extension, and stored as WebVTT. Reserved to the admins, unless the users are
+// @Description allowed to upload subtitles by the configuration.
+// @Tags File
+// @Param id path string true "File ID"
+// @Param roles header string false "User roles, bingemate-admin is required unless users can upload"
+// @Param user-id header string false "User ID, required if not admin"
+// @Accept multipart/form-data
+// @Param file formData file true "Subtitle file, 5 MB at most"
+// @Param language formData string true "ISO 639-2 language code" example(fre)
+// @Param format formData string false "Subtitle format: srt, ass, ssa or vtt"
+// @Produce json
+// @Success 201 {object} subtitleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id}/subtitles [post]
+func uploadSubtitle(c *gin.Context, subtitles *features.Subtitles, userUpload bool) {
+	isAdmin := strings.Contains(c.GetHeader("roles"), "bingemate-admin")
+	if !isAdmin && (!userUpload || c.GetHeader("user-id") == "") {
+		c.JSON(403, errorResponse{
+			Error: "admin role is required",
+		})
+		return
+	}
+	fileID := c.Param("id")
+	if _, err := uuid.Parse(fileID); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "id must be a file ID",
+		})
+		return
+	}
+	format, content, ok := subtitleUpload(c)
+	if !ok {
+		return
+	}
+	subtitle, err := subtitles.AddSubtitle(fileID, c.PostForm("language"), format, content)
+	if err != nil {
+		subtitleError(c, err)
+		return
+	}
+	c.JSON(201, toSubtitleResponse(*subtitle))
+}
+
+// @Summary Replace a subtitle
+// @Description Replace the content and the language of a subtitle of a file, see the upload. Reserved to the admins.
+// @Tags File
+// @Param id path string true "File ID"
+// @Param subtitleID path string true "Subtitle ID"
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Accept multipart/form-data
+// @Param file formData file true "Subtitle file, 5 MB at most"
+// @Param language formData string true "ISO 639-2 language code" example(fre)
+// @Param format formData string false "Subtitle format: srt, ass, ssa or vtt"
+// @Produce json
+// @Success 200 {object} subtitleResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id}/subtitles/{subtitleID} [put]
+func replaceSubtitle(c *gin.Context, subtitles *features.Subtitles) {
+	fileID, subtitleID, ok := subtitleParams(c)
+	if !ok {
+		return
+	}
+	format, content, ok := subtitleUpload(c)
+	if !ok {
+		return
+	}
+	subtitle, err := subtitles.ReplaceSubtitle(fileID, subtitleID, c.PostForm("language"), format, content)
+	if err != nil {
+		subtitleError(c, err)
+		return
+	}
+	c.JSON(200, toSubtitleResponse(*subtitle))
+}
+
+// @Summary Delete a subtitle
+// @Description Remove a subtitle from a file and delete it from the storage. Reserved to the admins.
+// @Tags File
+// @Param id path string true "File ID"
+// @Param subtitleID path string true "Subtitle ID"
+// @Param roles header string true "User roles, bingemate-admin is required"
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /file/{id}/subtitles/{subtitleID} [delete]
+func deleteSubtitle(c *gin.Context, subtitles *features.Subtitles) {
+	fileID, subtitleID, ok := subtitleParams(c)
+	if !ok {
+		return
+	}
+	if err := subtitles.DeleteSubtitle(fileID, subtitleID); err != nil {
+		subtitleError(c, err)
+		return
+	}
+	c.Status(204)
+}
+
+// subtitleParams returns the file and subtitle IDs of the path, responding 400 if they are invalid
+func subtitleParams(c *gin.Context) (string, string, bool) {
+	fileID := c.Param("id")
+	if _, err := uuid.Parse(fileID); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "id must be a file ID",
+		})
+		return "", "", false
+	}
+	subtitleID := c.Param("subtitleID")
+	if _, err := uuid.Parse(subtitleID); err != nil {
+		c.JSON(400, errorResponse{
+			Error: "subtitleID must be a subtitle ID",
+		})
+		return "", "", false
+	}
+	return fileID, subtitleID, true
+}
+
+// subtitleUpload returns the format and the content of the uploaded subtitle file, responding 400 if it is invalid
+func subtitleUpload(c *gin.Context) (features.SubtitleFormat, []byte, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: "file is required",
+		})
+		return "", nil, false
+	}
+	if file.Size > maxSubtitleSize {
+		c.JSON(400, errorResponse{
+			Error: "file must be 5 MB at most",
+		})
+		return "", nil, false
+	}
+	format, err := features.ParseSubtitleFormat(c.DefaultPostForm("format", filepath.Ext(file.Filename)))
+	if err != nil {
+		c.JSON(400, errorResponse{
+			Error: err.Error(),
+		})
+		return "", nil, false
+	}
+	reader, err := file.Open()
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return "", nil, false
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(io.LimitReader(reader, maxSubtitleSize))
+	if err != nil {
+		c.JSON(500, errorResponse{
+			Error: err.Error(),
+		})
+		return "", nil, false
+	}
+	return format, content, true
+}
+
+func subtitleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, features.ErrMediaNotFound), errors.Is(err, features.ErrSubtitleNotFound):
+		c.JSON(404, errorResponse{Error: err.Error()})
+	case errors.Is(err, features.ErrInvalidSubtitle), errors.Is(err, features.ErrInvalidSubtitleFormat),
+		errors.Is(err, features.ErrInvalidLanguage):
+		c.JSON(400, errorResponse{Error: err.Error()})
+	default:
+		c.JSON(500, errorResponse{Error: err.Error()})
+	}
+}
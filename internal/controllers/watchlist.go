@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/bingemate/media-service/internal/features"
+	"github.com/gin-gonic/gin"
+)
+
+func InitWatchListController(engine *gin.RouterGroup, watchListService *features.WatchListService) {
+	engine.GET("movie", func(c *gin.Context) {
+		getMovieWatchList(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.GET("movie/:mediaID", func(c *gin.Context) {
+		getMovieWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.PUT("movie/:mediaID", func(c *gin.Context) {
+		saveMovieWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.DELETE("movie/:mediaID", func(c *gin.Context) {
+		deleteMovieWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.GET("tv", func(c *gin.Context) {
+		getTvShowWatchList(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.GET("tv/:mediaID", func(c *gin.Context) {
+		getTvShowWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.PUT("tv/:mediaID", func(c *gin.Context) {
+		saveTvShowWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.DELETE("tv/:mediaID", func(c *gin.Context) {
+		deleteTvShowWatchListItem(c, watchListService.WithContext(c.Request.Context()))
+	})
+	engine.POST("import", func(c *gin.Context) {
+		importWatchList(c, watchListService.WithContext(c.Request.Context()))
+	})
+}
+
+// @Summary Get movie watch list
+// @Description Get the movies of the user's watch list, ordered by movie ID
+// @Tags WatchList
+// @Param user-id header string true "User ID"
+// @Param status query string false "Comma separated statuses (PLAN_TO_WATCH, WATCHING, FINISHED, ABANDONED), one of them is required"
+// @Param available query bool false "Only the movies available in the library"
+// @Produce json
+// @Success 200 {array} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/movie [get]
+func getMovieWatchList(c *gin.Context, watchListService *features.WatchListService) {
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "user-id header is required"})
+		return
+	}
+	items, err := watchListService.GetMovieWatchList(userID, queryList(c, "status"), c.Query("available") == "true")
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toMovieWatchListResponse(items))
+}
+
+// @Summary Get tv show watch list
+// @Description Get the tv shows of the user's watch list, ordered by tv show ID
+// @Tags WatchList
+// @Param user-id header string true "User ID"
+// @Param status query string false "Comma separated statuses (PLAN_TO_WATCH, WATCHING, FINISHED, ABANDONED), one of them is required"
+// @Param available query bool false "Only the tv shows having episodes in the library"
+// @Produce json
+// @Success 200 {array} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/tv [get]
+func getTvShowWatchList(c *gin.Context, watchListService *features.WatchListService) {
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "user-id header is required"})
+		return
+	}
+	items, err := watchListService.GetTvShowWatchList(userID, queryList(c, "status"), c.Query("available") == "true")
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toTvShowWatchListResponse(items))
+}
+
+// @Summary Get movie watch list item
+// @Description Get the status of a movie in the user's watch list
+// @Tags WatchList
+// @Param mediaID path int true "Movie ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 200 {object} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/movie/{mediaID} [get]
+func getMovieWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	item, err := watchListService.GetMovieWatchListItem(userID, mediaID)
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toMovieWatchListItemResponse(item))
+}
+
+// @Summary Get tv show watch list item
+// @Description Get the status of a tv show in the user's watch list
+// @Tags WatchList
+// @Param mediaID path int true "TV Show ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 200 {object} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/tv/{mediaID} [get]
+func getTvShowWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	item, err := watchListService.GetTvShowWatchListItem(userID, mediaID)
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toTvShowWatchListItemResponse(item))
+}
+
+// @Summary Save movie watch list item
+// @Description Add a movie to the user's watch list, or change its status
+// @Tags WatchList
+// @Param mediaID path int true "Movie ID"
+// @Param user-id header string true "User ID"
+// @Param item body watchListStatusRequest true "Status"
+// @Produce json
+// @Success 200 {object} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/movie/{mediaID} [put]
+func saveMovieWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	var request watchListStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
+	}
+	item, err := watchListService.SaveMovieWatchListItem(userID, mediaID, request.Status)
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toMovieWatchListItemResponse(item))
+}
+
+// @Summary Save tv show watch list item
+// @Description Add a tv show to the user's watch list, or change its status
+// @Tags WatchList
+// @Param mediaID path int true "TV Show ID"
+// @Param user-id header string true "User ID"
+// @Param item body watchListStatusRequest true "Status"
+// @Produce json
+// @Success 200 {object} watchListItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/tv/{mediaID} [put]
+func saveTvShowWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	var request watchListStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
+	}
+	item, err := watchListService.SaveTvShowWatchListItem(userID, mediaID, request.Status)
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, toTvShowWatchListItemResponse(item))
+}
+
+// @Summary Delete movie watch list item
+// @Description Remove a movie from the user's watch list
+// @Tags WatchList
+// @Param mediaID path int true "Movie ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/movie/{mediaID} [delete]
+func deleteMovieWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	if err := watchListService.DeleteMovieWatchListItem(userID, mediaID); err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.Status(204)
+}
+
+// @Summary Delete tv show watch list item
+// @Description Remove a tv show from the user's watch list
+// @Tags WatchList
+// @Param mediaID path int true "TV Show ID"
+// @Param user-id header string true "User ID"
+// @Produce json
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/tv/{mediaID} [delete]
+func deleteTvShowWatchListItem(c *gin.Context, watchListService *features.WatchListService) {
+	mediaID, userID, ok := mediaUserParams(c)
+	if !ok {
+		return
+	}
+	if err := watchListService.DeleteTvShowWatchListItem(userID, mediaID); err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.Status(204)
+}
+
+// @Summary Import watch list
+// @Description Add movies and tv shows to the user's watch list at once, updating the status of those already in it.
+// @Description Nothing is imported if an item is invalid.
+// @Tags WatchList
+// @Param user-id header string true "User ID"
+// @Param items body watchListImportRequest true "Items"
+// @Produce json
+// @Success 200 {object} watchListImportResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /watchlist/import [post]
+func importWatchList(c *gin.Context, watchListService *features.WatchListService) {
+	userID := c.GetHeader("user-id")
+	if userID == "" {
+		c.JSON(400, errorResponse{Error: "user-id header is required"})
+		return
+	}
+	var request watchListImportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, errorResponse{Error: err.Error()})
+		return
+	}
+	movies, tvShows, err := watchListService.ImportWatchList(userID, toWatchListEntries(request.Movies), toWatchListEntries(request.TvShows))
+	if err != nil {
+		watchListError(c, err)
+		return
+	}
+	c.JSON(200, watchListImportResponse{Movies: movies, TvShows: tvShows})
+}
+
+func watchListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, features.ErrNotInWatchList):
+		c.JSON(404, errorResponse{Error: err.Error()})
+	case errors.Is(err, features.ErrInvalidWatchListStatus), errors.Is(err, features.ErrInvalidWatchListImport):
+		c.JSON(400, errorResponse{Error: err.Error()})
+	default:
+		c.JSON(500, errorResponse{Error: err.Error()})
+	}
+}
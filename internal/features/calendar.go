@@ -1,19 +1,32 @@
 package features
 
 import (
+	"context"
 	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/logging"
 	"github.com/bingemate/media-service/internal/repository"
-	"log"
+	"github.com/bingemate/media-service/internal/tracing"
+	"log/slog"
 	"time"
 )
 
 type CalendarService struct {
 	mediaClient     tmdb.MediaClient
 	mediaRepository *repository.MediaRepository
+	logger          *slog.Logger
 }
 
 func NewCalendarService(mediaClient tmdb.MediaClient, mediaRepository *repository.MediaRepository) *CalendarService {
-	return &CalendarService{mediaClient, mediaRepository}
+	return &CalendarService{mediaClient, mediaRepository, slog.Default()}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (s *CalendarService) WithContext(ctx context.Context) *CalendarService {
+	return &CalendarService{
+		tracing.NewMediaClient(ctx, s.mediaClient),
+		s.mediaRepository.WithContext(ctx),
+		logging.FromContext(ctx),
+	}
 }
 
 func (s *CalendarService) GetMoviesCalendar(userID string, month int, year int) ([]*tmdb.Movie, *[]bool, error) {
@@ -26,7 +39,7 @@ func (s *CalendarService) GetMoviesCalendar(userID string, month int, year int)
 	var presence []bool
 	movies, err := s.mediaClient.GetMoviesReleases(*followedReleases, startOfMonth, endOfMonth)
 	if err != nil {
-		log.Println(err)
+		s.logger.Error("error getting movies releases", "error", err)
 		return nil, nil, err
 	}
 	presence = make([]bool, len(movies))
@@ -45,7 +58,7 @@ func (s *CalendarService) GetTvShowCalendar(userID string, month int, year int)
 	}
 	episodes, tvShows, err := s.mediaClient.GetTVShowsReleases(*followedReleases, startOfMonth, endOfMonth)
 	if err != nil {
-		log.Println(err)
+		s.logger.Error("error getting tv shows releases", "error", err)
 		return nil, nil, nil, err
 	}
 	presence := make([]bool, len(episodes))
@@ -64,7 +77,7 @@ func (s *CalendarService) GetMoviesCalendarInRange(userID string, start time.Tim
 	var presence []bool
 	movies, err := s.mediaClient.GetMoviesReleases(*followedReleases, start, end)
 	if err != nil {
-		log.Println(err)
+		s.logger.Error("error getting movies releases", "error", err)
 		return nil, nil, err
 	}
 	presence = make([]bool, len(movies))
@@ -81,7 +94,7 @@ func (s *CalendarService) GetTvShowCalendarInRange(userID string, start time.Tim
 	}
 	episodes, tvShows, err := s.mediaClient.GetTVShowsReleases(*followedReleases, start, end)
 	if err != nil {
-		log.Println(err)
+		s.logger.Error("error getting tv shows releases", "error", err)
 		return nil, nil, nil, err
 	}
 	presence := make([]bool, len(episodes))
@@ -1,6 +1,7 @@
 package features
 
 import (
+	"context"
 	"fmt"
 	repository2 "github.com/bingemate/media-go-pkg/repository"
 	"github.com/bingemate/media-service/internal/repository"
@@ -15,28 +16,37 @@ func NewCommentService(mediaRepository *repository.MediaRepository) *CommentServ
 	return &CommentService{mediaRepository}
 }
 
+// WithContext returns a copy of the service bound to the given request context
+func (s *CommentService) WithContext(ctx context.Context) *CommentService {
+	return &CommentService{s.mediaRepository.WithContext(ctx)}
+}
+
 //func (s *CommentService) GetComments(mediaID, page int) ([]*repository2.Comment, int, error) {
 //	return s.mediaRepository.GetMediaComments(mediaID, 5, page)
 //}
 
-func (s *CommentService) GetMovieComments(movieID, page int) ([]*repository2.MovieComment, int, error) {
-	return s.mediaRepository.GetMovieComments(movieID, 5, page)
+// GetMovieComments returns a page of the comments of a movie, the most recent first
+func (s *CommentService) GetMovieComments(movieID int, page repository.Page) (*repository.CursorPage[*repository2.MovieComment], error) {
+	return s.mediaRepository.GetMovieComments(movieID, withPageSize(page, 5))
 }
 
-func (s *CommentService) GetTvShowComments(tvShowID, page int) ([]*repository2.TvShowComment, int, error) {
-	return s.mediaRepository.GetTvShowComments(tvShowID, 5, page)
+// GetTvShowComments returns a page of the comments of a tv show, the most recent first
+func (s *CommentService) GetTvShowComments(tvShowID int, page repository.Page) (*repository.CursorPage[*repository2.TvShowComment], error) {
+	return s.mediaRepository.GetTvShowComments(tvShowID, withPageSize(page, 5))
 }
 
 //func (s *CommentService) GetUserComments(userID string, page int) ([]*repository2.Comment, int, error) {
 //	return s.mediaRepository.GetUserComments(userID, 5, page)
 //}
 
-func (s *CommentService) GetMovieUserComments(userID string, page int) ([]*repository2.MovieComment, int, error) {
-	return s.mediaRepository.GetUserMovieComments(userID, 5, page)
+// GetMovieUserComments returns a page of the movie comments of a user, the most recent first
+func (s *CommentService) GetMovieUserComments(userID string, page repository.Page) (*repository.CursorPage[*repository2.MovieComment], error) {
+	return s.mediaRepository.GetUserMovieComments(userID, withPageSize(page, 5))
 }
 
-func (s *CommentService) GetTvShowUserComments(userID string, page int) ([]*repository2.TvShowComment, int, error) {
-	return s.mediaRepository.GetUserTvShowComments(userID, 5, page)
+// GetTvShowUserComments returns a page of the tv show comments of a user, the most recent first
+func (s *CommentService) GetTvShowUserComments(userID string, page repository.Page) (*repository.CursorPage[*repository2.TvShowComment], error) {
+	return s.mediaRepository.GetUserTvShowComments(userID, withPageSize(page, 5))
 }
 
 //func (s *CommentService) AddComment(userID string, mediaID int, comment string) (*repository2.Comment, error) {
@@ -123,30 +133,6 @@ func (s *CommentService) UpdateTvShowComment(commentID, userID string, isAdmin b
 	return s.mediaRepository.UpdateTvShowComment(commentID, content)
 }
 
-func (s *CommentService) GetUserMovieCommentsByRange(userID string, start, end string) ([]*repository2.MovieComment, error) {
-	startTime, err := time.Parse("2006-01-02", start)
-	if err != nil {
-		return nil, err
-	}
-	endTime, err := time.Parse("2006-01-02", end)
-	if err != nil {
-		return nil, err
-	}
-	return s.mediaRepository.GetUserMovieCommentsByRange(userID, startTime, endTime)
-}
-
-func (s *CommentService) GetUserTvShowCommentsByRange(userID string, start, end string) ([]*repository2.TvShowComment, error) {
-	startTime, err := time.Parse("2006-01-02", start)
-	if err != nil {
-		return nil, err
-	}
-	endTime, err := time.Parse("2006-01-02", end)
-	if err != nil {
-		return nil, err
-	}
-	return s.mediaRepository.GetUserTvShowCommentsByRange(userID, startTime, endTime)
-}
-
 func (s *CommentService) CountUserComments(userID string) (int, error) {
 	movieCommentsCount, err := s.mediaRepository.CountUserMovieComments(userID)
 	if err != nil {
@@ -159,38 +145,47 @@ func (s *CommentService) CountUserComments(userID string) (int, error) {
 	return movieCommentsCount + tvShowCommentsCount, nil
 }
 
-func (s *CommentService) GetMovieCommentsByRange(start, end string) ([]*repository2.MovieComment, error) {
-	startTime, err := time.Parse("2006-01-02", start)
+func (s *CommentService) CountComments() (int, error) {
+	movieCommentsCount, err := s.mediaRepository.CountMovieComments()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	endTime, err := time.Parse("2006-01-02", end)
+	tvShowCommentsCount, err := s.mediaRepository.CountTvShowComments()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return s.mediaRepository.GetMovieCommentsByRange(startTime, endTime)
+	return movieCommentsCount + tvShowCommentsCount, nil
 }
 
-func (s *CommentService) GetTvShowCommentsByRange(start, end string) ([]*repository2.TvShowComment, error) {
-	startTime, err := time.Parse("2006-01-02", start)
+// GetCommentHistory returns a page of the days from start to end (YYYY-MM-DD, both included) having comments,
+// with their number of comments, the most recent first. The comments are the ones of userID, or of every user
+// if it is empty.
+func (s *CommentService) GetCommentHistory(userID, start, end string, page repository.Page) (*repository.CursorPage[*repository.CommentDay], error) {
+	startTime, endTime, err := parseCommentRange(start, end)
 	if err != nil {
 		return nil, err
 	}
-	endTime, err := time.Parse("2006-01-02", end)
+	return s.mediaRepository.GetCommentHistory(userID, startTime, endTime, withPageSize(page, 31))
+}
+
+// GetCommentDays returns all the days from start to end (YYYY-MM-DD, both included) having comments, with their
+// number of comments, the oldest first. The comments are the ones of userID, or of every user if it is empty.
+func (s *CommentService) GetCommentDays(userID, start, end string) ([]*repository.CommentDay, error) {
+	startTime, endTime, err := parseCommentRange(start, end)
 	if err != nil {
 		return nil, err
 	}
-	return s.mediaRepository.GetTvShowCommentsByRange(startTime, endTime)
+	return s.mediaRepository.GetCommentDays(userID, startTime, endTime)
 }
 
-func (s *CommentService) CountComments() (int, error) {
-	movieCommentsCount, err := s.mediaRepository.CountMovieComments()
+func parseCommentRange(start, end string) (time.Time, time.Time, error) {
+	startTime, err := time.Parse("2006-01-02", start)
 	if err != nil {
-		return 0, err
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: start %q is not a YYYY-MM-DD date", ErrInvalidDate, start)
 	}
-	tvShowCommentsCount, err := s.mediaRepository.CountTvShowComments()
+	endTime, err := time.Parse("2006-01-02", end)
 	if err != nil {
-		return 0, err
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: end %q is not a YYYY-MM-DD date", ErrInvalidDate, end)
 	}
-	return movieCommentsCount + tvShowCommentsCount, nil
+	return startTime, endTime, nil
 }
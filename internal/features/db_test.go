@@ -0,0 +1,64 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/migrations"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testDBEnv names the environment variable holding the DSN of the PostgreSQL database of the tests
+const testDBEnv = "TEST_DB_DSN"
+
+// newTestDB returns a transaction on a throwaway schema of the test database, migrated and rolled back at the end
+// of the test, see the repository tests. The test is skipped when no test database is configured.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(testDBEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set, skipping the database test (make test-db runs it)", testDBEnv)
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("error connecting to the test database: %v", err)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	for _, statement := range []string{
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`,
+		`CREATE EXTENSION IF NOT EXISTS "unaccent" SCHEMA public`,
+		`CREATE EXTENSION IF NOT EXISTS "pg_trgm" SCHEMA public`,
+		"CREATE SCHEMA " + schema,
+		"SET LOCAL search_path TO " + schema + ", public",
+	} {
+		if err := tx.Exec(statement).Error; err != nil {
+			t.Fatalf("error preparing the test schema: %v", err)
+		}
+	}
+	migrator, err := migrations.NewMigrator(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("error migrating the test schema: %v", err)
+	}
+	return tx
+}
+
+// mustExec runs a statement of a test fixture
+func mustExec(t *testing.T, db *gorm.DB, sql string, values ...any) {
+	t.Helper()
+	if err := db.Exec(sql, values...).Error; err != nil {
+		t.Fatalf("error running %q: %v", sql, err)
+	}
+}
@@ -0,0 +1,78 @@
+package features
+
+import (
+	"context"
+	"github.com/bingemate/media-service/internal/repository"
+	"time"
+)
+
+const (
+	// maxDeletionAttempts is the number of times the objects of a media file are deleted before its job fails
+	maxDeletionAttempts = 5
+	// deletionRetryDelay is the wait before the second attempt, doubled after each failed attempt
+	deletionRetryDelay = 2 * time.Second
+)
+
+// ResumeDeletionJobs runs again in the background the deletion jobs left pending or running by a previous run.
+// These jobs and the ones started afterwards stop waiting for their next attempt once ctx is done, to be resumed
+// at the next start.
+func (m *MediaFile) ResumeDeletionJobs(ctx context.Context) error {
+	m.jobs = ctx
+	jobs, err := m.jobRepository.GetActiveDeletionJobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		go m.runDeletionJob(ctx, job)
+	}
+	return nil
+}
+
+// runDeletionJob deletes the objects of a media file from the storage, retrying on failure, then removes its row.
+// The file is kept if its objects could not be deleted, the job recording the last error. The job is left running
+// if ctx is done before its next attempt.
+func (m *MediaFile) runDeletionJob(ctx context.Context, job *repository.DeletionJob) {
+	logger := m.logger.With("job_id", job.ID, "media_file_id", job.MediaFileID)
+	job.Status = repository.DeletionRunning
+	if err := m.jobRepository.UpdateDeletionJob(job); err != nil {
+		logger.Error("error starting deletion job", "error", err)
+		return
+	}
+	for job.Prefix != "" {
+		job.Attempts++
+		err := m.objectStorage.DeleteMediaFiles(job.Prefix)
+		if err == nil {
+			break
+		}
+		logger.Warn("error deleting media file objects", "prefix", job.Prefix, "attempt", job.Attempts, "error", err)
+		job.Error = err.Error()
+		if job.Attempts >= maxDeletionAttempts {
+			job.Status = repository.DeletionFailed
+		}
+		if err := m.jobRepository.UpdateDeletionJob(job); err != nil {
+			logger.Error("error saving deletion job", "error", err)
+			return
+		}
+		if job.Status == repository.DeletionFailed {
+			return
+		}
+		retry := time.NewTimer(deletionRetryDelay << (job.Attempts - 1))
+		select {
+		case <-ctx.Done():
+			retry.Stop()
+			logger.Info("Deletion job interrupted", "prefix", job.Prefix, "attempts", job.Attempts)
+			return
+		case <-retry.C:
+		}
+	}
+	if err := m.jobRepository.CompleteDeletionJob(job); err != nil {
+		logger.Error("error deleting media file", "error", err)
+		job.Status = repository.DeletionFailed
+		job.Error = err.Error()
+		if err := m.jobRepository.UpdateDeletionJob(job); err != nil {
+			logger.Error("error saving deletion job", "error", err)
+		}
+		return
+	}
+	logger.Info("Media file deleted", "prefix", job.Prefix, "attempts", job.Attempts)
+}
@@ -0,0 +1,252 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DuplicateGroup is a movie or an episode with the copies of its file: the media files no media uses having the
+// same filename, size and duration as its file, LinkedFileID, the oldest first. A file no media uses does not
+// record the media it was ingested for, so its content is the only way to tell which media it is a copy of.
+// The copies matching the file of no media, or the files of several media, make a group without media, Type,
+// MediaID and LinkedFileID being empty. As every copy of a media is stored in its directory, only one of them
+// keeps its objects.
+type DuplicateGroup struct {
+	Type         repository.SavedMediaType
+	MediaID      int
+	Name         string
+	Season       int
+	Episode      int
+	Filename     string
+	Size         int64
+	Duration     float64
+	LinkedFileID string
+	Files        []repository.DuplicateFile
+}
+
+// ReclaimableSize is the size freed by keeping a single copy
+func (g *DuplicateGroup) ReclaimableSize() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// DuplicateReport lists the groups of duplicated media files, ReclaimableSize being the size freed by keeping
+// one copy of each. Pending counts the copies skipped as they are already being deleted.
+type DuplicateReport struct {
+	ScannedAt       time.Time
+	Groups          []DuplicateGroup
+	ReclaimableSize int64
+	Pending         int
+}
+
+// Duplicates detects the media files ingested several times and deletes the extra copies, through the deletion
+// jobs of MediaFile. The report of the last detection is kept in memory.
+type Duplicates struct {
+	mediaRepository *repository.MediaRepository
+	mediaFile       *MediaFile
+	interval        time.Duration
+	detections      *duplicateDetections // Shared by the copies bound to a request context.
+	logger          *slog.Logger
+}
+
+// duplicateDetections serializes the detections and resolutions of the duplicates and keeps the last report
+type duplicateDetections struct {
+	mutex      sync.Mutex
+	lastReport *DuplicateReport
+}
+
+func NewDuplicates(mediaRepository *repository.MediaRepository, mediaFile *MediaFile, interval time.Duration) *Duplicates {
+	return &Duplicates{
+		mediaRepository: mediaRepository,
+		mediaFile:       mediaFile,
+		interval:        interval,
+		detections:      &duplicateDetections{},
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (d *Duplicates) WithContext(ctx context.Context) *Duplicates {
+	return &Duplicates{
+		mediaRepository: d.mediaRepository.WithContext(ctx),
+		mediaFile:       d.mediaFile.WithContext(ctx),
+		interval:        d.interval,
+		detections:      d.detections,
+		logger:          logging.FromContext(ctx),
+	}
+}
+
+// Start detects the duplicates in the background now, then every interval until ctx is done
+func (d *Duplicates) Start(ctx context.Context) {
+	detect := func() {
+		report, err := d.Detect()
+		if err != nil {
+			d.logger.Error("error detecting duplicated media files", "error", err)
+			return
+		}
+		if len(report.Groups) > 0 {
+			d.logger.Warn("duplicated media files found", "groups", len(report.Groups),
+				"reclaimable_size", report.ReclaimableSize)
+		}
+	}
+	go func() {
+		detect()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				detect()
+			}
+		}
+	}()
+}
+
+// LastReport returns the report of the last detection, nil if the duplicates were not detected yet
+func (d *Duplicates) LastReport() *DuplicateReport {
+	d.detections.mutex.Lock()
+	defer d.detections.mutex.Unlock()
+	return d.detections.lastReport
+}
+
+// Detect groups the duplicated media files now and keeps the report
+func (d *Duplicates) Detect() (*DuplicateReport, error) {
+	d.detections.mutex.Lock()
+	defer d.detections.mutex.Unlock()
+	report, err := d.detect()
+	if err != nil {
+		return nil, err
+	}
+	d.detections.lastReport = report
+	return report, nil
+}
+
+// DuplicateResolution is the outcome of keeping a copy of a duplicated media file. Relinked tells whether the media
+// was made to use the kept copy, Jobs lists the deletion jobs started for the other copies and Errors the
+// deletions which could not start, the copies being left to a later resolution.
+type DuplicateResolution struct {
+	Group    DuplicateGroup
+	Keep     string
+	Relinked bool
+	Jobs     []*repository.DeletionJob
+	Errors   []string
+}
+
+// Resolve keeps a copy of a duplicated media file and deletes the others. When a media uses another copy, it is
+// made to use the kept one first, so the deleted copies no longer own the objects of its directory.
+// Nothing is deleted if the media could not be relinked, while a copy whose deletion could not start is reported
+// among the errors of the resolution, the other deletions going on.
+func (d *Duplicates) Resolve(keepFileID string) (*DuplicateResolution, error) {
+	d.detections.mutex.Lock()
+	defer d.detections.mutex.Unlock()
+	report, err := d.detect()
+	if err != nil {
+		return nil, err
+	}
+	d.detections.lastReport = report
+	for _, group := range report.Groups {
+		if !slices.ContainsFunc(group.Files, func(file repository.DuplicateFile) bool {
+			return file.MediaFileID == keepFileID
+		}) {
+			continue
+		}
+		resolution := &DuplicateResolution{Group: group, Keep: keepFileID}
+		if group.LinkedFileID != "" && group.LinkedFileID != keepFileID {
+			if err := d.mediaRepository.LinkMediaFile(group.Type, group.MediaID, keepFileID); err != nil {
+				return nil, err
+			}
+			resolution.Relinked = true
+		}
+		for _, file := range group.Files {
+			if file.MediaFileID == keepFileID {
+				continue
+			}
+			job, err := d.mediaFile.DeleteMediaFile(file.MediaFileID)
+			if err != nil {
+				d.logger.Error("error deleting duplicated media file", "media_file_id", file.MediaFileID, "error", err)
+				resolution.Errors = append(resolution.Errors, fmt.Sprintf("%s: %v", file.MediaFileID, err))
+				continue
+			}
+			resolution.Jobs = append(resolution.Jobs, job)
+		}
+		return resolution, nil
+	}
+	return nil, ErrDuplicateNotFound
+}
+
+// detect groups the duplicated files of the library, see groupDuplicates
+func (d *Duplicates) detect() (*DuplicateReport, error) {
+	files, err := d.mediaRepository.GetDuplicateFiles()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := d.mediaRepository.GetActiveDeletionJobs()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		pending[job.MediaFileID] = true
+	}
+	return groupDuplicates(files, pending, time.Now()), nil
+}
+
+// groupDuplicates groups the files sorted by content, as GetDuplicateFiles returns them, by the media they are
+// copies of, see DuplicateGroup. The files in pending are skipped as they are already being deleted.
+func groupDuplicates(files []repository.DuplicateFile, pending map[string]bool, now time.Time) *DuplicateReport {
+	report := &DuplicateReport{ScannedAt: now}
+	for start := 0; start < len(files); {
+		end := start + 1
+		for end < len(files) && sameContent(files[start], files[end]) {
+			end++
+		}
+		var linked, unlinked []repository.DuplicateFile
+		for _, file := range files[start:end] {
+			switch {
+			case pending[file.MediaFileID]:
+				report.Pending++
+			case file.MediaID == 0:
+				unlinked = append(unlinked, file)
+			default:
+				linked = append(linked, file)
+			}
+		}
+		start = end
+
+		// the files of several media are not copies of each other, the unlinked ones cannot be attributed
+		group := DuplicateGroup{Files: unlinked}
+		if len(linked) == 1 {
+			group.Files = append(group.Files, linked[0])
+			group.Type = linked[0].Type
+			group.MediaID = linked[0].MediaID
+			group.Name = linked[0].Name
+			group.Season = linked[0].Season
+			group.Episode = linked[0].Episode
+			group.LinkedFileID = linked[0].MediaFileID
+		}
+		if len(group.Files) < 2 {
+			continue
+		}
+		slices.SortFunc(group.Files, func(a, b repository.DuplicateFile) int {
+			return a.AddedAt.Compare(b.AddedAt)
+		})
+		group.Filename = group.Files[0].Filename
+		group.Size = group.Files[0].Size
+		group.Duration = group.Files[0].Duration
+		report.Groups = append(report.Groups, group)
+		report.ReclaimableSize += group.ReclaimableSize()
+	}
+	return report
+}
+
+// sameContent reports whether two files have the same filename, size and duration
+func sameContent(a, b repository.DuplicateFile) bool {
+	return a.Filename == b.Filename && a.Size == b.Size && a.Duration == b.Duration
+}
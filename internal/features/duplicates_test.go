@@ -0,0 +1,79 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupDuplicates(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	file := func(id, filename string, size int64, age int, mediaType repository.SavedMediaType, mediaID int) repository.DuplicateFile {
+		return repository.DuplicateFile{
+			MediaFileID: id,
+			Filename:    filename,
+			Size:        size,
+			Duration:    60,
+			AddedAt:     now.Add(-time.Duration(age) * time.Hour),
+			Type:        mediaType,
+			MediaID:     mediaID,
+		}
+	}
+	// sorted by content as GetDuplicateFiles returns them
+	files := []repository.DuplicateFile{
+		// a movie with the copies left by its ingestions, one being deleted
+		file("movie old copy", "movie.m3u8", 300, 3, "", 0),
+		file("movie copy", "movie.m3u8", 300, 2, "", 0),
+		file("movie file", "movie.m3u8", 300, 1, repository.SavedMovie, 603),
+		file("movie deleted copy", "movie.m3u8", 300, 0, "", 0),
+		// two episodes with the same content, which are not copies of each other, and a copy of one of them
+		file("episode 1 file", "episode.m3u8", 200, 3, repository.SavedEpisode, 1),
+		file("episode 2 file", "episode.m3u8", 200, 2, repository.SavedEpisode, 2),
+		file("episode copy", "episode.m3u8", 200, 1, "", 0),
+		// the same filename with another size is another content
+		file("other size file", "index.m3u8", 150, 2, repository.SavedMovie, 604),
+		// copies of no media
+		file("orphan", "index.m3u8", 100, 2, "", 0),
+		file("other orphan", "index.m3u8", 100, 1, "", 0),
+		// a single file once its copy is being deleted
+		file("show file", "show.m3u8", 50, 2, repository.SavedEpisode, 3),
+		file("show deleted copy", "show.m3u8", 50, 1, "", 0),
+	}
+	pending := map[string]bool{"movie deleted copy": true, "show deleted copy": true}
+
+	report := groupDuplicates(files, pending, now)
+	type group struct {
+		mediaType repository.SavedMediaType
+		mediaID   int
+		linked    string
+		files     []string
+	}
+	var got []group
+	for _, duplicates := range report.Groups {
+		g := group{duplicates.Type, duplicates.MediaID, duplicates.LinkedFileID, nil}
+		for _, file := range duplicates.Files {
+			g.files = append(g.files, file.MediaFileID)
+		}
+		got = append(got, g)
+	}
+	want := []group{
+		{repository.SavedMovie, 603, "movie file", []string{"movie old copy", "movie copy", "movie file"}},
+		{"", 0, "", []string{"orphan", "other orphan"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %+v, want %+v", got, want)
+	}
+	if report.Pending != 2 {
+		t.Errorf("pending = %d, want 2", report.Pending)
+	}
+	if report.ReclaimableSize != 2*300+100 {
+		t.Errorf("reclaimable size = %d, want %d", report.ReclaimableSize, 2*300+100)
+	}
+	if movie := report.Groups[0]; movie.Filename != "movie.m3u8" || movie.Size != 300 || movie.Duration != 60 {
+		t.Errorf("movie group content = %s, %d, %v, want movie.m3u8, 300, 60", movie.Filename, movie.Size, movie.Duration)
+	}
+	if !report.ScannedAt.Equal(now) {
+		t.Errorf("scanned at %v, want %v", report.ScannedAt, now)
+	}
+}
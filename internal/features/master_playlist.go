@@ -0,0 +1,173 @@
+package features
+
+import (
+	"fmt"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// subtitlePlaylistSuffix follows the key of a subtitle to name the media playlist generated for it
+	subtitlePlaylistSuffix = ".m3u8"
+	// masterBandwidth is the peak bit rate of a rendition: the transcoder caps the video at 3 Mb/s
+	// and encodes the audios at 160 kb/s
+	masterBandwidth = 3_000_000 + 160_000
+	// masterCodecs are the codecs of the transcoder, H.264 High 4.0 and AAC-LC
+	masterCodecs = "avc1.640028,mp4a.40.2"
+)
+
+// GetMovieMasterPlaylist returns the HLS master playlist of a movie, see masterPlaylist
+func (p *Playback) GetMovieMasterPlaylist(movieID int, preferred string) ([]byte, error) {
+	prefix, file, err := p.movieFile(movieID)
+	if err != nil {
+		return nil, err
+	}
+	return p.masterPlaylist(prefix, file, preferred), nil
+}
+
+// GetEpisodeMasterPlaylist returns the HLS master playlist of an episode, see masterPlaylist
+func (p *Playback) GetEpisodeMasterPlaylist(episodeID int, preferred string) ([]byte, error) {
+	prefix, file, err := p.episodeFile(episodeID)
+	if err != nil {
+		return nil, err
+	}
+	return p.masterPlaylist(prefix, file, preferred), nil
+}
+
+// masterPlaylist returns an HLS master playlist with an audio rendition per audio and a WebVTT rendition per
+// subtitle of a media file, all URIs being signed playlist URLs. The audio in the preferred language, else the
+// first one, is the default. Without audio in the preferred language, the subtitle in it is the default.
+func (p *Playback) masterPlaylist(prefix string, file *repository2.MediaFile, preferred string) []byte {
+	expiresAt := time.Now().Add(p.expiry).Truncate(time.Second)
+	preferredTag, _ := language.Parse(preferred)
+
+	defaultAudio := 0
+	for i, audio := range file.Audios {
+		if sameLanguage(audio.Language, preferredTag) {
+			defaultAudio = i
+			break
+		}
+	}
+	defaultSubtitle := -1
+	if len(file.Audios) == 0 || !sameLanguage(file.Audios[defaultAudio].Language, preferredTag) {
+		for i, subtitle := range file.Subtitles {
+			if sameLanguage(subtitle.Language, preferredTag) {
+				defaultSubtitle = i
+				break
+			}
+		}
+	}
+
+	var playlist strings.Builder
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-INDEPENDENT-SEGMENTS\n")
+	names := map[string]bool{}
+	for i, audio := range file.Audios {
+		playlist.WriteString("#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID=\"audio\"")
+		writeRendition(&playlist, audio.Language, names, i == defaultAudio)
+		fmt.Fprintf(&playlist, ",URI=%q\n", p.playlistURL(path.Join(prefix, audio.Filename), expiresAt))
+	}
+	names = map[string]bool{}
+	for i, subtitle := range file.Subtitles {
+		playlist.WriteString("#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID=\"subtitles\"")
+		writeRendition(&playlist, subtitle.Language, names, i == defaultSubtitle)
+		fmt.Fprintf(&playlist, ",FORCED=NO,URI=%q\n", p.playlistURL(path.Join(prefix, subtitle.Filename)+subtitlePlaylistSuffix, expiresAt))
+	}
+	fmt.Fprintf(&playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=%q", masterBandwidth, masterCodecs)
+	if len(file.Audios) > 0 {
+		playlist.WriteString(",AUDIO=\"audio\"")
+	}
+	if len(file.Subtitles) > 0 {
+		playlist.WriteString(",SUBTITLES=\"subtitles\"")
+	}
+	fmt.Fprintf(&playlist, "\n%s\n", p.playlistURL(path.Join(prefix, file.Filename), expiresAt))
+	return []byte(playlist.String())
+}
+
+// writeRendition writes the NAME, LANGUAGE, DEFAULT and AUTOSELECT attributes of a rendition. The stored languages
+// being ISO 639-2 codes, they are written as BCP 47 tags, and named in their own language. The names of a group
+// must be unique, so a number is appended to the names already used.
+func writeRendition(playlist *strings.Builder, code string, names map[string]bool, isDefault bool) {
+	name := code
+	tag, err := language.Parse(code)
+	if err == nil && tag != language.Und {
+		if self := display.Self.Name(tag); self != "" {
+			name = self
+		}
+	}
+	if name == "" {
+		name = "und"
+	}
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = name + " " + strconv.Itoa(i)
+	}
+	names[unique] = true
+	fmt.Fprintf(playlist, ",NAME=%q", unique)
+	if err == nil && tag != language.Und {
+		fmt.Fprintf(playlist, ",LANGUAGE=%q", tag.String())
+	}
+	if isDefault {
+		playlist.WriteString(",DEFAULT=YES,AUTOSELECT=YES")
+	} else {
+		playlist.WriteString(",DEFAULT=NO,AUTOSELECT=YES")
+	}
+}
+
+// sameLanguage reports whether a stored language code has the base language of the preferred tag
+func sameLanguage(code string, preferred language.Tag) bool {
+	if preferred == language.Und {
+		return false
+	}
+	tag, err := language.Parse(code)
+	if err != nil {
+		return false
+	}
+	base, _ := tag.Base()
+	preferredBase, _ := preferred.Base()
+	return base == preferredBase
+}
+
+// subtitlePlaylist returns a media playlist made of the single WebVTT file at key, presigned until expiresAt,
+// as HLS renditions are playlists and not subtitle files
+func (p *Playback) subtitlePlaylist(key string, expiresAt time.Time) ([]byte, error) {
+	file, err := p.keyFile(key)
+	if err != nil {
+		return nil, err
+	}
+	presigned, err := p.bucket.PresignGet(key, time.Until(expiresAt))
+	if err != nil {
+		return nil, err
+	}
+	playlist := fmt.Sprintf("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:%d\n#EXT-X-MEDIA-SEQUENCE:0\n"+
+		"#EXT-X-PLAYLIST-TYPE:VOD\n#EXTINF:%.3f,\n%s\n#EXT-X-ENDLIST\n",
+		int(math.Ceil(file.Duration)), file.Duration, presigned)
+	return []byte(playlist), nil
+}
+
+// keyFile returns the media file owning the object at key, movies/{id}/... or tv-shows/{id}/...
+func (p *Playback) keyFile(key string) (*repository2.MediaFile, error) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 3 {
+		return nil, ErrMediaNotFound
+	}
+	mediaID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrMediaNotFound
+	}
+	var file *repository2.MediaFile
+	switch parts[0] {
+	case movieStoragePrefix:
+		_, file, err = p.movieFile(mediaID)
+	case tvShowStoragePrefix:
+		_, file, err = p.episodeFile(mediaID)
+	default:
+		err = ErrMediaNotFound
+	}
+	return file, err
+}
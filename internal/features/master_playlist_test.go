@@ -0,0 +1,111 @@
+package features
+
+import (
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRendition(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		isDefault bool
+		want      string
+	}{
+		{"ISO 639-2/B code", "fre", false, `,NAME="français",LANGUAGE="fr",DEFAULT=NO,AUTOSELECT=YES`},
+		{"ISO 639-2/T code", "deu", false, `,NAME="Deutsch",LANGUAGE="de",DEFAULT=NO,AUTOSELECT=YES`},
+		{"default", "eng", true, `,NAME="English",LANGUAGE="en",DEFAULT=YES,AUTOSELECT=YES`},
+		{"undetermined", "und", false, `,NAME="und",DEFAULT=NO,AUTOSELECT=YES`},
+		{"empty", "", false, `,NAME="und",DEFAULT=NO,AUTOSELECT=YES`},
+		{"invalid", "xx1", false, `,NAME="xx1",DEFAULT=NO,AUTOSELECT=YES`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var playlist strings.Builder
+			writeRendition(&playlist, test.code, map[string]bool{}, test.isDefault)
+			if got := playlist.String(); got != test.want {
+				t.Errorf("writeRendition(%q) = %s, want %s", test.code, got, test.want)
+			}
+		})
+	}
+
+	names := map[string]bool{}
+	var playlist strings.Builder
+	for _, code := range []string{"fre", "fra", "fre"} {
+		writeRendition(&playlist, code, names, false)
+	}
+	for _, name := range []string{`NAME="français"`, `NAME="français 2"`, `NAME="français 3"`} {
+		if !strings.Contains(playlist.String(), name+",") {
+			t.Errorf("renditions %s do not contain %s", playlist.String(), name)
+		}
+	}
+}
+
+func TestMasterPlaylist(t *testing.T) {
+	playback := NewPlayback(nil, nil, "signing key", "/playback/playlist/", time.Hour)
+	file := &repository2.MediaFile{
+		Filename: "video.m3u8",
+		Audios: []repository2.Audio{
+			{Filename: "audio_1.m3u8", Language: "eng"},
+			{Filename: "audio_2.m3u8", Language: "fre"},
+		},
+		Subtitles: []repository2.Subtitle{
+			{Filename: "subtitle_1.vtt", Language: "fre"},
+			{Filename: "subtitle_2.vtt", Language: "ger"},
+		},
+	}
+	// defaults returns the names of the default audio and subtitle renditions of a master playlist
+	defaults := func(playlist string) (audio, subtitle string) {
+		for _, line := range strings.Split(playlist, "\n") {
+			if !strings.Contains(line, "DEFAULT=YES") {
+				continue
+			}
+			name := strings.SplitN(strings.SplitN(line, `NAME="`, 2)[1], `"`, 2)[0]
+			if strings.Contains(line, "TYPE=AUDIO") {
+				audio = name
+			} else {
+				subtitle = name
+			}
+		}
+		return audio, subtitle
+	}
+	tests := []struct {
+		name         string
+		preferred    string
+		wantAudio    string
+		wantSubtitle string
+	}{
+		{"no preference", "", "English", ""},
+		{"audio in the preferred language", "fr-FR", "français", ""},
+		{"subtitle in the preferred language", "de", "English", "Deutsch"},
+		{"ISO 639-2 preference", "ger", "English", "Deutsch"},
+		{"nothing in the preferred language", "ja", "English", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			playlist := string(playback.masterPlaylist("movies/603", file, test.preferred))
+			if audio, subtitle := defaults(playlist); audio != test.wantAudio || subtitle != test.wantSubtitle {
+				t.Errorf("defaults = %q, %q, want %q, %q", audio, subtitle, test.wantAudio, test.wantSubtitle)
+			}
+		})
+	}
+
+	playlist := string(playback.masterPlaylist("movies/603", file, ""))
+	for _, want := range []string{
+		"#EXTM3U\n",
+		`URI="/playback/playlist/movies/603/audio_1.m3u8?expires=`,
+		`URI="/playback/playlist/movies/603/subtitle_2.vtt.m3u8?expires=`,
+		`#EXT-X-STREAM-INF:BANDWIDTH=3160000,CODECS="avc1.640028,mp4a.40.2",AUDIO="audio",SUBTITLES="subtitles"` + "\n/playback/playlist/movies/603/video.m3u8?expires=",
+	} {
+		if !strings.Contains(playlist, want) {
+			t.Errorf("master playlist does not contain %q:\n%s", want, playlist)
+		}
+	}
+
+	videoOnly := string(playback.masterPlaylist("movies/603", &repository2.MediaFile{Filename: "video.m3u8"}, "fr"))
+	if strings.Contains(videoOnly, "#EXT-X-MEDIA") || strings.Contains(videoOnly, "AUDIO=") || strings.Contains(videoOnly, "SUBTITLES=") {
+		t.Errorf("master playlist of a file without audios and subtitles has renditions:\n%s", videoOnly)
+	}
+}
@@ -1,6 +1,10 @@
 package features
 
-import "github.com/bingemate/media-go-pkg/tmdb"
+import (
+	"context"
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/tracing"
+)
 
 type MediaAssetsData struct {
 	mediaClient tmdb.MediaClient
@@ -12,6 +16,13 @@ func NewMediaAssetsData(mediaClient tmdb.MediaClient) *MediaAssetsData {
 	}
 }
 
+// WithContext returns a copy of the service bound to the given request context
+func (m *MediaAssetsData) WithContext(ctx context.Context) *MediaAssetsData {
+	return &MediaAssetsData{
+		mediaClient: tracing.NewMediaClient(ctx, m.mediaClient),
+	}
+}
+
 func (m *MediaAssetsData) GetMovieGenre(id int) (*tmdb.Genre, error) {
 	genres, err := m.mediaClient.GetMovieGenre(id)
 	if err != nil {
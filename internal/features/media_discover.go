@@ -1,27 +1,51 @@
 package features
 
 import (
+	"context"
 	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/logging"
 	"github.com/bingemate/media-service/internal/repository"
-	"log"
+	"github.com/bingemate/media-service/internal/tracing"
+	"log/slog"
 	"math"
 )
 
 type MediaDiscovery struct {
-	mediaClient     tmdb.MediaClient
-	mediaRepository *repository.MediaRepository
+	mediaClient         tmdb.MediaClient
+	mediaRepository     *repository.MediaRepository
+	suggestionIndex     *SuggestionIndex
+	trendingIndex       *TrendingIndex
+	recommendationIndex *RecommendationIndex
+	logger              *slog.Logger
 }
 
-func NewMediaDiscovery(mediaClient tmdb.MediaClient, mediaRepository *repository.MediaRepository) *MediaDiscovery {
+func NewMediaDiscovery(mediaClient tmdb.MediaClient, mediaRepository *repository.MediaRepository, suggestionIndex *SuggestionIndex, trendingIndex *TrendingIndex, recommendationIndex *RecommendationIndex) *MediaDiscovery {
 	return &MediaDiscovery{
-		mediaClient:     mediaClient,
-		mediaRepository: mediaRepository,
+		mediaClient:         mediaClient,
+		mediaRepository:     mediaRepository,
+		suggestionIndex:     suggestionIndex,
+		trendingIndex:       trendingIndex,
+		recommendationIndex: recommendationIndex,
+		logger:              slog.Default(),
 	}
 }
 
-func (m *MediaDiscovery) SearchMovie(query string, page int, adult, available bool) (*tmdb.PaginatedMovieResults, *[]bool, error) {
+// WithContext returns a copy of the service bound to the given request context
+func (m *MediaDiscovery) WithContext(ctx context.Context) *MediaDiscovery {
+	return &MediaDiscovery{
+		mediaClient:         tracing.NewMediaClient(ctx, m.mediaClient),
+		mediaRepository:     m.mediaRepository.WithContext(ctx),
+		suggestionIndex:     m.suggestionIndex,
+		trendingIndex:       m.trendingIndex,
+		recommendationIndex: m.recommendationIndex,
+		logger:              logging.FromContext(ctx),
+	}
+}
+
+// SearchMovie searches movies on TMDB, or only the available ones having the given languages
+func (m *MediaDiscovery) SearchMovie(query string, page int, adult, available bool, languages repository.LanguageFilter) (*tmdb.PaginatedMovieResults, *[]bool, error) {
 	if available {
-		return m.searchAvailableMovie(query, page)
+		return m.searchAvailableMovie(query, page, languages)
 	}
 	return m.searchAllMovie(query, page, adult)
 }
@@ -43,8 +67,8 @@ func (m *MediaDiscovery) searchAllMovie(query string, page int, adult bool) (*tm
 	return movies, &presence, nil
 }
 
-func (m *MediaDiscovery) searchAvailableMovie(query string, page int) (*tmdb.PaginatedMovieResults, *[]bool, error) {
-	movies, total, err := m.mediaRepository.SearchAvailableMovies(page, 20, query)
+func (m *MediaDiscovery) searchAvailableMovie(query string, page int, languages repository.LanguageFilter) (*tmdb.PaginatedMovieResults, *[]bool, error) {
+	movies, total, err := m.mediaRepository.SearchAvailableMovies(page, 20, query, languages)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -53,7 +77,7 @@ func (m *MediaDiscovery) searchAvailableMovie(query string, page int) (*tmdb.Pag
 	for i, movie := range movies {
 		result, err := m.mediaClient.GetMovieShort(movie.ID)
 		if err != nil {
-			log.Println("error getting movie", movie.ID, err)
+			m.logger.Error("error getting movie", "movie_id", movie.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(movie.ID)
@@ -67,13 +91,14 @@ func (m *MediaDiscovery) searchAvailableMovie(query string, page int) (*tmdb.Pag
 	return &tmdb.PaginatedMovieResults{
 		Results:     results,
 		TotalResult: total,
-		TotalPage:   int(math.Round(float64(total) / 20)),
+		TotalPage:   int(math.Ceil(float64(total) / 20)),
 	}, &presence, nil
 }
 
-func (m *MediaDiscovery) SearchShow(query string, page int, adult, available bool) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
+// SearchShow searches tv shows on TMDB, or only the available ones having an episode with the given languages
+func (m *MediaDiscovery) SearchShow(query string, page int, adult, available bool, languages repository.LanguageFilter) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
 	if available {
-		return m.searchAvailableShow(query, page)
+		return m.searchAvailableShow(query, page, languages)
 	}
 	return m.searchAllShow(query, page, adult)
 }
@@ -99,8 +124,8 @@ func (m *MediaDiscovery) SearchActor(query string, page int, adult bool) (*tmdb.
 	return m.mediaClient.SearchActors(query, page, adult)
 }
 
-func (m *MediaDiscovery) searchAvailableShow(query string, page int) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
-	shows, total, err := m.mediaRepository.SearchAvailableTvShows(page, 20, query)
+func (m *MediaDiscovery) searchAvailableShow(query string, page int, languages repository.LanguageFilter) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
+	shows, total, err := m.mediaRepository.SearchAvailableTvShows(page, 20, query, languages)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -110,7 +135,7 @@ func (m *MediaDiscovery) searchAvailableShow(query string, page int) (*tmdb.Pagi
 
 		result, err := m.mediaClient.GetTVShowShort(show.ID)
 		if err != nil {
-			log.Println("error getting show", show.ID, err)
+			m.logger.Error("error getting show", "tv_show_id", show.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(show.ID)
@@ -124,13 +149,14 @@ func (m *MediaDiscovery) searchAvailableShow(query string, page int) (*tmdb.Pagi
 	return &tmdb.PaginatedTVShowResults{
 		Results:     results,
 		TotalResult: total,
-		TotalPage:   int(math.Round(float64(total) / 20)),
+		TotalPage:   int(math.Ceil(float64(total) / 20)),
 	}, &presence, nil
 }
 
-func (m *MediaDiscovery) GetPopularMovies(page int, available bool) (*tmdb.PaginatedMovieResults, *[]bool, error) {
+// GetPopularMovies returns the popular movies of TMDB, or the best rated available ones having the given languages
+func (m *MediaDiscovery) GetPopularMovies(page int, available bool, languages repository.LanguageFilter) (*tmdb.PaginatedMovieResults, *[]bool, error) {
 	if available {
-		return m.getAvailablePopularMovies(page)
+		return m.getAvailablePopularMovies(page, languages)
 	}
 	return m.getAllPopularMovies(page)
 }
@@ -152,8 +178,8 @@ func (m *MediaDiscovery) getAllPopularMovies(page int) (*tmdb.PaginatedMovieResu
 	return movies, &presence, nil
 }
 
-func (m *MediaDiscovery) getAvailablePopularMovies(page int) (*tmdb.PaginatedMovieResults, *[]bool, error) {
-	movies, total, err := m.mediaRepository.GetAvailableMoviesByRating(page, 20, 30)
+func (m *MediaDiscovery) getAvailablePopularMovies(page int, languages repository.LanguageFilter) (*tmdb.PaginatedMovieResults, *[]bool, error) {
+	movies, total, err := m.mediaRepository.GetAvailableMoviesByRating(languages, page, 20, 30)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -162,7 +188,7 @@ func (m *MediaDiscovery) getAvailablePopularMovies(page int) (*tmdb.PaginatedMov
 	for i, movie := range movies {
 		result, err := m.mediaClient.GetMovieShort(movie.ID)
 		if err != nil {
-			log.Println("error getting movie", movie.ID, err)
+			m.logger.Error("error getting movie", "movie_id", movie.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(movie.ID)
@@ -176,13 +202,14 @@ func (m *MediaDiscovery) getAvailablePopularMovies(page int) (*tmdb.PaginatedMov
 	return &tmdb.PaginatedMovieResults{
 		Results:     results,
 		TotalResult: total,
-		TotalPage:   int(math.Round(float64(total) / 20)),
+		TotalPage:   int(math.Ceil(float64(total) / 20)),
 	}, &presence, nil
 }
 
-func (m *MediaDiscovery) GetPopularShows(page int, available bool) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
+// GetPopularShows returns the popular tv shows of TMDB, or the best rated available ones having an episode with the given languages
+func (m *MediaDiscovery) GetPopularShows(page int, available bool, languages repository.LanguageFilter) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
 	if available {
-		return m.getAvailablePopularTVShows(page)
+		return m.getAvailablePopularTVShows(page, languages)
 	}
 	return m.getAllPopularTVShows(page)
 }
@@ -204,8 +231,8 @@ func (m *MediaDiscovery) getAllPopularTVShows(page int) (*tmdb.PaginatedTVShowRe
 	return shows, &presence, nil
 }
 
-func (m *MediaDiscovery) getAvailablePopularTVShows(page int) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
-	shows, total, err := m.mediaRepository.GetAvailableTvShowsByRating(page, 20, 30)
+func (m *MediaDiscovery) getAvailablePopularTVShows(page int, languages repository.LanguageFilter) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
+	shows, total, err := m.mediaRepository.GetAvailableTvShowsByRating(languages, page, 20, 30)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -214,7 +241,7 @@ func (m *MediaDiscovery) getAvailablePopularTVShows(page int) (*tmdb.PaginatedTV
 	for i, show := range shows {
 		result, err := m.mediaClient.GetTVShowShort(show.ID)
 		if err != nil {
-			log.Println("error getting show", show.ID, err)
+			m.logger.Error("error getting show", "tv_show_id", show.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(show.ID)
@@ -228,13 +255,14 @@ func (m *MediaDiscovery) getAvailablePopularTVShows(page int) (*tmdb.PaginatedTV
 	return &tmdb.PaginatedTVShowResults{
 		Results:     results,
 		TotalResult: total,
-		TotalPage:   int(math.Round(float64(total) / 20)),
+		TotalPage:   int(math.Ceil(float64(total) / 20)),
 	}, &presence, nil
 }
 
-func (m *MediaDiscovery) GetRecentMovies(available bool) ([]*tmdb.Movie, *[]bool, error) {
+// GetRecentMovies returns the recent movies of TMDB, or the last available ones having the given languages
+func (m *MediaDiscovery) GetRecentMovies(available bool, languages repository.LanguageFilter) ([]*tmdb.Movie, *[]bool, error) {
 	if available {
-		return m.getAvailableRecentMovies()
+		return m.getAvailableRecentMovies(languages)
 	}
 	return m.getAllRecentMovies()
 }
@@ -256,8 +284,8 @@ func (m *MediaDiscovery) getAllRecentMovies() ([]*tmdb.Movie, *[]bool, error) {
 	return movies, &presence, nil
 }
 
-func (m *MediaDiscovery) getAvailableRecentMovies() ([]*tmdb.Movie, *[]bool, error) {
-	movies, _, err := m.mediaRepository.GetAvailableRecentMovies(1, 20)
+func (m *MediaDiscovery) getAvailableRecentMovies(languages repository.LanguageFilter) ([]*tmdb.Movie, *[]bool, error) {
+	movies, _, err := m.mediaRepository.GetAvailableRecentMovies(languages, 1, 20)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -266,7 +294,7 @@ func (m *MediaDiscovery) getAvailableRecentMovies() ([]*tmdb.Movie, *[]bool, err
 	for i, movie := range movies {
 		result, err := m.mediaClient.GetMovieShort(movie.ID)
 		if err != nil {
-			log.Println("error getting movie", movie.ID, err)
+			m.logger.Error("error getting movie", "movie_id", movie.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(movie.ID)
@@ -280,9 +308,10 @@ func (m *MediaDiscovery) getAvailableRecentMovies() ([]*tmdb.Movie, *[]bool, err
 	return results, &presence, nil
 }
 
-func (m *MediaDiscovery) GetRecentShows(available bool) ([]*tmdb.TVShow, *[]bool, error) {
+// GetRecentShows returns the recent tv shows of TMDB, or the last available ones having an episode with the given languages
+func (m *MediaDiscovery) GetRecentShows(available bool, languages repository.LanguageFilter) ([]*tmdb.TVShow, *[]bool, error) {
 	if available {
-		return m.getAvailableRecentShows()
+		return m.getAvailableRecentShows(languages)
 	}
 	return m.getAllRecentShows()
 }
@@ -304,8 +333,8 @@ func (m *MediaDiscovery) getAllRecentShows() ([]*tmdb.TVShow, *[]bool, error) {
 	return shows, &presence, nil
 }
 
-func (m *MediaDiscovery) getAvailableRecentShows() ([]*tmdb.TVShow, *[]bool, error) {
-	shows, _, err := m.mediaRepository.GetAvailableRecentTvShows(1, 20)
+func (m *MediaDiscovery) getAvailableRecentShows(languages repository.LanguageFilter) ([]*tmdb.TVShow, *[]bool, error) {
+	shows, _, err := m.mediaRepository.GetAvailableRecentTvShows(languages, 1, 20)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -314,7 +343,7 @@ func (m *MediaDiscovery) getAvailableRecentShows() ([]*tmdb.TVShow, *[]bool, err
 	for i, show := range shows {
 		result, err := m.mediaClient.GetTVShowShort(show.ID)
 		if err != nil {
-			log.Println("error getting show", show.ID, err)
+			m.logger.Error("error getting show", "tv_show_id", show.ID, "error", err)
 			return nil, nil, err
 		}
 		voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(show.ID)
@@ -1,13 +1,14 @@
 package features
 
 import (
+	"context"
 	"errors"
 	objectStorage "github.com/bingemate/media-go-pkg/object-storage"
 	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/logging"
 	"github.com/bingemate/media-service/internal/repository"
 	"gorm.io/gorm"
-	"path"
-	"strconv"
+	"log/slog"
 	"syscall"
 )
 
@@ -15,15 +16,37 @@ type MediaFile struct {
 	moviePath       string
 	tvPath          string
 	mediaRepository *repository.MediaRepository
+	jobRepository   *repository.MediaRepository // Repository of the background jobs, not cancelled with the request.
 	objectStorage   objectStorage.ObjectStorage // Object storage object to upload the media files.
+	thresholds      StorageThresholds
+	jobs            context.Context // Context of the deletion jobs, see ResumeDeletionJobs.
+	logger          *slog.Logger
 }
 
-func NewMediaFile(moviePath string, tvPath string, mediaRepository *repository.MediaRepository, objectStorage objectStorage.ObjectStorage) *MediaFile {
+func NewMediaFile(moviePath string, tvPath string, mediaRepository *repository.MediaRepository, objectStorage objectStorage.ObjectStorage, thresholds StorageThresholds) *MediaFile {
 	return &MediaFile{
 		moviePath:       moviePath,
 		tvPath:          tvPath,
 		mediaRepository: mediaRepository,
+		jobRepository:   mediaRepository,
 		objectStorage:   objectStorage,
+		thresholds:      thresholds,
+		jobs:            context.Background(),
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (m *MediaFile) WithContext(ctx context.Context) *MediaFile {
+	return &MediaFile{
+		moviePath:       m.moviePath,
+		tvPath:          m.tvPath,
+		mediaRepository: m.mediaRepository.WithContext(ctx),
+		jobRepository:   m.jobRepository.WithContext(context.WithoutCancel(ctx)),
+		objectStorage:   m.objectStorage,
+		thresholds:      m.thresholds,
+		jobs:            m.jobs,
+		logger:          logging.FromContext(ctx),
 	}
 }
 
@@ -54,38 +77,64 @@ func (m *MediaFile) GetAvailableEpisode(tvShowID int) (*[]int, error) {
 	return episodes, nil
 }
 
-// SearchEpisodeFiles returns all episodes that match the query
-func (m *MediaFile) SearchEpisodeFiles(query string, page, limit int) ([]*repository2.Episode, int, error) {
-	return m.mediaRepository.SearchEpisodeFiles(query, page, limit)
+// SearchEpisodeFiles returns a page of the available episodes that match the query, the most relevant first
+func (m *MediaFile) SearchEpisodeFiles(query string, languages repository.LanguageFilter, page repository.Page) (*repository.CursorPage[*repository2.Episode], error) {
+	return m.mediaRepository.SearchEpisodeFiles(query, languages, withPageSize(page, 20))
 }
 
-// SearchMovieFiles returns all movies that match the query
-func (m *MediaFile) SearchMovieFiles(query string, page, limit int) ([]*repository2.Movie, int, error) {
-	return m.mediaRepository.SearchMovieFiles(query, page, limit)
+// SearchMovieFiles returns a page of the available movies that match the query, the most relevant first
+func (m *MediaFile) SearchMovieFiles(query string, languages repository.LanguageFilter, page repository.Page) (*repository.CursorPage[*repository2.Movie], error) {
+	return m.mediaRepository.SearchMovieFiles(query, languages, withPageSize(page, 20))
 }
 
-// DeleteMediaFile deletes a media file given the fileID
-func (m *MediaFile) DeleteMediaFile(fileID string) error {
-	// Check if the file is present in movie or tv show folder
-	// If it is, delete it
-	episode, err := m.mediaRepository.GetEpisodeByFileID(fileID)
+// DeleteMediaFile marks a media file pending deletion and deletes it in the background, see DeletionJob.
+// The job already in progress for the file is returned if any.
+func (m *MediaFile) DeleteMediaFile(fileID string) (*repository.DeletionJob, error) {
+	if !m.mediaRepository.IsMediaFilePresent(fileID) {
+		return nil, ErrMediaNotFound
+	}
+	prefix, err := mediaFilePrefix(m.mediaRepository, fileID)
+	if err != nil {
+		return nil, err
+	}
+	job, created, err := m.mediaRepository.CreateDeletionJob(fileID, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		go m.runDeletionJob(m.jobs, job)
+	}
+	return job, nil
+}
+
+// mediaFilePrefix returns the storage directory of the movie or episode of a media file, see storagePrefix,
+// empty if it has none
+func mediaFilePrefix(mediaRepository *repository.MediaRepository, fileID string) (string, error) {
+	var prefix string
+	episode, err := mediaRepository.GetEpisodeByFileID(fileID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+		return "", err
 	}
 	if episode != nil {
-		tvPath := path.Join("tv-shows", strconv.Itoa(episode.ID))
-		go m.objectStorage.DeleteMediaFiles(tvPath)
+		prefix = storagePrefix(tvShowStoragePrefix, episode.ID)
 	}
-
-	movie, err := m.mediaRepository.GetMovieByFileID(fileID)
+	movie, err := mediaRepository.GetMovieByFileID(fileID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+		return "", err
 	}
 	if movie != nil {
-		moviePath := path.Join("movies", strconv.Itoa(movie.ID))
-		go m.objectStorage.DeleteMediaFiles(moviePath)
+		prefix = storagePrefix(movieStoragePrefix, movie.ID)
 	}
-	return m.mediaRepository.DeleteMediaFile(fileID)
+	return prefix, nil
+}
+
+// GetDeletionJob returns a media file deletion job given its ID
+func (m *MediaFile) GetDeletionJob(jobID string) (*repository.DeletionJob, error) {
+	job, err := m.mediaRepository.GetDeletionJob(jobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeletionJobNotFound
+	}
+	return job, err
 }
 
 // MediaFilesTotalSize returns the total size of all media files
@@ -93,6 +142,19 @@ func (m *MediaFile) MediaFilesTotalSize() (int64, error) {
 	return m.mediaRepository.MediaFilesTotalSize()
 }
 
+// GetLanguages returns the audio and subtitle languages of the library, with the number of movies and episodes having them
+func (m *MediaFile) GetLanguages() ([]repository.LanguageCount, []repository.LanguageCount, error) {
+	audios, err := m.mediaRepository.GetAudioLanguages()
+	if err != nil {
+		return nil, nil, err
+	}
+	subtitles, err := m.mediaRepository.GetSubtitleLanguages()
+	if err != nil {
+		return nil, nil, err
+	}
+	return audios, subtitles, nil
+}
+
 // MediaFilesCount returns the total number of media files
 func (m *MediaFile) MediaFilesCount() (int64, error) {
 	return m.mediaRepository.MediaFilesCount()
@@ -123,7 +185,12 @@ func (m *MediaFile) CountEpisodesTotalDuration() (int64, error) {
 	return m.mediaRepository.CountEpisodesTotalDuration()
 }
 
-// AvailableSpace returns the available space in the media folder
+// ReindexSearch rebuilds the database indexes used by the searches
+func (m *MediaFile) ReindexSearch() error {
+	return m.mediaRepository.ReindexSearch()
+}
+
+// AvailableSpace returns the available space in the movie folder, see GetFoldersUsage for both target folders
 func (m *MediaFile) AvailableSpace() (uint64, error) {
 	fs := syscall.Statfs_t{}
 	err := syscall.Statfs(m.moviePath, &fs)
@@ -0,0 +1,58 @@
+package features
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bucketStorage is an object storage deleting the objects listed under a prefix, as the S3 one does
+type bucketStorage struct {
+	mutex sync.Mutex
+	keys  []string
+}
+
+func (b *bucketStorage) UploadMediaFiles(string, string) error {
+	return nil
+}
+
+func (b *bucketStorage) DeleteMediaFiles(prefix string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.keys = slices.DeleteFunc(b.keys, func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+	return nil
+}
+
+func (b *bucketStorage) remaining() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return slices.Clone(b.keys)
+}
+
+func TestStoragePrefix(t *testing.T) {
+	if got := storagePrefix(movieStoragePrefix, 12); got != "movies/12/" {
+		t.Errorf("storagePrefix(movies, 12) = %q, want %q", got, "movies/12/")
+	}
+	if got := storagePrefix(tvShowStoragePrefix, 34); got != "tv-shows/34/" {
+		t.Errorf("storagePrefix(tv-shows, 34) = %q, want %q", got, "tv-shows/34/")
+	}
+
+	storage := &bucketStorage{keys: []string{
+		"movies/12/video.m3u8",
+		"movies/12/video_000.ts",
+		"movies/120/video.m3u8",
+		"movies/1200/video.m3u8",
+		"tv-shows/12/video.m3u8",
+	}}
+	if err := storage.DeleteMediaFiles(storagePrefix(movieStoragePrefix, 12)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"movies/120/video.m3u8", "movies/1200/video.m3u8", "tv-shows/12/video.m3u8"}
+	if got := storage.remaining(); !reflect.DeepEqual(got, want) {
+		t.Errorf("remaining objects = %v, want %v", got, want)
+	}
+}
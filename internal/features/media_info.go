@@ -1,10 +1,12 @@
 package features
 
 import (
+	"context"
 	"errors"
 	repository2 "github.com/bingemate/media-go-pkg/repository"
 	"github.com/bingemate/media-go-pkg/tmdb"
 	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/tracing"
 	"gorm.io/gorm"
 	"sync"
 )
@@ -21,6 +23,14 @@ func NewMediaData(mediaClient tmdb.MediaClient, mediaRepository *repository.Medi
 	}
 }
 
+// WithContext returns a copy of the service bound to the given request context
+func (m *MediaData) WithContext(ctx context.Context) *MediaData {
+	return &MediaData{
+		mediaClient:     tracing.NewMediaClient(ctx, m.mediaClient),
+		mediaRepository: m.mediaRepository.WithContext(ctx),
+	}
+}
+
 //// GetMediaByID returns a media given the mediaID (TMDB ID)
 //func (m *MediaData) GetMediaByID(id int) (*repository2.Media, error) {
 //	media, err := m.mediaRepository.GetMedia(id)
@@ -258,3 +268,16 @@ func (m *MediaData) GetTvShowEpisodes(tvID int) ([]*tmdb.TVEpisode, *[]bool, err
 	}
 	return episodes, &presence, nil
 }
+
+// GetLibrary returns all the stored movies and tv shows with their files, only the available ones if availableOnly is set
+func (m *MediaData) GetLibrary(availableOnly bool) ([]repository2.Movie, []repository2.TvShow, error) {
+	movies, err := m.mediaRepository.GetLibraryMovies(availableOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	tvShows, err := m.mediaRepository.GetLibraryTvShows(availableOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	return movies, tvShows, nil
+}
@@ -0,0 +1,77 @@
+package features
+
+import (
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/repository"
+	"math"
+)
+
+const libraryPageSize = 20
+
+// FilterLibraryMovies returns a page of the available movies matching the filter, sorted as asked,
+// with the facet counts of the filter
+func (m *MediaDiscovery) FilterLibraryMovies(filter repository.LibraryFilter, sort repository.LibrarySort, descending bool, page int) (*tmdb.PaginatedMovieResults, *[]bool, *repository.LibraryFacets, error) {
+	movies, total, err := m.mediaRepository.FilterLibraryMovies(filter, sort, descending, page, libraryPageSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	facets, err := m.mediaRepository.GetLibraryMovieFacets(filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	presence := make([]bool, len(movies))
+	results := make([]*tmdb.Movie, len(movies))
+	for i, movie := range movies {
+		result, err := m.mediaClient.GetMovieShort(movie.ID)
+		if err != nil {
+			m.logger.Error("error getting movie", "movie_id", movie.ID, "error", err)
+			return nil, nil, nil, err
+		}
+		voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(movie.ID)
+		if err == nil {
+			result.VoteAverage = voteAverage
+			result.VoteCount = voteCount
+		}
+		results[i] = result
+		presence[i] = true
+	}
+	return &tmdb.PaginatedMovieResults{
+		Results:     results,
+		TotalResult: total,
+		TotalPage:   int(math.Ceil(float64(total) / libraryPageSize)),
+	}, &presence, facets, nil
+}
+
+// FilterLibraryShows returns a page of the available tv shows matching the filter, sorted as asked,
+// with the facet counts of the filter
+func (m *MediaDiscovery) FilterLibraryShows(filter repository.LibraryFilter, sort repository.LibrarySort, descending bool, page int) (*tmdb.PaginatedTVShowResults, *[]bool, *repository.LibraryFacets, error) {
+	shows, total, err := m.mediaRepository.FilterLibraryTvShows(filter, sort, descending, page, libraryPageSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	facets, err := m.mediaRepository.GetLibraryTvShowFacets(filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	presence := make([]bool, len(shows))
+	results := make([]*tmdb.TVShow, len(shows))
+	for i, show := range shows {
+		result, err := m.mediaClient.GetTVShowShort(show.ID)
+		if err != nil {
+			m.logger.Error("error getting show", "tv_show_id", show.ID, "error", err)
+			return nil, nil, nil, err
+		}
+		voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(show.ID)
+		if err == nil {
+			result.VoteAverage = voteAverage
+			result.VoteCount = voteCount
+		}
+		results[i] = result
+		presence[i] = true
+	}
+	return &tmdb.PaginatedTVShowResults{
+		Results:     results,
+		TotalResult: total,
+		TotalPage:   int(math.Ceil(float64(total) / libraryPageSize)),
+	}, &presence, facets, nil
+}
@@ -0,0 +1,275 @@
+package features
+
+import (
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/repository"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SearchResultType is the kind of a unified search result
+type SearchResultType string
+
+const (
+	SearchResultMovie   SearchResultType = "movie"
+	SearchResultTvShow  SearchResultType = "tv"
+	SearchResultEpisode SearchResultType = "episode"
+	SearchResultPerson  SearchResultType = "person"
+)
+
+// librarySearchPageSize is the number of results of a page of the unified search of the library
+const librarySearchPageSize = 20
+
+// SearchResult is a result of the unified search, only the field matching Type is set
+type SearchResult struct {
+	Type    SearchResultType
+	Movie   *tmdb.Movie
+	TvShow  *tmdb.TVShow
+	Episode *tmdb.TVEpisode
+	Person  *tmdb.Actor
+	Present bool
+	score   float64
+}
+
+// SearchTotal is the number of pages and results of the search of a type
+type SearchTotal struct {
+	TotalPage   int
+	TotalResult int
+}
+
+// PaginatedSearchResults is a page of the unified search, Totals giving the number of pages and results of each type.
+// In the library, TotalPage and TotalResult count the single list of the results. On TMDB, pages are per type:
+// TotalPage is the largest page count of the searched types and TotalResult the sum of their results.
+type PaginatedSearchResults struct {
+	Results     []*SearchResult
+	TotalPage   int
+	TotalResult int
+	Totals      map[SearchResultType]SearchTotal
+}
+
+// Search searches movies, tv shows and people at once and merges the results by relevance.
+// If available is set, only the movies, tv shows and episodes of the library having the given languages are searched,
+// ranked together by the database so that a page is a slice of a single list. Otherwise TMDB is searched, a page
+// holding the same page of each search, a type having fewer pages adding nothing to the following pages, and
+// episodes are not searched. types restricts the searched kinds, all of them are searched if it is empty.
+func (m *MediaDiscovery) Search(query string, page int, adult, available bool, languages repository.LanguageFilter, types []SearchResultType) (*PaginatedSearchResults, error) {
+	if available {
+		return m.searchLibrary(query, page, languages, types)
+	}
+	searched := func(resultType SearchResultType) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, t := range types {
+			if t == resultType {
+				return true
+			}
+		}
+		return false
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+		results  = &PaginatedSearchResults{
+			Results: make([]*SearchResult, 0),
+			Totals:  make(map[SearchResultType]SearchTotal),
+		}
+	)
+	collect := func(resultType SearchResultType, found []*SearchResult, totalPage, totalResult int, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		results.Results = append(results.Results, found...)
+		results.Totals[resultType] = SearchTotal{totalPage, totalResult}
+		results.TotalResult += totalResult
+		if totalPage > results.TotalPage {
+			results.TotalPage = totalPage
+		}
+	}
+
+	if searched(SearchResultMovie) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			movies, presence, err := m.SearchMovie(query, page, adult, false, languages)
+			if err != nil {
+				collect(SearchResultMovie, nil, 0, 0, err)
+				return
+			}
+			found := make([]*SearchResult, len(movies.Results))
+			for i, movie := range movies.Results {
+				found[i] = &SearchResult{
+					Type:    SearchResultMovie,
+					Movie:   movie,
+					Present: (*presence)[i],
+					score:   relevance(query, movie.Title, i),
+				}
+			}
+			collect(SearchResultMovie, found, movies.TotalPage, movies.TotalResult, nil)
+		}()
+	}
+	if searched(SearchResultTvShow) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shows, presence, err := m.SearchShow(query, page, adult, false, languages)
+			if err != nil {
+				collect(SearchResultTvShow, nil, 0, 0, err)
+				return
+			}
+			found := make([]*SearchResult, len(shows.Results))
+			for i, show := range shows.Results {
+				found[i] = &SearchResult{
+					Type:    SearchResultTvShow,
+					TvShow:  show,
+					Present: (*presence)[i],
+					score:   relevance(query, show.Title, i),
+				}
+			}
+			collect(SearchResultTvShow, found, shows.TotalPage, shows.TotalResult, nil)
+		}()
+	}
+	if searched(SearchResultPerson) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			actors, err := m.SearchActor(query, page, adult)
+			if err != nil {
+				collect(SearchResultPerson, nil, 0, 0, err)
+				return
+			}
+			found := make([]*SearchResult, len(actors.Results))
+			for i, actor := range actors.Results {
+				found[i] = &SearchResult{
+					Type:   SearchResultPerson,
+					Person: actor,
+					score:  relevance(query, actor.Name, i),
+				}
+			}
+			collect(SearchResultPerson, found, actors.TotalPage, actors.TotalResult, nil)
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.SliceStable(results.Results, func(i, j int) bool {
+		return results.Results[i].score > results.Results[j].score
+	})
+	return results, nil
+}
+
+// searchLibrary returns a page of the available movies, tv shows and episodes matching query, ordered by relevance
+func (m *MediaDiscovery) searchLibrary(query string, page int, languages repository.LanguageFilter, types []SearchResultType) (*PaginatedSearchResults, error) {
+	// people are not part of the library
+	var mediaTypes []repository.SavedMediaType
+	for _, resultType := range types {
+		if resultType != SearchResultPerson {
+			mediaTypes = append(mediaTypes, repository.SavedMediaType(resultType))
+		}
+	}
+	if len(types) > 0 && len(mediaTypes) == 0 {
+		return &PaginatedSearchResults{Results: []*SearchResult{}, Totals: map[SearchResultType]SearchTotal{}}, nil
+	}
+	matches, totals, err := m.mediaRepository.SearchLibrary(query, languages, mediaTypes, page, librarySearchPageSize)
+	if err != nil {
+		return nil, err
+	}
+	results := &PaginatedSearchResults{
+		Results: make([]*SearchResult, len(matches)),
+		Totals:  make(map[SearchResultType]SearchTotal, len(totals)),
+	}
+	for mediaType, total := range totals {
+		results.Totals[SearchResultType(mediaType)] = SearchTotal{searchPageCount(total), total}
+		results.TotalResult += total
+	}
+	results.TotalPage = searchPageCount(results.TotalResult)
+	for i, match := range matches {
+		result := &SearchResult{Type: SearchResultType(match.Type), Present: true, score: match.SearchRank}
+		switch match.Type {
+		case repository.SavedMovie:
+			result.Movie, err = m.mediaClient.GetMovieShort(match.ID)
+			if err == nil {
+				if voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(match.ID); err == nil {
+					result.Movie.VoteAverage, result.Movie.VoteCount = voteAverage, voteCount
+				}
+			}
+		case repository.SavedTvShow:
+			result.TvShow, err = m.mediaClient.GetTVShowShort(match.ID)
+			if err == nil {
+				if voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(match.ID); err == nil {
+					result.TvShow.VoteAverage, result.TvShow.VoteCount = voteAverage, voteCount
+				}
+			}
+		default:
+			result.Episode, err = m.mediaClient.GetTVEpisode(match.TvShowID, match.Season, match.Episode)
+		}
+		if err != nil {
+			m.logger.Error("error getting search result", "type", match.Type, "id", match.ID, "error", err)
+			return nil, err
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// searchPageCount returns the number of pages of total library search results
+func searchPageCount(total int) int {
+	return (total + librarySearchPageSize - 1) / librarySearchPageSize
+}
+
+// relevance scores how well name matches query, between 0 and 1, ignoring case and accents.
+// position is the rank of the result in its own search, used to keep that order between equal matches.
+func relevance(query, name string, position int) float64 {
+	query, name = normalizeName(query), normalizeName(name)
+	var score float64
+	switch {
+	case query == "" || name == "":
+		score = 0
+	case name == query:
+		score = 1
+	case strings.HasPrefix(name, query):
+		score = 0.9
+	default:
+		queryWords := strings.Fields(query)
+		nameWords := strings.Fields(name)
+		matched := 0
+		for _, queryWord := range queryWords {
+			for _, nameWord := range nameWords {
+				if strings.HasPrefix(nameWord, queryWord) {
+					matched++
+					break
+				}
+			}
+		}
+		score = 0.8 * float64(matched) / float64(len(queryWords))
+	}
+	return score - 0.001*float64(position)
+}
+
+// normalizeName lower cases name, removes its accents and replaces its punctuation with spaces.
+// The transformer removing the accents keeps a state, so each call builds its own.
+func normalizeName(name string) string {
+	removeAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	unaccented, _, err := transform.String(removeAccents, name)
+	if err != nil {
+		unaccented = name
+	}
+	words := strings.FieldsFunc(strings.ToLower(unaccented), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,43 @@
+package features
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Amélie", "amelie"},
+		{"  Spider-Man: No Way Home ", "spider man no way home"},
+		{"Léon: The Professional", "leon the professional"},
+		{"WALL·E", "wall e"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := normalizeName(test.name); got != test.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+// TestNormalizeNameConcurrent is meant to be run with -race, normalizeName being called by the searches
+// and the suggestion index at the same time
+func TestNormalizeNameConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := normalizeName("Amélie Poulain"); got != "amelie poulain" {
+					t.Errorf("normalizeName() = %q", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
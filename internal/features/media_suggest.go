@@ -0,0 +1,208 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxSuggestionMatches bounds the number of index entries ranked for a single prefix
+const maxSuggestionMatches = 500
+
+type SuggestionSource string
+
+const (
+	SuggestionLibrary SuggestionSource = "library"
+	SuggestionTMDB    SuggestionSource = "tmdb"
+)
+
+// Suggestion is an autocomplete entry, TvShowID is only set for episodes
+type Suggestion struct {
+	Type     string
+	ID       int
+	TvShowID int
+	Name     string
+	Source   SuggestionSource
+	rank     int
+}
+
+// SuggestionIndex is an in-memory prefix index over the names of the library. Every name is
+// indexed from the start of each of its words, ignoring case and accents, so "wa" finds "Star Wars".
+// It is loaded once from the database, then kept up to date by the OnSave hook of the repository. The media losing
+// their files are not removed, so the availability of the suggestions is checked at once when they are returned.
+type SuggestionIndex struct {
+	mutex           sync.RWMutex
+	mediaRepository *repository.MediaRepository
+	media           []repository.SavedMedia
+	known           map[repository.SavedMediaType]map[int]bool
+	entries         []suggestionEntry
+}
+
+// suggestionEntry points to a media, key being its normalized name from its word-th word
+type suggestionEntry struct {
+	key   string
+	media int
+	word  int
+}
+
+// NewSuggestionIndex returns an index registered on the repository, so that the new movies, tv shows and episodes
+// are added to it. It is empty until it is loaded.
+func NewSuggestionIndex(mediaRepository *repository.MediaRepository) *SuggestionIndex {
+	index := &SuggestionIndex{
+		mediaRepository: mediaRepository,
+		known:           map[repository.SavedMediaType]map[int]bool{},
+	}
+	mediaRepository.OnSave(index.Add)
+	return index
+}
+
+// Load indexes the names of the library
+func (s *SuggestionIndex) Load() error {
+	media, err := s.mediaRepository.GetMediaNames()
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, m := range media {
+		s.entries = append(s.entries, s.add(m)...)
+	}
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].key < s.entries[j].key
+	})
+	return nil
+}
+
+// Add indexes a saved media, ignoring the ones already indexed
+func (s *SuggestionIndex) Add(media repository.SavedMedia) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, entry := range s.add(media) {
+		position, _ := slices.BinarySearchFunc(s.entries, entry.key, func(e suggestionEntry, key string) int {
+			return strings.Compare(e.key, key)
+		})
+		s.entries = slices.Insert(s.entries, position, entry)
+	}
+}
+
+// add registers media and returns its entries, to be inserted in the sorted entries by the caller
+func (s *SuggestionIndex) add(media repository.SavedMedia) []suggestionEntry {
+	if s.known[media.Type] == nil {
+		s.known[media.Type] = map[int]bool{}
+	}
+	name := normalizeName(media.Name)
+	if s.known[media.Type][media.ID] || name == "" {
+		return nil
+	}
+	s.known[media.Type][media.ID] = true
+	s.media = append(s.media, media)
+
+	var entries []suggestionEntry
+	words := strings.Split(name, " ")
+	for i := range words {
+		entries = append(entries, suggestionEntry{
+			key:   strings.Join(words[i:], " "),
+			media: len(s.media) - 1,
+			word:  i,
+		})
+	}
+	return entries
+}
+
+// Suggest returns at most limit media whose name has a word starting with prefix.
+// The names starting with prefix come first, then the shortest names.
+func (s *SuggestionIndex) Suggest(prefix string, limit int) []*Suggestion {
+	prefix = normalizeName(prefix)
+	if prefix == "" || limit <= 0 {
+		return []*Suggestion{}
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	start := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].key >= prefix
+	})
+	seen := map[int]*Suggestion{}
+	matches := make([]*Suggestion, 0)
+	for i := start; i < len(s.entries) && len(matches) < maxSuggestionMatches; i++ {
+		entry := s.entries[i]
+		if !strings.HasPrefix(entry.key, prefix) {
+			break
+		}
+		if suggestion, ok := seen[entry.media]; ok {
+			suggestion.rank = min(suggestion.rank, entry.word, 1)
+			continue
+		}
+		media := s.media[entry.media]
+		seen[entry.media] = &Suggestion{
+			Type:     string(media.Type),
+			ID:       media.ID,
+			TvShowID: media.TvShowID,
+			Name:     media.Name,
+			Source:   SuggestionLibrary,
+			rank:     min(entry.word, 1),
+		}
+		matches = append(matches, seen[entry.media])
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return len(matches[i].Name) < len(matches[j].Name)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Suggest returns at most limit names of the available media of the library matching the beginning of query,
+// for type-ahead. If withTMDB is set, the remaining places are filled with the first movies and tv shows found
+// on TMDB whose title matches too.
+func (m *MediaDiscovery) Suggest(query string, limit int, withTMDB bool) ([]*Suggestion, error) {
+	suggestions := make([]*Suggestion, 0, limit)
+	candidates := m.suggestionIndex.Suggest(query, maxSuggestionMatches)
+	media := make([]repository.SavedMedia, len(candidates))
+	for i, candidate := range candidates {
+		media[i] = repository.SavedMedia{Type: repository.SavedMediaType(candidate.Type), ID: candidate.ID}
+	}
+	available, err := m.mediaRepository.GetAvailableMedia(media)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if len(suggestions) >= limit {
+			break
+		}
+		if available[repository.SavedMediaType(candidate.Type)][candidate.ID] {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	if !withTMDB || len(suggestions) >= limit {
+		return suggestions, nil
+	}
+
+	results, err := m.Search(query, 1, false, false, repository.LanguageFilter{}, []SearchResultType{SearchResultMovie, SearchResultTvShow})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results.Results {
+		if len(suggestions) >= limit {
+			break
+		}
+		// the library is already covered by the index, and below 0.75 some words of the query do not match
+		if result.Present || result.score < 0.75 {
+			continue
+		}
+		suggestion := &Suggestion{Type: string(result.Type), Source: SuggestionTMDB}
+		if result.Type == SearchResultMovie {
+			suggestion.ID, suggestion.Name = result.Movie.ID, result.Movie.Title
+		} else {
+			suggestion.ID, suggestion.Name = result.TvShow.ID, result.TvShow.Title
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,76 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func newTestSuggestionIndex(media ...repository.SavedMedia) *SuggestionIndex {
+	index := &SuggestionIndex{known: map[repository.SavedMediaType]map[int]bool{}}
+	for _, m := range media {
+		index.Add(m)
+	}
+	return index
+}
+
+func suggestionNames(suggestions []*Suggestion) []string {
+	names := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		names[i] = suggestion.Name
+	}
+	return names
+}
+
+func TestSuggestionIndexSuggest(t *testing.T) {
+	index := newTestSuggestionIndex(
+		repository.SavedMedia{Type: repository.SavedMovie, ID: 11, Name: "Star Wars"},
+		repository.SavedMedia{Type: repository.SavedMovie, ID: 1891, Name: "The Empire Strikes Back"},
+		repository.SavedMedia{Type: repository.SavedTvShow, ID: 1399, Name: "Game of Thrones"},
+		repository.SavedMedia{Type: repository.SavedEpisode, ID: 63056, TvShowID: 1399, Name: "Winter Is Coming"},
+		repository.SavedMedia{Type: repository.SavedMovie, ID: 194, Name: "Le Fabuleux Destin d'Amélie Poulain"},
+		repository.SavedMedia{Type: repository.SavedMovie, ID: 12, Name: "Wall-E"},
+	)
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"start of a name first, then the shortest", "w", 10, []string{"Wall-E", "Winter Is Coming", "Star Wars"}},
+		{"word inside a name", "strikes", 10, []string{"The Empire Strikes Back"}},
+		{"several words", "star w", 10, []string{"Star Wars"}},
+		{"case and accents ignored", "AMELIE", 10, []string{"Le Fabuleux Destin d'Amélie Poulain"}},
+		{"limit", "w", 1, []string{"Wall-E"}},
+		{"no match", "xyz", 10, []string{}},
+		{"empty prefix", " ", 10, []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := suggestionNames(index.Suggest(test.prefix, test.limit)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", test.prefix, got, test.want)
+			}
+		})
+	}
+
+	episode := index.Suggest("winter", 1)[0]
+	if episode.Type != string(repository.SavedEpisode) || episode.ID != 63056 || episode.TvShowID != 1399 || episode.Source != SuggestionLibrary {
+		t.Errorf("Suggest(winter) = %+v, want episode 63056 of tv show 1399 from the library", episode)
+	}
+}
+
+func TestSuggestionIndexAdd(t *testing.T) {
+	index := newTestSuggestionIndex(repository.SavedMedia{Type: repository.SavedMovie, ID: 11, Name: "Star Wars"})
+
+	index.Add(repository.SavedMedia{Type: repository.SavedMovie, ID: 11, Name: "Star Wars"})
+	index.Add(repository.SavedMedia{Type: repository.SavedTvShow, ID: 11, Name: "Star Trek"})
+	index.Add(repository.SavedMedia{Type: repository.SavedMovie, ID: 13, Name: ""})
+	if got := suggestionNames(index.Suggest("star", 10)); !reflect.DeepEqual(got, []string{"Star Trek", "Star Wars"}) {
+		t.Errorf("Suggest(star) = %v, want Star Trek and Star Wars once", got)
+	}
+	for i := 1; i < len(index.entries); i++ {
+		if index.entries[i-1].key > index.entries[i].key {
+			t.Fatalf("entries are not sorted: %q before %q", index.entries[i-1].key, index.entries[i].key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package features
+
+import (
+	"context"
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/tracing"
+	"log/slog"
+)
+
+// MetadataRefreshReport counts the medias refreshed from TMDB and the ones that failed
+type MetadataRefreshReport struct {
+	Movies   int
+	TvShows  int
+	Episodes int
+	Failed   int
+}
+
+type MetadataRefresh struct {
+	mediaClient     tmdb.MediaClient
+	mediaRepository *repository.MediaRepository
+	logger          *slog.Logger
+}
+
+func NewMetadataRefresh(mediaClient tmdb.MediaClient, mediaRepository *repository.MediaRepository) *MetadataRefresh {
+	return &MetadataRefresh{
+		mediaClient:     mediaClient,
+		mediaRepository: mediaRepository,
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (m *MetadataRefresh) WithContext(ctx context.Context) *MetadataRefresh {
+	return &MetadataRefresh{
+		mediaClient:     tracing.NewMediaClient(ctx, m.mediaClient),
+		mediaRepository: m.mediaRepository.WithContext(ctx),
+		logger:          logging.FromContext(ctx),
+	}
+}
+
+// RefreshAll fetches again from TMDB the metadata of every movie, tv show and episode stored in the database.
+// A media failing to refresh is logged and counted, it does not stop the refresh of the others.
+func (m *MetadataRefresh) RefreshAll() (*MetadataRefreshReport, error) {
+	report := &MetadataRefreshReport{}
+	movieIDs, err := m.mediaRepository.GetMovieIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, movieID := range movieIDs {
+		if err := m.refreshMovie(movieID); err != nil {
+			m.logger.Warn("failed to refresh movie metadata", "movie_id", movieID, "error", err)
+			report.Failed++
+			continue
+		}
+		report.Movies++
+	}
+
+	tvShowIDs, err := m.mediaRepository.GetTvShowIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, tvShowID := range tvShowIDs {
+		if err := m.refreshTvShow(tvShowID); err != nil {
+			m.logger.Warn("failed to refresh tv show metadata", "tv_show_id", tvShowID, "error", err)
+			report.Failed++
+			continue
+		}
+		report.TvShows++
+		episodes, failed := m.refreshEpisodes(tvShowID)
+		report.Episodes += episodes
+		report.Failed += failed
+	}
+	return report, nil
+}
+
+func (m *MetadataRefresh) refreshMovie(movieID int) error {
+	movie, err := m.mediaClient.GetMovie(movieID)
+	if err != nil {
+		return err
+	}
+	return m.mediaRepository.UpdateMovie(movie)
+}
+
+func (m *MetadataRefresh) refreshTvShow(tvShowID int) error {
+	tvShow, err := m.mediaClient.GetTVShow(tvShowID)
+	if err != nil {
+		return err
+	}
+	return m.mediaRepository.UpdateTvShow(tvShow)
+}
+
+// refreshEpisodes refreshes the stored episodes of a tv show season by season, matching them by ID
+// so that episodes renumbered on TMDB are moved too. It returns the number of refreshed and failed episodes.
+func (m *MetadataRefresh) refreshEpisodes(tvShowID int) (int, int) {
+	episodes, err := m.mediaRepository.GetTvShowEpisodes(tvShowID)
+	if err != nil {
+		m.logger.Warn("failed to list tv show episodes", "tv_show_id", tvShowID, "error", err)
+		return 0, 1
+	}
+	stored := make(map[int]bool, len(episodes))
+	seasons := make(map[int]bool)
+	for _, episode := range episodes {
+		stored[episode.ID] = true
+		seasons[episode.NbSeason] = true
+	}
+
+	refreshed := 0
+	for season := range seasons {
+		seasonEpisodes, err := m.mediaClient.GetTVSeasonEpisodes(tvShowID, season)
+		if err != nil {
+			m.logger.Warn("failed to fetch tv show season", "tv_show_id", tvShowID, "season", season, "error", err)
+			continue
+		}
+		for _, episode := range seasonEpisodes {
+			if !stored[episode.ID] {
+				continue
+			}
+			if err := m.mediaRepository.UpdateEpisode(episode); err != nil {
+				m.logger.Warn("failed to refresh episode metadata", "episode_id", episode.ID, "error", err)
+				continue
+			}
+			delete(stored, episode.ID)
+			refreshed++
+		}
+	}
+	return refreshed, len(stored)
+}
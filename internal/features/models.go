@@ -4,6 +4,19 @@ import "errors"
 
 var ErrMediaNotFound = errors.New("media not found")
 var ErrInvalidMediaType = errors.New("invalid media type")
+var ErrNotInWatchList = errors.New("media not in watch list")
+var ErrInvalidWatchListStatus = errors.New("invalid watch list status")
+var ErrInvalidWatchListImport = errors.New("invalid watch list import")
+var ErrInvalidTrendingWindow = errors.New("invalid trending window")
+var ErrDeletionJobNotFound = errors.New("deletion job not found")
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+var ErrSubtitleNotFound = errors.New("subtitle not found")
+var ErrInvalidSubtitle = errors.New("invalid subtitle")
+var ErrInvalidSubtitleFormat = errors.New("invalid subtitle format")
+var ErrInvalidLanguage = errors.New("invalid language")
+var ErrInvalidSubtitleShift = errors.New("invalid subtitle shift")
+var ErrDuplicateNotFound = errors.New("duplicate not found")
+var ErrInvalidDate = errors.New("invalid date")
 
 type Rating struct {
 	Rating float32 `json:"rating"`
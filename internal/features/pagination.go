@@ -0,0 +1,20 @@
+package features
+
+import "github.com/bingemate/media-service/internal/repository"
+
+// maxPageSize bounds the number of items a client can ask for in a single page
+const maxPageSize = 100
+
+// pageSize returns the page size asked by a client within [1, maxPageSize], defaultSize when it is not set
+func pageSize(limit, defaultSize int) int {
+	if limit <= 0 {
+		return defaultSize
+	}
+	return min(limit, maxPageSize)
+}
+
+// withPageSize returns page with the page size asked by a client, defaultSize when it is not set
+func withPageSize(page repository.Page, defaultSize int) repository.Page {
+	page.Limit = pageSize(page.Limit, defaultSize)
+	return page
+}
@@ -0,0 +1,217 @@
+package features
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
+	"gorm.io/gorm"
+	"log/slog"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// playlistURIAttribute matches the URI attribute of the playlist tags, such as #EXT-X-MAP:URI="init.mp4"
+var playlistURIAttribute = regexp.MustCompile(`URI="([^"]+)"`)
+
+// PlaybackTrack is a signed URL of an audio or subtitle track
+type PlaybackTrack struct {
+	Language string
+	URL      string
+}
+
+// PlaybackURLs are the signed URLs reading a media file until ExpiresAt
+type PlaybackURLs struct {
+	ExpiresAt time.Time
+	Manifest  string
+	Audios    []PlaybackTrack
+	Subtitles []PlaybackTrack
+}
+
+// Playback signs the URLs reading the media files, so that the bucket can be private.
+//
+// Subtitles are presigned URLs of the bucket. HLS playlists referencing their segments by relative URIs, which a
+// presigned URL cannot cover, the playlist URLs point to the service instead: it serves them with their segments
+// replaced by presigned URLs. A playlist URL is signed with an HMAC of its key and expiry time, keyed with the
+// playback signing key, so that only the URLs issued by the service are served.
+type Playback struct {
+	mediaRepository *repository.MediaRepository
+	bucket          *storage.Bucket
+	signingKey      []byte
+	playlistPath    string
+	expiry          time.Duration
+	logger          *slog.Logger
+}
+
+// NewPlayback returns the service signing URLs valid for expiry, playlistPath being the path serving the playlists
+func NewPlayback(mediaRepository *repository.MediaRepository, bucket *storage.Bucket, signingKey, playlistPath string, expiry time.Duration) *Playback {
+	return &Playback{
+		mediaRepository: mediaRepository,
+		bucket:          bucket,
+		signingKey:      []byte(signingKey),
+		playlistPath:    playlistPath,
+		expiry:          expiry,
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (p *Playback) WithContext(ctx context.Context) *Playback {
+	return &Playback{
+		mediaRepository: p.mediaRepository.WithContext(ctx),
+		bucket:          p.bucket,
+		signingKey:      p.signingKey,
+		playlistPath:    p.playlistPath,
+		expiry:          p.expiry,
+		logger:          logging.FromContext(ctx),
+	}
+}
+
+// GetMoviePlaybackURLs returns the signed URLs of the video, audios and subtitles of a movie
+func (p *Playback) GetMoviePlaybackURLs(movieID int) (*PlaybackURLs, error) {
+	prefix, file, err := p.movieFile(movieID)
+	if err != nil {
+		return nil, err
+	}
+	return p.playbackURLs(prefix, file)
+}
+
+// GetEpisodePlaybackURLs returns the signed URLs of the video, audios and subtitles of an episode
+func (p *Playback) GetEpisodePlaybackURLs(episodeID int) (*PlaybackURLs, error) {
+	prefix, file, err := p.episodeFile(episodeID)
+	if err != nil {
+		return nil, err
+	}
+	return p.playbackURLs(prefix, file)
+}
+
+// movieFile returns the storage prefix and the file of a movie
+func (p *Playback) movieFile(movieID int) (string, *repository2.MediaFile, error) {
+	file, err := p.mediaRepository.GetMovieFileInfo(movieID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && file == nil {
+		return "", nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return storagePrefix(movieStoragePrefix, movieID), file, nil
+}
+
+// episodeFile returns the storage prefix and the file of an episode
+func (p *Playback) episodeFile(episodeID int) (string, *repository2.MediaFile, error) {
+	file, err := p.mediaRepository.GetEpisodeFileInfo(episodeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && file == nil {
+		return "", nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return storagePrefix(tvShowStoragePrefix, episodeID), file, nil
+}
+
+func (p *Playback) playbackURLs(prefix string, file *repository2.MediaFile) (*PlaybackURLs, error) {
+	expiresAt := time.Now().Add(p.expiry).Truncate(time.Second)
+	urls := &PlaybackURLs{
+		ExpiresAt: expiresAt,
+		Manifest:  p.playlistURL(path.Join(prefix, file.Filename), expiresAt),
+		Audios:    make([]PlaybackTrack, len(file.Audios)),
+		Subtitles: make([]PlaybackTrack, len(file.Subtitles)),
+	}
+	for i, audio := range file.Audios {
+		urls.Audios[i] = PlaybackTrack{
+			Language: audio.Language,
+			URL:      p.playlistURL(path.Join(prefix, audio.Filename), expiresAt),
+		}
+	}
+	for i, subtitle := range file.Subtitles {
+		presigned, err := p.bucket.PresignGet(path.Join(prefix, subtitle.Filename), p.expiry)
+		if err != nil {
+			return nil, err
+		}
+		urls.Subtitles[i] = PlaybackTrack{Language: subtitle.Language, URL: presigned}
+	}
+	return urls, nil
+}
+
+// GetPlaylist returns the playlist of the bucket at key if the signature matches and has not expired,
+// its segment URIs being replaced by presigned URLs expiring at the same time. The keys of the subtitles
+// followed by .m3u8 are the playlists generated for them, see subtitlePlaylist.
+func (p *Playback) GetPlaylist(key string, expires int64, signature string) ([]byte, error) {
+	expiresAt := time.Unix(expires, 0)
+	if !hmac.Equal([]byte(signature), []byte(p.sign(key, expiresAt))) || time.Now().After(expiresAt) {
+		return nil, ErrInvalidSignature
+	}
+	if subtitle := strings.TrimSuffix(key, subtitlePlaylistSuffix); path.Ext(subtitle) == ".vtt" {
+		return p.subtitlePlaylist(subtitle, expiresAt)
+	}
+	playlist, err := p.bucket.GetObject(key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.signPlaylist(playlist, key, expiresAt)
+}
+
+// signPlaylist replaces the relative URIs of the playlist at key by signed URLs expiring at expiresAt
+func (p *Playback) signPlaylist(playlist []byte, key string, expiresAt time.Time) ([]byte, error) {
+	directory := path.Dir(key)
+	var signErr error
+	uri := func(reference string) string {
+		if strings.Contains(reference, "://") {
+			return reference
+		}
+		target := path.Join(directory, reference)
+		if path.Ext(target) == ".m3u8" {
+			return p.playlistURL(target, expiresAt)
+		}
+		presigned, err := p.bucket.PresignGet(target, time.Until(expiresAt))
+		if err != nil {
+			signErr = err
+		}
+		return presigned
+	}
+	lines := bytes.Split(playlist, []byte("\n"))
+	for i, line := range lines {
+		line = bytes.TrimRight(line, "\r")
+		switch {
+		case len(line) == 0:
+		case line[0] == '#':
+			lines[i] = playlistURIAttribute.ReplaceAllFunc(line, func(attribute []byte) []byte {
+				reference := playlistURIAttribute.FindSubmatch(attribute)[1]
+				return []byte(`URI="` + uri(string(reference)) + `"`)
+			})
+		default:
+			lines[i] = []byte(uri(string(line)))
+		}
+	}
+	if signErr != nil {
+		return nil, signErr
+	}
+	return bytes.Join(lines, []byte("\n")), nil
+}
+
+// playlistURL returns the URL of the service serving the playlist at key until expiresAt
+func (p *Playback) playlistURL(key string, expiresAt time.Time) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", p.sign(key, expiresAt))
+	return p.playlistPath + key + "?" + query.Encode()
+}
+
+func (p *Playback) sign(key string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, p.signingKey)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,7 @@
 package features
 
 import (
+	"context"
 	"errors"
 	repository2 "github.com/bingemate/media-go-pkg/repository"
 	"github.com/bingemate/media-service/internal/repository"
@@ -15,28 +16,37 @@ func NewRatingService(mediaRepository *repository.MediaRepository) *RatingServic
 	return &RatingService{mediaRepository}
 }
 
+// WithContext returns a copy of the service bound to the given request context
+func (s *RatingService) WithContext(ctx context.Context) *RatingService {
+	return &RatingService{s.mediaRepository.WithContext(ctx)}
+}
+
 //func (s *RatingService) GetMediaRating(mediaID, page int) ([]*repository2.Rating, int, error) {
 //	return s.mediaRepository.GetMediaRatings(mediaID, 10, page)
 //}
 
-func (s *RatingService) GetMovieRatings(movieID, page int) ([]*repository2.MovieRating, int, error) {
-	return s.mediaRepository.GetMovieRatings(movieID, 10, page)
+// GetMovieRatings returns a page of the ratings of a movie, the most recent first
+func (s *RatingService) GetMovieRatings(movieID int, page repository.Page) (*repository.CursorPage[*repository2.MovieRating], error) {
+	return s.mediaRepository.GetMovieRatings(movieID, withPageSize(page, 10))
 }
 
-func (s *RatingService) GetTvShowRatings(tvShowID, page int) ([]*repository2.TvShowRating, int, error) {
-	return s.mediaRepository.GetTvShowRatings(tvShowID, 10, page)
+// GetTvShowRatings returns a page of the ratings of a tv show, the most recent first
+func (s *RatingService) GetTvShowRatings(tvShowID int, page repository.Page) (*repository.CursorPage[*repository2.TvShowRating], error) {
+	return s.mediaRepository.GetTvShowRatings(tvShowID, withPageSize(page, 10))
 }
 
 //func (s *RatingService) GetUsersRating(userID string, page int) ([]*repository2.Rating, int, error) {
 //	return s.mediaRepository.GetUserRatings(userID, 10, page)
 //}
 
-func (s *RatingService) GetUsersMovieRatings(userID string, page int) ([]*repository2.MovieRating, int, error) {
-	return s.mediaRepository.GetUserMovieRatings(userID, 10, page)
+// GetUsersMovieRatings returns a page of the movie ratings of a user, the most recent first
+func (s *RatingService) GetUsersMovieRatings(userID string, page repository.Page) (*repository.CursorPage[*repository2.MovieRating], error) {
+	return s.mediaRepository.GetUserMovieRatings(userID, withPageSize(page, 10))
 }
 
-func (s *RatingService) GetUsersTvShowRatings(userID string, page int) ([]*repository2.TvShowRating, int, error) {
-	return s.mediaRepository.GetUserTvShowRatings(userID, 10, page)
+// GetUsersTvShowRatings returns a page of the tv show ratings of a user, the most recent first
+func (s *RatingService) GetUsersTvShowRatings(userID string, page repository.Page) (*repository.CursorPage[*repository2.TvShowRating], error) {
+	return s.mediaRepository.GetUserTvShowRatings(userID, withPageSize(page, 10))
 }
 
 //func (s *RatingService) GetUserMediaRating(userID string, mediaID int) (*repository2.Rating, error) {
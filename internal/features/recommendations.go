@@ -0,0 +1,310 @@
+package features
+
+import (
+	"context"
+	"github.com/bingemate/media-service/internal/repository"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// recommendationRefreshInterval is the time between two computations of the similarities between titles
+	recommendationRefreshInterval = time.Hour
+	// minCommonRaters is the number of users having rated two titles needed to compare them
+	minCommonRaters = 2
+	// similarityShrinkage lowers the similarity of titles compared on few users
+	similarityShrinkage = 5
+	// maxReasons is the number of liked titles given to explain a recommendation
+	maxReasons = 3
+	// likedRating is the rating from which a title is liked by a user whose ratings do not vary
+	likedRating = 4
+)
+
+// title identifies a movie or a tv show
+type title struct {
+	Type repository.SavedMediaType
+	ID   int
+}
+
+// Recommendation is an available movie or tv show recommended to a user.
+// Because lists the titles the user liked that are the most similar to it, the most similar first, leaving out the
+// ones rated since the last refresh of the index, whose names are not known yet.
+type Recommendation struct {
+	repository.SavedMedia
+	Score   float64
+	Because []repository.SavedMedia
+}
+
+// similarTitle is a title with its similarity to another one
+type similarTitle struct {
+	title      title
+	similarity float64
+}
+
+// RecommendationIndex keeps the similarities between the rated titles and the available titles, and the names of
+// both. They are computed in memory from all the ratings when started, then again every hour.
+type RecommendationIndex struct {
+	mutex           sync.RWMutex
+	mediaRepository *repository.MediaRepository
+	available       map[title]repository.SavedMedia
+	rated           map[title]repository.SavedMedia
+	similarities    map[title][]similarTitle
+}
+
+// NewRecommendationIndex returns the index of the rated titles, empty until it is started
+func NewRecommendationIndex(mediaRepository *repository.MediaRepository) *RecommendationIndex {
+	return &RecommendationIndex{mediaRepository: mediaRepository}
+}
+
+// Start computes the similarities now, then refreshes them in the background every recommendationRefreshInterval
+// until ctx is done
+func (r *RecommendationIndex) Start(ctx context.Context) error {
+	if err := r.Refresh(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(recommendationRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Refresh(); err != nil {
+					slog.Error("error refreshing recommendations", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Refresh computes the similarities again from all the ratings, and loads the available and rated titles
+func (r *RecommendationIndex) Refresh() error {
+	ratings, err := r.mediaRepository.GetAllRatings()
+	if err != nil {
+		return err
+	}
+	availableTitles, err := r.mediaRepository.GetAvailableTitles()
+	if err != nil {
+		return err
+	}
+	ratedTitles, err := r.mediaRepository.GetRatedTitles()
+	if err != nil {
+		return err
+	}
+	similarities := computeSimilarities(centeredRatings(ratings))
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.available = titlesByKey(availableTitles)
+	r.rated = titlesByKey(ratedTitles)
+	r.similarities = similarities
+	return nil
+}
+
+func titlesByKey(titles []repository.SavedMedia) map[title]repository.SavedMedia {
+	byKey := make(map[title]repository.SavedMedia, len(titles))
+	for _, media := range titles {
+		byKey[title{media.Type, media.ID}] = media
+	}
+	return byKey
+}
+
+// GetRecommendations recommends available movies and tv shows a user did not rate yet, from the ratings of the user
+// and the similarities between titles. types restricts the recommended kinds (SavedMovie, SavedTvShow), both if it is empty.
+// The ratings of the user are read at each call, so that a title just rated counts, while the similarities are the
+// ones of the last refresh of the index.
+func (m *MediaDiscovery) GetRecommendations(userID string, types []repository.SavedMediaType, limit int) ([]*Recommendation, error) {
+	ratings, err := m.mediaRepository.GetUserRatings(userID)
+	if err != nil {
+		return nil, err
+	}
+	index := m.recommendationIndex
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	return scoreRecommendations(userPreferences(ratings, userID), index.similarities, index.available, index.rated, types, pageSize(limit, 20)), nil
+}
+
+// computeSimilarities returns the titles similar to each rated title, the similarity being positive.
+//
+// It is an item-item collaborative filtering: two titles are similar when the users rated them alike, which is
+// measured by the cosine of the ratings centered on the mean rating of each user, lowered when few users rated both.
+func computeSimilarities(centered map[string]map[title]float64) map[title][]similarTitle {
+	// products of the centered ratings of each pair of titles, over the users having rated both
+	type pairStats struct {
+		dot, firstSquares, secondSquares float64
+		raters                           int
+	}
+	pairs := make(map[[2]title]*pairStats)
+	for _, userRatings := range centered {
+		for first, firstRating := range userRatings {
+			for second, secondRating := range userRatings {
+				if !titleLess(first, second) {
+					continue
+				}
+				key := [2]title{first, second}
+				stats, ok := pairs[key]
+				if !ok {
+					stats = &pairStats{}
+					pairs[key] = stats
+				}
+				stats.dot += firstRating * secondRating
+				stats.firstSquares += firstRating * firstRating
+				stats.secondSquares += secondRating * secondRating
+				stats.raters++
+			}
+		}
+	}
+
+	similarities := make(map[title][]similarTitle)
+	for key, stats := range pairs {
+		if stats.raters < minCommonRaters || stats.firstSquares == 0 || stats.secondSquares == 0 {
+			continue
+		}
+		similarity := stats.dot / math.Sqrt(stats.firstSquares*stats.secondSquares)
+		similarity *= float64(stats.raters) / float64(stats.raters+similarityShrinkage)
+		if similarity <= 0 {
+			continue
+		}
+		first, second := key[0], key[1]
+		similarities[first] = append(similarities[first], similarTitle{second, similarity})
+		similarities[second] = append(similarities[second], similarTitle{first, similarity})
+	}
+	return similarities
+}
+
+// titleLess orders the titles by type then ID
+func titleLess(a, b title) bool {
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.ID < b.ID
+}
+
+// scoreRecommendations returns the available titles of types not rated by the user, the best scored first.
+// The score of a title is the mean of the user's centered ratings weighted by the similarities,
+// so that titles close to the ones the user liked come first. The liked titles given as reasons are named from rated.
+func scoreRecommendations(userRatings map[title]float64, similarities map[title][]similarTitle, available, rated map[title]repository.SavedMedia, types []repository.SavedMediaType, limit int) []*Recommendation {
+	searched := func(candidate title) bool {
+		if _, ok := available[candidate]; !ok {
+			return false
+		}
+		if _, rated := userRatings[candidate]; rated {
+			return false
+		}
+		if len(types) == 0 {
+			return true
+		}
+		for _, t := range types {
+			if t == candidate.Type {
+				return true
+			}
+		}
+		return false
+	}
+
+	type candidateScore struct {
+		weighted, weights float64
+		liked             []similarTitle
+	}
+	candidates := make(map[title]*candidateScore)
+	for rated, rating := range userRatings {
+		for _, similar := range similarities[rated] {
+			if !searched(similar.title) {
+				continue
+			}
+			score, ok := candidates[similar.title]
+			if !ok {
+				score = &candidateScore{}
+				candidates[similar.title] = score
+			}
+			score.weighted += similar.similarity * rating
+			score.weights += similar.similarity
+			if rating > 0 {
+				score.liked = append(score.liked, similarTitle{rated, similar.similarity})
+			}
+		}
+	}
+
+	recommendations := make([]*Recommendation, 0, len(candidates))
+	for candidate, score := range candidates {
+		if len(score.liked) == 0 || score.weighted <= 0 {
+			continue
+		}
+		sort.Slice(score.liked, func(i, j int) bool {
+			if score.liked[i].similarity != score.liked[j].similarity {
+				return score.liked[i].similarity > score.liked[j].similarity
+			}
+			return titleLess(score.liked[i].title, score.liked[j].title)
+		})
+		recommendation := &Recommendation{
+			SavedMedia: available[candidate],
+			Score:      score.weighted / score.weights,
+		}
+		for _, liked := range score.liked[:min(len(score.liked), maxReasons)] {
+			if reason, ok := rated[liked.title]; ok {
+				recommendation.Because = append(recommendation.Because, reason)
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].ID < recommendations[j].ID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations
+}
+
+// userPreferences returns the centered ratings of a user. When they do not vary, a single rating or identical
+// ratings telling nothing against the mean, the titles rated at least likedRating count 1 and the others -1.
+func userPreferences(ratings []repository.UserRating, userID string) map[title]float64 {
+	preferences := centeredRatings(ratings)[userID]
+	for _, preference := range preferences {
+		if preference != 0 {
+			return preferences
+		}
+	}
+	for _, rating := range ratings {
+		if rating.UserID != userID {
+			continue
+		}
+		preference := -1.0
+		if rating.Rating >= likedRating {
+			preference = 1
+		}
+		preferences[title{rating.Type, rating.MediaID}] = preference
+	}
+	return preferences
+}
+
+// centeredRatings returns the ratings of each user minus the mean rating of the user, by user ID then title
+func centeredRatings(ratings []repository.UserRating) map[string]map[title]float64 {
+	byUser := make(map[string]map[title]float64)
+	for _, rating := range ratings {
+		userRatings, ok := byUser[rating.UserID]
+		if !ok {
+			userRatings = make(map[title]float64)
+			byUser[rating.UserID] = userRatings
+		}
+		userRatings[title{rating.Type, rating.MediaID}] = float64(rating.Rating)
+	}
+	for _, userRatings := range byUser {
+		var sum float64
+		for _, rating := range userRatings {
+			sum += rating
+		}
+		mean := sum / float64(len(userRatings))
+		for t := range userRatings {
+			userRatings[t] -= mean
+		}
+	}
+	return byUser
+}
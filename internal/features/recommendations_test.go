@@ -0,0 +1,154 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"math"
+	"testing"
+)
+
+func movie(id int) title {
+	return title{repository.SavedMovie, id}
+}
+
+func tvShow(id int) title {
+	return title{repository.SavedTvShow, id}
+}
+
+// recommendationRatings has two users liking movies 1 and 2 and disliking movie 3, the tv show 1 being
+// liked along with movie 3 and movie 4 being rated by a single user
+func recommendationRatings() []repository.UserRating {
+	rating := func(userID string, t title, value int) repository.UserRating {
+		return repository.UserRating{UserID: userID, Type: t.Type, MediaID: t.ID, Rating: value}
+	}
+	return []repository.UserRating{
+		rating("alice", movie(1), 5), rating("alice", movie(2), 5), rating("alice", movie(3), 1), rating("alice", tvShow(1), 1),
+		rating("bob", movie(1), 4), rating("bob", movie(2), 5), rating("bob", movie(3), 2), rating("bob", tvShow(1), 2),
+		rating("bob", movie(4), 5),
+		rating("carol", movie(1), 5), rating("carol", movie(3), 1),
+	}
+}
+
+func recommendationAvailable(titles ...title) map[title]repository.SavedMedia {
+	available := make(map[title]repository.SavedMedia, len(titles))
+	for _, t := range titles {
+		available[t] = repository.SavedMedia{Type: t.Type, ID: t.ID}
+	}
+	return available
+}
+
+func TestCenteredRatings(t *testing.T) {
+	centered := centeredRatings(recommendationRatings())
+	if got := centered["carol"]; got[movie(1)] != 2 || got[movie(3)] != -2 {
+		t.Errorf("centered ratings of carol = %v, want 2 and -2", got)
+	}
+	var sum float64
+	for _, rating := range centered["bob"] {
+		sum += rating
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("centered ratings of bob sum to %v, want 0", sum)
+	}
+}
+
+func TestComputeSimilarities(t *testing.T) {
+	similarities := computeSimilarities(centeredRatings(recommendationRatings()))
+	find := func(a, b title) (float64, bool) {
+		for _, similar := range similarities[a] {
+			if similar.title == b {
+				return similar.similarity, true
+			}
+		}
+		return 0, false
+	}
+	forward, ok := find(movie(1), movie(2))
+	if !ok {
+		t.Fatal("movies 1 and 2 are not similar")
+	}
+	if backward, _ := find(movie(2), movie(1)); backward != forward {
+		t.Errorf("similarity of movies 2 and 1 = %v, want %v", backward, forward)
+	}
+	if forward <= 0 || forward >= 1 {
+		t.Errorf("similarity of movies 1 and 2 = %v, want it shrunk within (0, 1)", forward)
+	}
+	if _, ok := find(movie(1), movie(3)); ok {
+		t.Error("movies 1 and 3 rated oppositely are similar")
+	}
+	if _, ok := find(movie(2), movie(4)); ok {
+		t.Errorf("movie 4 rated by a single user is similar to movie 2")
+	}
+}
+
+func TestScoreRecommendations(t *testing.T) {
+	centered := centeredRatings(append(recommendationRatings(),
+		repository.UserRating{UserID: "dave", Type: repository.SavedMovie, MediaID: 1, Rating: 5},
+		repository.UserRating{UserID: "dave", Type: repository.SavedMovie, MediaID: 3, Rating: 1},
+	))
+	similarities := computeSimilarities(centered)
+	available := recommendationAvailable(movie(1), movie(2), movie(3), movie(4), tvShow(1))
+
+	recommendations := scoreRecommendations(centered["dave"], similarities, available, available, nil, 20)
+	if len(recommendations) != 1 {
+		t.Fatalf("got %d recommendations, want 1", len(recommendations))
+	}
+	recommendation := recommendations[0]
+	if recommendation.Type != repository.SavedMovie || recommendation.ID != 2 {
+		t.Errorf("recommended %s %d, want movie 2", recommendation.Type, recommendation.ID)
+	}
+	if recommendation.Score <= 0 {
+		t.Errorf("score = %v, want it positive", recommendation.Score)
+	}
+	if len(recommendation.Because) != 1 || recommendation.Because[0].ID != 1 {
+		t.Errorf("because = %v, want movie 1", recommendation.Because)
+	}
+
+	unavailable := recommendationAvailable(movie(2), movie(3), movie(4), tvShow(1))
+	rated := map[title]repository.SavedMedia{movie(1): {Type: repository.SavedMovie, ID: 1, Name: "Alpha"}}
+	if got := scoreRecommendations(centered["dave"], similarities, unavailable, rated, nil, 20); len(got) != 1 || len(got[0].Because) != 1 || got[0].Because[0].Name != "Alpha" {
+		t.Errorf("recommendations = %v, want movie 2 because of Alpha, not available anymore", got)
+	}
+	if got := scoreRecommendations(centered["dave"], similarities, unavailable, nil, nil, 20); len(got) != 1 || len(got[0].Because) != 0 {
+		t.Errorf("recommendations = %v, want movie 2 without the unknown liked title", got)
+	}
+
+	if got := scoreRecommendations(centered["dave"], similarities, available, available, []repository.SavedMediaType{repository.SavedTvShow}, 20); len(got) != 0 {
+		t.Errorf("got %d tv show recommendations, want none", len(got))
+	}
+	if got := scoreRecommendations(centered["dave"], similarities, recommendationAvailable(movie(1), movie(3)), available, nil, 20); len(got) != 0 {
+		t.Errorf("got %d recommendations without movie 2 available, want none", len(got))
+	}
+	if got := scoreRecommendations(centered["carol"], similarities, available, available, nil, 20); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("recommendations of carol = %v, want movie 2", got)
+	}
+	if got := scoreRecommendations(nil, similarities, available, available, nil, 20); len(got) != 0 {
+		t.Errorf("got %d recommendations without ratings, want none", len(got))
+	}
+}
+
+func TestUserPreferences(t *testing.T) {
+	rating := func(userID string, t title, value int) repository.UserRating {
+		return repository.UserRating{UserID: userID, Type: t.Type, MediaID: t.ID, Rating: value}
+	}
+	ratings := append(recommendationRatings(),
+		rating("erin", movie(1), 5),
+		rating("frank", movie(1), 4), rating("frank", movie(3), 4),
+		rating("grace", movie(1), 2),
+	)
+	similarities := computeSimilarities(centeredRatings(ratings))
+	available := recommendationAvailable(movie(1), movie(2), movie(3), movie(4), tvShow(1))
+
+	if got := userPreferences(ratings, "carol"); got[movie(1)] != 2 || got[movie(3)] != -2 {
+		t.Errorf("preferences of carol = %v, want the centered ratings", got)
+	}
+	if got := userPreferences(ratings, "frank"); got[movie(1)] != 1 || got[movie(3)] != 1 {
+		t.Errorf("preferences of frank = %v, want both liked", got)
+	}
+	if got := scoreRecommendations(userPreferences(ratings, "erin"), similarities, available, available, nil, 20); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("recommendations of erin = %v, want movie 2", got)
+	}
+	if got := scoreRecommendations(userPreferences(ratings, "grace"), similarities, available, available, nil, 20); len(got) != 0 {
+		t.Errorf("got %d recommendations for grace, want none", len(got))
+	}
+	if got := userPreferences(ratings, "nobody"); len(got) != 0 {
+		t.Errorf("preferences without ratings = %v, want none", got)
+	}
+}
@@ -0,0 +1,249 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+type RetentionReason string
+
+const (
+	// RetentionUnwatched is the reason of the files not watched since RetentionPolicy.UnwatchedDays
+	RetentionUnwatched RetentionReason = "UNWATCHED"
+	// RetentionOldSeason is the reason of the episodes not in the RetentionPolicy.KeepSeasons latest seasons
+	RetentionOldSeason RetentionReason = "OLD_SEASON"
+)
+
+// RetentionPolicy tells which movie and episode files are deleted to free some storage. A rule set to 0 is disabled.
+//   - UnwatchedDays: the files not watched for this number of days. A file never watched counts from its addition,
+//     or from the start of the view tracking if it was added before, as its former views were not logged.
+//   - KeepSeasons: the episodes of the seasons older than this number of latest seasons of their tv show having
+//     files, the specials (season 0) being kept
+//   - ProtectRating: the titles rated above this on average are never deleted, a title rated exactly this is not
+//
+// The files matched by a rule are only reported unless Delete is set. The policy is applied every Interval.
+type RetentionPolicy struct {
+	UnwatchedDays int
+	KeepSeasons   int
+	ProtectRating float64
+	Delete        bool
+	Interval      time.Duration
+}
+
+// Enabled reports whether any rule selects files to delete
+func (p RetentionPolicy) Enabled() bool {
+	return p.UnwatchedDays > 0 || p.KeepSeasons > 0
+}
+
+// RetentionCandidate is a file selected by the retention policy, with the rules selecting it
+type RetentionCandidate struct {
+	repository.RetentionFile
+	Reasons []RetentionReason
+}
+
+// RetentionReport lists the files selected by the retention policy among the CheckedFiles, ReclaimableSize
+// being their total size. Protected counts the files selected but kept for their rating, Pending the ones skipped
+// as they are already being deleted. When the files were deleted, Deleted is set and Jobs lists the deletion jobs
+// started, Errors the deletions which could not start.
+type RetentionReport struct {
+	EvaluatedAt     time.Time
+	Policy          RetentionPolicy
+	CheckedFiles    int
+	Candidates      []RetentionCandidate
+	ReclaimableSize int64
+	Protected       int
+	Pending         int
+	Deleted         bool
+	Jobs            []*repository.DeletionJob
+	Errors          []string
+}
+
+// Retention applies the retention policy: it reports the files to delete and deletes them if the policy says so,
+// through the deletion jobs of MediaFile. The last report is kept in memory.
+type Retention struct {
+	mediaRepository *repository.MediaRepository
+	mediaFile       *MediaFile
+	policy          RetentionPolicy
+	runs            *retentionRuns // Shared by the copies bound to a request context.
+	logger          *slog.Logger
+}
+
+// retentionRuns serializes the runs of the policy and keeps the report of the last one
+type retentionRuns struct {
+	mutex      sync.Mutex
+	lastReport *RetentionReport
+}
+
+func NewRetention(mediaRepository *repository.MediaRepository, mediaFile *MediaFile, policy RetentionPolicy) *Retention {
+	return &Retention{
+		mediaRepository: mediaRepository,
+		mediaFile:       mediaFile,
+		policy:          policy,
+		runs:            &retentionRuns{},
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (r *Retention) WithContext(ctx context.Context) *Retention {
+	return &Retention{
+		mediaRepository: r.mediaRepository.WithContext(ctx),
+		mediaFile:       r.mediaFile.WithContext(ctx),
+		policy:          r.policy,
+		runs:            r.runs,
+		logger:          logging.FromContext(ctx),
+	}
+}
+
+// Start applies the policy in the background every policy interval until ctx is done, if any rule is enabled.
+// It is also evaluated at startup, only reporting the selected files, so that no file is deleted before the admins
+// could check a report.
+func (r *Retention) Start(ctx context.Context) {
+	if !r.policy.Enabled() {
+		return
+	}
+	apply := func(deleteFiles bool) {
+		report, err := r.run(deleteFiles)
+		if err != nil {
+			r.logger.Error("error applying the retention policy", "error", err)
+			return
+		}
+		r.logger.Info("retention policy applied", "candidates", len(report.Candidates), "deleted", report.Deleted,
+			"reclaimable_size", report.ReclaimableSize, "errors", len(report.Errors))
+	}
+	go func() {
+		apply(false)
+		ticker := time.NewTicker(r.policy.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				apply(r.policy.Delete)
+			}
+		}
+	}()
+}
+
+// LastReport returns the report of the last run, nil if the policy was not applied yet
+func (r *Retention) LastReport() *RetentionReport {
+	r.runs.mutex.Lock()
+	defer r.runs.mutex.Unlock()
+	return r.runs.lastReport
+}
+
+// Run applies the policy now: the selected files are deleted if the policy says so, then the report is kept
+func (r *Retention) Run() (*RetentionReport, error) {
+	return r.run(r.policy.Delete)
+}
+
+// run applies the policy, deleting the selected files if deleteFiles is set, and keeps the report
+func (r *Retention) run(deleteFiles bool) (*RetentionReport, error) {
+	r.runs.mutex.Lock()
+	defer r.runs.mutex.Unlock()
+	report, err := r.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	if deleteFiles {
+		report.Deleted = true
+		for _, candidate := range report.Candidates {
+			job, err := r.mediaFile.DeleteMediaFile(candidate.MediaFileID)
+			if err != nil {
+				r.logger.Error("error deleting file of the retention policy", "media_file_id", candidate.MediaFileID, "error", err)
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", candidate.MediaFileID, err))
+				continue
+			}
+			report.Jobs = append(report.Jobs, job)
+		}
+	}
+	r.runs.lastReport = report
+	return report, nil
+}
+
+// Evaluate returns the files the policy selects now, without deleting them
+func (r *Retention) Evaluate() (*RetentionReport, error) {
+	files, err := r.mediaRepository.GetRetentionFiles()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := r.mediaRepository.GetActiveDeletionJobs()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		pending[job.MediaFileID] = true
+	}
+	now := time.Now()
+	trackingStart, err := r.mediaRepository.GetViewTrackingStart()
+	if err != nil {
+		return nil, err
+	}
+	if trackingStart == nil {
+		// without view logs, no file can be told unwatched
+		r.logger.Warn("the views are not logged, no file is unwatched")
+		trackingStart = &now
+	}
+	return evaluateRetention(r.policy, files, pending, *trackingStart, now), nil
+}
+
+// evaluateRetention returns the report of the files the policy selects at now, pending telling the files
+// already being deleted and trackingStart when the views started being logged
+func evaluateRetention(policy RetentionPolicy, files []repository.RetentionFile, pending map[string]bool, trackingStart, now time.Time) *RetentionReport {
+	report := &RetentionReport{EvaluatedAt: now, Policy: policy, CheckedFiles: len(files)}
+	keptSeasons := latestSeasons(files, policy.KeepSeasons)
+	for _, file := range files {
+		var reasons []RetentionReason
+		if policy.UnwatchedDays > 0 {
+			lastUse := file.AddedAt
+			if trackingStart.After(lastUse) {
+				lastUse = trackingStart
+			}
+			if file.LastViewed != nil && file.LastViewed.After(lastUse) {
+				lastUse = *file.LastViewed
+			}
+			if now.Sub(lastUse) > time.Duration(policy.UnwatchedDays)*24*time.Hour {
+				reasons = append(reasons, RetentionUnwatched)
+			}
+		}
+		if policy.KeepSeasons > 0 && file.Type == repository.SavedEpisode && file.Season > 0 &&
+			!slices.Contains(keptSeasons[file.TvShowID], file.Season) {
+			reasons = append(reasons, RetentionOldSeason)
+		}
+		switch {
+		case len(reasons) == 0:
+		case policy.ProtectRating > 0 && file.Ratings > 0 && file.Rating > policy.ProtectRating:
+			report.Protected++
+		case pending[file.MediaFileID]:
+			report.Pending++
+		default:
+			report.Candidates = append(report.Candidates, RetentionCandidate{RetentionFile: file, Reasons: reasons})
+			report.ReclaimableSize += file.Size
+		}
+	}
+	return report
+}
+
+// latestSeasons returns the keep latest seasons having episode files of each tv show, by tv show ID
+func latestSeasons(files []repository.RetentionFile, keep int) map[int][]int {
+	seasons := make(map[int][]int)
+	for _, file := range files {
+		if file.Type == repository.SavedEpisode && file.Season > 0 && !slices.Contains(seasons[file.TvShowID], file.Season) {
+			seasons[file.TvShowID] = append(seasons[file.TvShowID], file.Season)
+		}
+	}
+	for tvShowID, showSeasons := range seasons {
+		slices.Sort(showSeasons)
+		slices.Reverse(showSeasons)
+		seasons[tvShowID] = showSeasons[:min(keep, len(showSeasons))]
+	}
+	return seasons
+}
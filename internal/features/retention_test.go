@@ -0,0 +1,197 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLatestSeasons(t *testing.T) {
+	files := []repository.RetentionFile{
+		{Type: repository.SavedEpisode, TvShowID: 1, Season: 1},
+		{Type: repository.SavedEpisode, TvShowID: 1, Season: 3},
+		{Type: repository.SavedEpisode, TvShowID: 1, Season: 2},
+		{Type: repository.SavedEpisode, TvShowID: 1, Season: 3},
+		{Type: repository.SavedEpisode, TvShowID: 1, Season: 0},
+		{Type: repository.SavedEpisode, TvShowID: 2, Season: 1},
+		{Type: repository.SavedMovie, MediaID: 5},
+	}
+	want := map[int][]int{1: {3, 2}, 2: {1}}
+	if got := latestSeasons(files, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("latestSeasons() = %v, want %v", got, want)
+	}
+}
+
+func TestEvaluateRetention(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	viewedDaysAgo := func(days int) *time.Time {
+		viewed := daysAgo(days)
+		return &viewed
+	}
+	files := []repository.RetentionFile{
+		{MediaFileID: "recent", Type: repository.SavedMovie, AddedAt: daysAgo(10), Size: 1},
+		{MediaFileID: "never watched", Type: repository.SavedMovie, AddedAt: daysAgo(100), Size: 2},
+		{MediaFileID: "watched lately", Type: repository.SavedMovie, AddedAt: daysAgo(100), LastViewed: viewedDaysAgo(5), Size: 4},
+		{MediaFileID: "rated above", Type: repository.SavedMovie, AddedAt: daysAgo(100), Rating: 4.5, Ratings: 2, Size: 8},
+		{MediaFileID: "rated exactly", Type: repository.SavedMovie, AddedAt: daysAgo(100), Rating: 4, Ratings: 3, Size: 16},
+		{MediaFileID: "being deleted", Type: repository.SavedMovie, AddedAt: daysAgo(100), Size: 32},
+		{MediaFileID: "old season", Type: repository.SavedEpisode, TvShowID: 1, Season: 1, AddedAt: daysAgo(1), Size: 64},
+		{MediaFileID: "old and unwatched", Type: repository.SavedEpisode, TvShowID: 1, Season: 1, AddedAt: daysAgo(100), Size: 128},
+		{MediaFileID: "latest season", Type: repository.SavedEpisode, TvShowID: 1, Season: 2, AddedAt: daysAgo(1), Size: 256},
+		{MediaFileID: "special", Type: repository.SavedEpisode, TvShowID: 1, Season: 0, AddedAt: daysAgo(1), Size: 512},
+	}
+	pending := map[string]bool{"being deleted": true}
+	tests := []struct {
+		name          string
+		policy        RetentionPolicy
+		want          map[string][]RetentionReason
+		wantProtected int
+		wantPending   int
+	}{
+		{
+			name:   "disabled",
+			policy: RetentionPolicy{ProtectRating: 4},
+			want:   map[string][]RetentionReason{},
+		},
+		{
+			name:   "unwatched",
+			policy: RetentionPolicy{UnwatchedDays: 30},
+			want: map[string][]RetentionReason{
+				"never watched":     {RetentionUnwatched},
+				"rated above":       {RetentionUnwatched},
+				"rated exactly":     {RetentionUnwatched},
+				"old and unwatched": {RetentionUnwatched},
+			},
+			wantPending: 1,
+		},
+		{
+			name:   "old seasons",
+			policy: RetentionPolicy{KeepSeasons: 1},
+			want: map[string][]RetentionReason{
+				"old season":        {RetentionOldSeason},
+				"old and unwatched": {RetentionOldSeason},
+			},
+		},
+		{
+			name:   "both rules, protecting the titles rated above 4",
+			policy: RetentionPolicy{UnwatchedDays: 30, KeepSeasons: 1, ProtectRating: 4},
+			want: map[string][]RetentionReason{
+				"never watched":     {RetentionUnwatched},
+				"rated exactly":     {RetentionUnwatched},
+				"old season":        {RetentionOldSeason},
+				"old and unwatched": {RetentionUnwatched, RetentionOldSeason},
+			},
+			wantProtected: 1,
+			wantPending:   1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := evaluateRetention(test.policy, files, pending, daysAgo(365), now)
+			got := make(map[string][]RetentionReason, len(report.Candidates))
+			var size int64
+			for _, candidate := range report.Candidates {
+				got[candidate.MediaFileID] = candidate.Reasons
+				size += candidate.Size
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("candidates = %v, want %v", got, test.want)
+			}
+			if report.ReclaimableSize != size {
+				t.Errorf("reclaimable size = %d, want %d", report.ReclaimableSize, size)
+			}
+			if report.Protected != test.wantProtected || report.Pending != test.wantPending {
+				t.Errorf("protected, pending = %d, %d, want %d, %d", report.Protected, report.Pending, test.wantProtected, test.wantPending)
+			}
+			if report.CheckedFiles != len(files) || !report.EvaluatedAt.Equal(now) {
+				t.Errorf("checked %d files at %v, want %d at %v", report.CheckedFiles, report.EvaluatedAt, len(files), now)
+			}
+		})
+	}
+}
+
+func TestEvaluateRetentionViewTrackingStart(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	files := []repository.RetentionFile{
+		{MediaFileID: "added before the tracking", Type: repository.SavedMovie, AddedAt: daysAgo(400)},
+		{MediaFileID: "added since the tracking", Type: repository.SavedMovie, AddedAt: daysAgo(5)},
+	}
+	policy := RetentionPolicy{UnwatchedDays: 30}
+	tests := []struct {
+		name          string
+		trackingStart time.Time
+		want          []string
+	}{
+		{"tracking started lately", daysAgo(10), nil},
+		{"tracking started long ago", daysAgo(40), []string{"added before the tracking"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, candidate := range evaluateRetention(policy, files, nil, test.trackingStart, now).Candidates {
+				got = append(got, candidate.MediaFileID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("candidates = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetentionDeletesOnlyTheMovieDirectory(t *testing.T) {
+	db := newTestDB(t)
+	unwatched, watched := "8c9d0e1f-2a3b-4c4d-6e5f-7a8b9c0d1e2f", "9d0e1f2a-3b4c-4d5e-7f6a-8b9c0d1e2f3a"
+	mustExec(t, db, "INSERT INTO media_files (id, filename, size, created_at) VALUES "+
+		"(?, 'video.m3u8', 100, now() - interval '1 year'), (?, 'video.m3u8', 100, now() - interval '1 year')",
+		unwatched, watched)
+	mustExec(t, db, "INSERT INTO movies (id, name, media_file_id) VALUES (12, 'Unwatched', ?), (120, 'Watched', ?)",
+		unwatched, watched)
+	mustExec(t, db, "INSERT INTO movie_view_log (user_id, movie_id, day, seconds) VALUES (?, 120, CURRENT_DATE, 600)",
+		"0e1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a4b")
+	mustExec(t, db, "UPDATE schema_migrations SET applied_at = now() - interval '1 year'")
+	storage := &bucketStorage{keys: []string{
+		"movies/12/video.m3u8",
+		"movies/12/video_000.ts",
+		"movies/120/video.m3u8",
+		"movies/1200/video.m3u8",
+	}}
+	mediaRepository := repository.NewMediaRepository(db)
+	mediaFile := NewMediaFile("", "", mediaRepository, storage, StorageThresholds{})
+	retention := NewRetention(mediaRepository, mediaFile, RetentionPolicy{UnwatchedDays: 30, Delete: true, Interval: time.Hour})
+
+	report, err := retention.Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Jobs) != 1 || report.Jobs[0].MediaFileID != unwatched || report.Jobs[0].Prefix != "movies/12/" {
+		t.Fatalf("Run() jobs = %+v, want the deletion of movies/12/", report.Jobs)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := mediaRepository.GetDeletionJob(report.Jobs[0].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !job.Active() {
+			if job.Status != repository.DeletionDone {
+				t.Fatalf("deletion job status = %s (%s), want %s", job.Status, job.Error, repository.DeletionDone)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the deletion job did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	want := []string{"movies/120/video.m3u8", "movies/1200/video.m3u8"}
+	if got := storage.remaining(); !reflect.DeepEqual(got, want) {
+		t.Errorf("remaining objects = %v, want %v", got, want)
+	}
+}
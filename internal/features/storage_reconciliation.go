@@ -0,0 +1,302 @@
+package features
+
+import (
+	"context"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	movieStoragePrefix  = "movies"
+	tvShowStoragePrefix = "tv-shows"
+)
+
+// storagePrefix returns the storage directory of a movie or episode under root, e.g. movies/12/. The trailing slash
+// keeps it from matching the directories of the IDs it prefixes, such as movies/120/, when objects are listed.
+func storagePrefix(root string, mediaID int) string {
+	return path.Join(root, strconv.Itoa(mediaID)) + "/"
+}
+
+// uploadGracePeriod is the age under which an object is never taken for orphaned, as it may belong to an upload
+// in progress whose file is not saved yet, nor a file for missing, as it may be saved before its upload completes
+const uploadGracePeriod = 24 * time.Hour
+
+// MissingMedia is a media having a file in the database but no objects in the bucket
+type MissingMedia struct {
+	MediaID     int
+	MediaFileID string
+	Prefix      string
+}
+
+// MissingFile is a video, audio or subtitle file of a media missing from its directory in the bucket
+type MissingFile struct {
+	repository.StoredFile
+	MediaID int
+	Key     string
+}
+
+// ReconciliationReport lists the differences between the database and the bucket.
+// Repaired is set when they were fixed, see Reconcile.
+type ReconciliationReport struct {
+	CheckedMovies    int
+	CheckedEpisodes  int
+	MissingObjects   []MissingMedia
+	MissingFiles     []MissingFile
+	OrphanedPrefixes []string
+	OrphanedObjects  []string
+	Repaired         bool
+}
+
+// Consistent reports whether no difference was found
+func (r *ReconciliationReport) Consistent() bool {
+	return len(r.MissingObjects) == 0 && len(r.MissingFiles) == 0 &&
+		len(r.OrphanedPrefixes) == 0 && len(r.OrphanedObjects) == 0
+}
+
+type StorageReconciliation struct {
+	mediaRepository *repository.MediaRepository
+	bucket          *storage.Bucket
+}
+
+func NewStorageReconciliation(mediaRepository *repository.MediaRepository, bucket *storage.Bucket) *StorageReconciliation {
+	return &StorageReconciliation{
+		mediaRepository: mediaRepository,
+		bucket:          bucket,
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (s *StorageReconciliation) WithContext(ctx context.Context) *StorageReconciliation {
+	return &StorageReconciliation{
+		mediaRepository: s.mediaRepository.WithContext(ctx),
+		bucket:          s.bucket,
+	}
+}
+
+// Reconcile compares the movies and episodes having a file with the media directories of the bucket, and the
+// video, audios and subtitles of each file with the objects of its directory. The files pending deletion are skipped.
+//
+// With repair, the differences are fixed once reported: the orphaned directories and objects are deleted from
+// the bucket, and the rows of the missing files are deleted from the database, a media missing its directory
+// or its video losing its file. The objects modified within the upload grace period are neither reported
+// nor deleted as orphaned, nor are the directories having any, as they may belong to uploads in progress.
+// Likewise, the files saved within the grace period are not reported missing.
+func (s *StorageReconciliation) Reconcile(repair bool) (*ReconciliationReport, error) {
+	now := time.Now()
+	movieFiles, err := s.mediaRepository.GetMovieFileIDs()
+	if err != nil {
+		return nil, err
+	}
+	episodeFiles, err := s.mediaRepository.GetEpisodeFileIDs()
+	if err != nil {
+		return nil, err
+	}
+	storedFiles, err := s.mediaRepository.GetStoredFiles()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.mediaRepository.GetActiveDeletionJobs()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		pending[job.MediaFileID] = true
+	}
+	report := &ReconciliationReport{
+		CheckedMovies:   len(movieFiles),
+		CheckedEpisodes: len(episodeFiles),
+	}
+	if err := s.reconcilePrefix(movieStoragePrefix, movieFiles, storedFiles, pending, now, report); err != nil {
+		return nil, err
+	}
+	if err := s.reconcilePrefix(tvShowStoragePrefix, episodeFiles, storedFiles, pending, now, report); err != nil {
+		return nil, err
+	}
+	if repair && !report.Consistent() {
+		if err := s.repair(report, now); err != nil {
+			return report, err
+		}
+		report.Repaired = true
+	}
+	return report, nil
+}
+
+func (s *StorageReconciliation) reconcilePrefix(prefix string, mediaFiles map[int]string, storedFiles map[string][]repository.StoredFile, pending map[string]bool, now time.Time, report *ReconciliationReport) error {
+	directories, err := s.bucket.ListDirectories(prefix + "/")
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]bool, len(directories))
+	for _, directory := range directories {
+		stored[directory] = true
+		mediaID, err := strconv.Atoi(directory)
+		if _, ok := mediaFiles[mediaID]; err == nil && ok {
+			continue
+		}
+		objects, err := s.bucket.ListObjects(prefix + "/" + directory + "/")
+		if err != nil {
+			return err
+		}
+		if len(settledObjects(objects, now)) == len(objects) {
+			report.OrphanedPrefixes = append(report.OrphanedPrefixes, prefix+"/"+directory)
+		}
+	}
+
+	mediaIDs := make([]int, 0, len(mediaFiles))
+	for mediaID := range mediaFiles {
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+	sort.Ints(mediaIDs)
+	for _, mediaID := range mediaIDs {
+		mediaFileID := mediaFiles[mediaID]
+		if pending[mediaFileID] {
+			continue
+		}
+		mediaPrefix := prefix + "/" + strconv.Itoa(mediaID)
+		files := settledFiles(storedFiles[mediaFileID], now)
+		if !stored[strconv.Itoa(mediaID)] {
+			if !slices.ContainsFunc(files, func(file repository.StoredFile) bool {
+				return file.Kind == repository.StoredVideo
+			}) {
+				// the media file was saved within the grace period, its upload may be in progress
+				continue
+			}
+			report.MissingObjects = append(report.MissingObjects, MissingMedia{
+				MediaID:     mediaID,
+				MediaFileID: mediaFileID,
+				Prefix:      mediaPrefix,
+			})
+			continue
+		}
+		objects, err := s.bucket.ListObjects(mediaPrefix + "/")
+		if err != nil {
+			return err
+		}
+		present := make(map[string]bool, len(objects))
+		for _, object := range objects {
+			present[object.Key] = true
+		}
+		for _, file := range files {
+			if !present[file.Filename] {
+				report.MissingFiles = append(report.MissingFiles, MissingFile{
+					StoredFile: file,
+					MediaID:    mediaID,
+					Key:        mediaPrefix + "/" + file.Filename,
+				})
+			}
+		}
+		for _, object := range orphanedObjects(settledObjects(objects, now), storedFiles[mediaFileID]) {
+			report.OrphanedObjects = append(report.OrphanedObjects, mediaPrefix+"/"+object)
+		}
+	}
+	return nil
+}
+
+// orphanedObjects returns the playlists and subtitles of a media directory no file references,
+// with the segments of the orphaned playlists, named after them (audio_1.m3u8 and audio_1_000.ts)
+func orphanedObjects(objects []string, files []repository.StoredFile) []string {
+	referenced := make(map[string]bool, len(files))
+	for _, file := range files {
+		referenced[file.Filename] = true
+	}
+	var orphans []string
+	orphanedPlaylists := make(map[string]bool)
+	for _, object := range objects {
+		extension := path.Ext(object)
+		if (extension == ".m3u8" || extension == ".vtt") && !referenced[object] {
+			orphans = append(orphans, object)
+			orphanedPlaylists[strings.TrimSuffix(object, extension)] = extension == ".m3u8"
+		}
+	}
+	for _, object := range objects {
+		separator := strings.LastIndex(object, "_")
+		if path.Ext(object) == ".ts" && separator > 0 && orphanedPlaylists[object[:separator]] {
+			orphans = append(orphans, object)
+		}
+	}
+	return orphans
+}
+
+// settledObjects returns the keys of the objects modified before the upload grace period
+func settledObjects(objects []storage.Object, now time.Time) []string {
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if now.Sub(object.LastModified) >= uploadGracePeriod {
+			keys = append(keys, object.Key)
+		}
+	}
+	return keys
+}
+
+// settledFiles returns the files saved before the upload grace period
+func settledFiles(files []repository.StoredFile, now time.Time) []repository.StoredFile {
+	settled := make([]repository.StoredFile, 0, len(files))
+	for _, file := range files {
+		if now.Sub(file.CreatedAt) >= uploadGracePeriod {
+			settled = append(settled, file)
+		}
+	}
+	return settled
+}
+
+// repair deletes the orphaned objects from the bucket and the rows of the missing files from the database.
+// The objects of the orphaned directories are listed again, leaving out the ones uploaded since the report,
+// and so are the directories of the missing files, keeping the files uploaded since the report.
+func (s *StorageReconciliation) repair(report *ReconciliationReport, now time.Time) error {
+	orphans := slices.Clone(report.OrphanedObjects)
+	for _, prefix := range report.OrphanedPrefixes {
+		objects, err := s.bucket.ListObjects(prefix + "/")
+		if err != nil {
+			return err
+		}
+		for _, object := range settledObjects(objects, now) {
+			orphans = append(orphans, prefix+"/"+object)
+		}
+	}
+	for _, missing := range report.MissingObjects {
+		objects, err := s.bucket.ListObjects(missing.Prefix + "/")
+		if err != nil {
+			return err
+		}
+		if len(objects) > 0 {
+			continue
+		}
+		if err := s.mediaRepository.DeleteMediaFile(missing.MediaFileID); err != nil {
+			return err
+		}
+	}
+	for _, missing := range report.MissingFiles {
+		objects, err := s.bucket.ListObjects(path.Dir(missing.Key) + "/")
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(objects, func(object storage.Object) bool {
+			return object.Key == missing.Filename
+		}) {
+			continue
+		}
+		if err := s.mediaRepository.DeleteStoredFile(missing.StoredFile); err != nil {
+			return err
+		}
+		if missing.Kind == repository.StoredVideo {
+			// the media has no file anymore, so its whole directory is orphaned
+			prefix := path.Dir(missing.Key)
+			objects, err := s.bucket.ListObjects(prefix + "/")
+			if err != nil {
+				return err
+			}
+			for _, object := range settledObjects(objects, now) {
+				orphans = append(orphans, prefix+"/"+object)
+			}
+		}
+	}
+	slices.Sort(orphans)
+	return s.bucket.DeleteObjects(slices.Compact(orphans))
+}
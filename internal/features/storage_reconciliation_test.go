@@ -0,0 +1,46 @@
+package features
+
+import (
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrphanedObjects(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * uploadGracePeriod)
+	objects := []storage.Object{
+		{Key: "video.m3u8", LastModified: old},
+		{Key: "video_000.ts", LastModified: old},
+		{Key: "audio_1.m3u8", LastModified: old},
+		{Key: "audio_1_000.ts", LastModified: old},
+		{Key: "audio_1_001.ts", LastModified: old},
+		{Key: "audio_2.m3u8", LastModified: now.Add(-time.Minute)},
+		{Key: "audio_2_000.ts", LastModified: now.Add(-time.Minute)},
+		{Key: "subtitle_1.vtt", LastModified: old},
+	}
+	files := []repository.StoredFile{{Filename: "video.m3u8"}}
+
+	got := orphanedObjects(settledObjects(objects, now), files)
+	want := []string{"audio_1.m3u8", "subtitle_1.vtt", "audio_1_000.ts", "audio_1_001.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orphanedObjects() = %v, want %v", got, want)
+	}
+}
+
+func TestSettledFiles(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	files := []repository.StoredFile{
+		{Kind: repository.StoredVideo, Filename: "video.m3u8", CreatedAt: now.Add(-time.Minute)},
+		{Kind: repository.StoredAudio, Filename: "audio_1.m3u8", CreatedAt: now.Add(-2 * uploadGracePeriod)},
+		{Kind: repository.StoredSubtitle, Filename: "subtitle_1.vtt", CreatedAt: now.Add(-uploadGracePeriod)},
+	}
+
+	got := settledFiles(files, now)
+	want := []repository.StoredFile{files[1], files[2]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("settledFiles() = %v, want %v", got, want)
+	}
+}
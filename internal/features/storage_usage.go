@@ -0,0 +1,139 @@
+package features
+
+import (
+	"context"
+	"github.com/bingemate/media-service/internal/repository"
+	"syscall"
+	"time"
+)
+
+type StorageStatus string
+
+const (
+	StorageOK       StorageStatus = "OK"
+	StorageLow      StorageStatus = "LOW"
+	StorageCritical StorageStatus = "CRITICAL"
+)
+
+// StorageThresholds are the percentages of available space under which a target folder is low or critical
+type StorageThresholds struct {
+	LowPercent      float64
+	CriticalPercent float64
+}
+
+// FolderUsage is the space of the filesystem of a target folder, Type being SavedMovie or SavedTvShow
+type FolderUsage struct {
+	Type             repository.SavedMediaType
+	Total            uint64
+	Available        uint64
+	AvailablePercent float64
+	Status           StorageStatus
+}
+
+// StorageUsageReport breaks the storage used by the media files down by kind, tv show, season and year added,
+// with the largest files and the available space of the target folders
+type StorageUsageReport struct {
+	Movies       repository.StorageUsage
+	Episodes     repository.StorageUsage
+	TvShows      []repository.TvShowUsage
+	Seasons      []repository.SeasonUsage
+	Years        []repository.YearUsage
+	LargestFiles []repository.FileUsage
+	Folders      []FolderUsage
+}
+
+// GetStorageUsage returns the storage usage report, limit being the number of tv shows, seasons and files listed
+func (m *MediaFile) GetStorageUsage(limit int) (*StorageUsageReport, error) {
+	limit = pageSize(limit, 20)
+	var report StorageUsageReport
+	var err error
+	if report.Movies, err = m.mediaRepository.GetMoviesUsage(); err != nil {
+		return nil, err
+	}
+	if report.Episodes, err = m.mediaRepository.GetEpisodesUsage(); err != nil {
+		return nil, err
+	}
+	if report.TvShows, err = m.mediaRepository.GetTvShowsUsage(limit); err != nil {
+		return nil, err
+	}
+	if report.Seasons, err = m.mediaRepository.GetSeasonsUsage(limit); err != nil {
+		return nil, err
+	}
+	if report.Years, err = m.mediaRepository.GetYearlyUsage(); err != nil {
+		return nil, err
+	}
+	if report.LargestFiles, err = m.mediaRepository.GetLargestFiles(limit); err != nil {
+		return nil, err
+	}
+	if report.Folders, err = m.GetFoldersUsage(); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// StartStorageCheck checks the available space of the target folders now, then in the background every interval
+// until ctx is done, logging a warning for each folder running low
+func (m *MediaFile) StartStorageCheck(ctx context.Context, interval time.Duration) {
+	m.checkFoldersUsage()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.checkFoldersUsage()
+			}
+		}
+	}()
+}
+
+func (m *MediaFile) checkFoldersUsage() {
+	usages, err := m.GetFoldersUsage()
+	if err != nil {
+		m.logger.Error("error checking the space of the target folders", "error", err)
+		return
+	}
+	for _, usage := range usages {
+		if usage.Status != StorageOK {
+			m.logger.Warn("target folder is running out of space", "type", usage.Type, "status", usage.Status,
+				"available", usage.Available, "available_percent", usage.AvailablePercent)
+		}
+	}
+}
+
+// GetFoldersUsage returns the available space of the movie and tv target folders, flagged against the thresholds
+func (m *MediaFile) GetFoldersUsage() ([]FolderUsage, error) {
+	folders := []struct {
+		mediaType repository.SavedMediaType
+		path      string
+	}{
+		{repository.SavedMovie, m.moviePath},
+		{repository.SavedTvShow, m.tvPath},
+	}
+	usages := make([]FolderUsage, len(folders))
+	for i, folder := range folders {
+		fs := syscall.Statfs_t{}
+		if err := syscall.Statfs(folder.path, &fs); err != nil {
+			return nil, err
+		}
+		usage := FolderUsage{
+			Type:      folder.mediaType,
+			Total:     fs.Blocks * uint64(fs.Bsize),
+			Available: fs.Bavail * uint64(fs.Bsize),
+			Status:    StorageOK,
+		}
+		if usage.Total > 0 {
+			usage.AvailablePercent = float64(usage.Available) / float64(usage.Total) * 100
+		}
+		switch {
+		case usage.AvailablePercent < m.thresholds.CriticalPercent:
+			usage.Status = StorageCritical
+		case usage.AvailablePercent < m.thresholds.LowPercent:
+			usage.Status = StorageLow
+		}
+		usages[i] = usage
+	}
+	return usages, nil
+}
@@ -0,0 +1,35 @@
+package features
+
+import "testing"
+
+func TestGetFoldersUsage(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		thresholds StorageThresholds
+		want       StorageStatus
+	}{
+		{"ok", StorageThresholds{LowPercent: 0, CriticalPercent: 0}, StorageOK},
+		{"low", StorageThresholds{LowPercent: 101, CriticalPercent: 0}, StorageLow},
+		{"critical", StorageThresholds{LowPercent: 101, CriticalPercent: 101}, StorageCritical},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usages, err := NewMediaFile(dir, dir, nil, nil, test.thresholds).GetFoldersUsage()
+			if err != nil {
+				t.Fatalf("GetFoldersUsage() error = %v", err)
+			}
+			if len(usages) != 2 {
+				t.Fatalf("got %d folders, want 2", len(usages))
+			}
+			for _, usage := range usages {
+				if usage.Status != test.want || usage.Total == 0 || usage.AvailablePercent > 100 {
+					t.Errorf("usage of the %s folder = %+v, want status %s", usage.Type, usage, test.want)
+				}
+			}
+		})
+	}
+	if _, err := NewMediaFile(dir+"/missing", dir, nil, nil, StorageThresholds{}).GetFoldersUsage(); err == nil {
+		t.Error("GetFoldersUsage() of a missing folder error = nil, want an error")
+	}
+}
@@ -0,0 +1,184 @@
+package features
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"github.com/bingemate/media-service/internal/storage"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSubtitleCacheSize is the total size of the converted subtitles kept in memory, in bytes
+const maxSubtitleCacheSize = 32 << 20
+
+// srtUnsupportedTags matches the WebVTT tags SRT does not know: classes, voices, languages, ruby and timestamps
+var srtUnsupportedTags = regexp.MustCompile(`</?(?:c|v|lang|ruby|rt)(?:[.\s][^>]*)?>|<\d[^>]*>`)
+
+// SubtitleShift corrects the timing of a subtitle: the times are scaled from the SourceFramerate to the
+// TargetFramerate, when both are set, then moved by Offset
+type SubtitleShift struct {
+	Offset          time.Duration
+	SourceFramerate float64
+	TargetFramerate float64
+}
+
+// ParseSubtitleShift returns the shift of an offset in milliseconds and of the framerates "source:target",
+// such as "23.976:25", both being optional
+func ParseSubtitleShift(offset, framerate string) (SubtitleShift, error) {
+	var shift SubtitleShift
+	if offset != "" {
+		milliseconds, err := strconv.ParseInt(offset, 10, 64)
+		if err != nil {
+			return shift, fmt.Errorf("%w: offset %q is not a number of milliseconds", ErrInvalidSubtitleShift, offset)
+		}
+		shift.Offset = time.Duration(milliseconds) * time.Millisecond
+	}
+	if framerate != "" {
+		source, target, found := strings.Cut(framerate, ":")
+		var sourceErr, targetErr error
+		shift.SourceFramerate, sourceErr = strconv.ParseFloat(source, 64)
+		shift.TargetFramerate, targetErr = strconv.ParseFloat(target, 64)
+		if !found || sourceErr != nil || targetErr != nil || shift.SourceFramerate <= 0 || shift.TargetFramerate <= 0 {
+			return shift, fmt.Errorf("%w: framerate %q is not source:target", ErrInvalidSubtitleShift, framerate)
+		}
+	}
+	return shift, nil
+}
+
+// apply returns the cues with the shift applied, dropping the ones ending before the start of the media
+func (s SubtitleShift) apply(cues []subtitleCue) []subtitleCue {
+	shifted := make([]subtitleCue, 0, len(cues))
+	move := func(t time.Duration) time.Duration {
+		if s.SourceFramerate > 0 && s.TargetFramerate > 0 {
+			t = time.Duration(float64(t) * s.SourceFramerate / s.TargetFramerate)
+		}
+		return t + s.Offset
+	}
+	for _, cue := range cues {
+		cue.Start, cue.End = move(cue.Start), move(cue.End)
+		if cue.End <= 0 {
+			continue
+		}
+		cue.Start = max(cue.Start, 0)
+		shifted = append(shifted, cue)
+	}
+	return shifted
+}
+
+// GetSubtitleContent returns a subtitle of a media file in the SRT or WebVTT format, with its timing shifted.
+// The converted subtitles are cached until they are replaced.
+func (s *Subtitles) GetSubtitleContent(fileID, subtitleID string, format SubtitleFormat, shift SubtitleShift) ([]byte, error) {
+	if format != SubtitleSRT && format != SubtitleVTT {
+		return nil, fmt.Errorf("%w: %q cannot be served, expected srt or vtt", ErrInvalidSubtitleFormat, format)
+	}
+	subtitle, err := s.getSubtitle(fileID, subtitleID)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := fmt.Sprintf("%s/%d/%s/%d/%g/%g", subtitle.ID, subtitle.UpdatedAt.UnixNano(), format,
+		shift.Offset, shift.SourceFramerate, shift.TargetFramerate)
+	if content, ok := s.cache.get(cacheKey); ok {
+		return content, nil
+	}
+	prefix, err := s.mediaFilePrefix(fileID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.bucket.GetObject(path.Join(prefix, subtitle.Filename))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrSubtitleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	cues, err := parseSubtitle(stored, SubtitleVTT)
+	if err != nil {
+		return nil, err
+	}
+	cues = shift.apply(cues)
+	var content []byte
+	if format == SubtitleSRT {
+		content = formatSRT(cues)
+	} else {
+		content = formatVTT(cues)
+	}
+	s.cache.put(cacheKey, content)
+	return content, nil
+}
+
+// formatSRT returns the SRT file of the cues, without the WebVTT cue settings and the tags SRT does not know
+func formatSRT(cues []subtitleCue) []byte {
+	var srt strings.Builder
+	number := 0
+	for _, cue := range cues {
+		var lines []string
+		for _, line := range strings.Split(html.UnescapeString(srtUnsupportedTags.ReplaceAllString(cue.Text, "")), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		number++
+		fmt.Fprintf(&srt, "%d\n%s --> %s\n%s\n\n", number,
+			formatSubtitleTime(cue.Start, ','), formatSubtitleTime(cue.End, ','), strings.Join(lines, "\n"))
+	}
+	return []byte(srt.String())
+}
+
+// subtitleCache keeps the last converted subtitles up to maxSubtitleCacheSize bytes, the least recently used
+// being evicted first
+type subtitleCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries *list.List
+	keys    map[string]*list.Element
+}
+
+type subtitleCacheEntry struct {
+	key     string
+	content []byte
+}
+
+func newSubtitleCache() *subtitleCache {
+	return &subtitleCache{
+		entries: list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+func (c *subtitleCache) get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.keys[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(element)
+	return element.Value.(*subtitleCacheEntry).content, true
+}
+
+func (c *subtitleCache) put(key string, content []byte) {
+	if len(content) > maxSubtitleCacheSize {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return
+	}
+	c.keys[key] = c.entries.PushFront(&subtitleCacheEntry{key: key, content: content})
+	c.size += len(content)
+	for c.size > maxSubtitleCacheSize {
+		oldest := c.entries.Remove(c.entries.Back()).(*subtitleCacheEntry)
+		delete(c.keys, oldest.key)
+		c.size -= len(oldest.content)
+	}
+}
@@ -0,0 +1,123 @@
+package features
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSubtitleShift(t *testing.T) {
+	tests := []struct {
+		offset, framerate string
+		want              SubtitleShift
+	}{
+		{"", "", SubtitleShift{}},
+		{"1500", "", SubtitleShift{Offset: 1500 * time.Millisecond}},
+		{"-250", "23.976:25", SubtitleShift{Offset: -250 * time.Millisecond, SourceFramerate: 23.976, TargetFramerate: 25}},
+	}
+	for _, test := range tests {
+		got, err := ParseSubtitleShift(test.offset, test.framerate)
+		if err != nil || got != test.want {
+			t.Errorf("ParseSubtitleShift(%q, %q) = %+v, %v, want %+v", test.offset, test.framerate, got, err, test.want)
+		}
+	}
+	invalid := [][2]string{{"1.5", ""}, {"abc", ""}, {"", "25"}, {"", "25:"}, {"", "0:25"}, {"", "25:-1"}, {"", "a:b"}}
+	for _, test := range invalid {
+		if _, err := ParseSubtitleShift(test[0], test[1]); !errors.Is(err, ErrInvalidSubtitleShift) {
+			t.Errorf("ParseSubtitleShift(%q, %q) error = %v, want ErrInvalidSubtitleShift", test[0], test[1], err)
+		}
+	}
+}
+
+func TestSubtitleShiftApply(t *testing.T) {
+	cues := []subtitleCue{
+		{Start: time.Second, End: 2 * time.Second, Text: "first"},
+		{Start: 5 * time.Second, End: 10 * time.Second, Text: "second"},
+	}
+	tests := []struct {
+		name  string
+		shift SubtitleShift
+		want  []subtitleCue
+	}{
+		{"none", SubtitleShift{}, cues},
+		{"delayed", SubtitleShift{Offset: time.Second}, []subtitleCue{
+			{Start: 2 * time.Second, End: 3 * time.Second, Text: "first"},
+			{Start: 6 * time.Second, End: 11 * time.Second, Text: "second"},
+		}},
+		{"advanced past the start", SubtitleShift{Offset: -6 * time.Second}, []subtitleCue{
+			{Start: 0, End: 4 * time.Second, Text: "second"},
+		}},
+		{"framerate", SubtitleShift{SourceFramerate: 25, TargetFramerate: 50}, []subtitleCue{
+			{Start: 500 * time.Millisecond, End: time.Second, Text: "first"},
+			{Start: 2500 * time.Millisecond, End: 5 * time.Second, Text: "second"},
+		}},
+		{"framerate then offset", SubtitleShift{Offset: time.Second, SourceFramerate: 50, TargetFramerate: 25}, []subtitleCue{
+			{Start: 3 * time.Second, End: 5 * time.Second, Text: "first"},
+			{Start: 11 * time.Second, End: 21 * time.Second, Text: "second"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.shift.apply(cues); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("apply() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatSRT(t *testing.T) {
+	cues := []subtitleCue{
+		{Start: 1500 * time.Millisecond, End: 3 * time.Second, Settings: "line:0", Text: "<v Bob>Hello</v> <c.yellow>you</c>\n\nthere &amp; <i>here</i>"},
+		{Start: 4 * time.Second, End: 5 * time.Second, Text: "<00:00:04.500>"},
+		{Start: time.Hour, End: time.Hour + time.Second, Text: "last"},
+	}
+	want := "1\n00:00:01,500 --> 00:00:03,000\nHello you\nthere & <i>here</i>\n\n" +
+		"2\n01:00:00,000 --> 01:00:01,000\nlast\n\n"
+	if got := string(formatSRT(cues)); got != want {
+		t.Errorf("formatSRT() = %q, want %q", got, want)
+	}
+}
+
+func TestSubtitleConversionRoundTrip(t *testing.T) {
+	srt := "1\n00:00:01,000 --> 00:00:02,500\nHello\nworld\n\n2\n00:01:00,250 --> 00:01:02,000\nBye\n\n"
+	cues, err := parseSubtitle([]byte(srt), SubtitleSRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues, err = parseSubtitle(formatVTT(cues), SubtitleVTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(formatSRT(cues)); got != srt {
+		t.Errorf("SRT converted to WebVTT and back = %q, want %q", got, srt)
+	}
+}
+
+func TestSubtitleCache(t *testing.T) {
+	cache := newSubtitleCache()
+	third := make([]byte, maxSubtitleCacheSize/3)
+	cache.put("a", third)
+	cache.put("b", third)
+	cache.put("c", third)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("a is not cached")
+	}
+	// b is now the least recently used entry
+	cache.put("d", third)
+	if _, ok := cache.get("b"); ok {
+		t.Error("b is cached, want it evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("%s is not cached", key)
+		}
+	}
+	if cache.size > maxSubtitleCacheSize {
+		t.Errorf("cache size = %d, want at most %d", cache.size, maxSubtitleCacheSize)
+	}
+	cache.put("too large", make([]byte, maxSubtitleCacheSize+1))
+	if _, ok := cache.get("too large"); ok {
+		t.Error("a content larger than the cache is cached")
+	}
+}
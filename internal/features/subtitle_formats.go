@@ -0,0 +1,247 @@
+package features
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/text/encoding/charmap"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type SubtitleFormat string
+
+const (
+	SubtitleSRT SubtitleFormat = "srt"
+	SubtitleASS SubtitleFormat = "ass"
+	SubtitleVTT SubtitleFormat = "vtt"
+)
+
+var (
+	// subtitleTiming matches the timing line of an SRT or WebVTT cue, the hours being optional in WebVTT
+	subtitleTiming = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{1,2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{1,2}[.,]\d{1,3})(.*)$`)
+	// assOverride matches the override blocks of an ASS dialogue, such as {\i1} or {\pos(10,20)}
+	assOverride = regexp.MustCompile(`\{[^}]*\}`)
+	// subtitleBlockSeparator matches the blank lines separating the blocks of an SRT or WebVTT file
+	subtitleBlockSeparator = regexp.MustCompile(`\n\s*\n`)
+	// unsupportedTags matches the SRT tags WebVTT does not know, such as <font color="red">
+	unsupportedTags = regexp.MustCompile(`(?i)</?font[^>]*>`)
+)
+
+// subtitleCue is a text displayed from Start to End. Settings are the WebVTT cue settings, such as "line:0".
+type subtitleCue struct {
+	Start    time.Duration
+	End      time.Duration
+	Settings string
+	Text     string
+}
+
+// ParseSubtitleFormat returns the subtitle format matching name or a file extension, ignoring its case.
+// SSA being the ancestor of ASS, it is read as ASS.
+func ParseSubtitleFormat(name string) (SubtitleFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "srt":
+		return SubtitleSRT, nil
+	case "ass", "ssa":
+		return SubtitleASS, nil
+	case "vtt", "webvtt":
+		return SubtitleVTT, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidSubtitleFormat, name)
+}
+
+// parseSubtitle returns the cues of a subtitle file by start time. The files which are not UTF-8,
+// as SRT files often are, are read as Windows-1252.
+func parseSubtitle(content []byte, format SubtitleFormat) ([]subtitleCue, error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	if !utf8.Valid(content) {
+		decoded, err := charmap.Windows1252.NewDecoder().Bytes(content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSubtitle, err)
+		}
+		content = decoded
+	}
+	text := strings.ReplaceAll(strings.ReplaceAll(string(content), "\r\n", "\n"), "\r", "\n")
+	var cues []subtitleCue
+	var err error
+	switch format {
+	case SubtitleSRT:
+		cues, err = parseTimedBlocks(text, false)
+	case SubtitleVTT:
+		if !strings.HasPrefix(text, "WEBVTT") {
+			return nil, fmt.Errorf("%w: missing WEBVTT header", ErrInvalidSubtitle)
+		}
+		cues, err = parseTimedBlocks(text, true)
+	case SubtitleASS:
+		cues, err = parseASS(text)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSubtitleFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("%w: no cue found", ErrInvalidSubtitle)
+	}
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].Start < cues[j].Start
+	})
+	return cues, nil
+}
+
+// parseTimedBlocks reads the cues of an SRT or WebVTT file, made of blocks separated by blank lines: an optional
+// identifier, the timing line and the text. The header, style, region and note blocks of WebVTT are skipped.
+func parseTimedBlocks(text string, webVTT bool) ([]subtitleCue, error) {
+	var cues []subtitleCue
+	for _, block := range subtitleBlockSeparator.Split(text, -1) {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		timing := -1
+		for i, line := range lines[:min(2, len(lines))] {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 {
+			// the WebVTT header and the STYLE, REGION and NOTE blocks have no timing
+			continue
+		}
+		match := subtitleTiming.FindStringSubmatch(strings.TrimSpace(lines[timing]))
+		if match == nil {
+			return nil, fmt.Errorf("%w: invalid timing %q", ErrInvalidSubtitle, lines[timing])
+		}
+		start, err := parseSubtitleTime(match[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseSubtitleTime(match[2])
+		if err != nil {
+			return nil, err
+		}
+		cue := subtitleCue{
+			Start: start,
+			End:   end,
+			Text:  unsupportedTags.ReplaceAllString(strings.Join(lines[timing+1:], "\n"), ""),
+		}
+		if webVTT {
+			cue.Settings = strings.Join(strings.Fields(match[3]), " ")
+		}
+		if strings.TrimSpace(cue.Text) != "" {
+			cues = append(cues, cue)
+		}
+	}
+	return cues, nil
+}
+
+// parseSubtitleTime parses an SRT, WebVTT or ASS time, [hh:]mm:ss,mmm, [hh:]mm:ss.mmm or h:mm:ss.cc,
+// the fraction of a second being optional
+func parseSubtitleTime(value string) (time.Duration, error) {
+	value = strings.Replace(value, ",", ".", 1)
+	clock, fraction, _ := strings.Cut(value, ".")
+	parts := strings.Split(clock, ":")
+	var total time.Duration
+	for _, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidSubtitle, value)
+		}
+		total = total*60 + time.Duration(number)
+	}
+	milliseconds, err := strconv.ParseUint((fraction + "000")[:3], 10, 16)
+	if err != nil || strings.Trim(fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidSubtitle, value)
+	}
+	return total*time.Second + time.Duration(milliseconds)*time.Millisecond, nil
+}
+
+// parseASS reads the Dialogue lines of the [Events] section of an ASS or SSA file, whose fields are named by
+// its Format line. The override blocks of the texts are removed, as WebVTT cannot style them alike.
+func parseASS(text string) ([]subtitleCue, error) {
+	var cues []subtitleCue
+	var format []string
+	inEvents := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !inEvents || !found {
+			continue
+		}
+		switch key {
+		case "Format":
+			format = strings.Split(value, ",")
+			for i := range format {
+				format[i] = strings.ToLower(strings.TrimSpace(format[i]))
+			}
+		case "Dialogue":
+			if len(format) == 0 {
+				return nil, fmt.Errorf("%w: dialogue before the events format", ErrInvalidSubtitle)
+			}
+			fields := strings.SplitN(value, ",", len(format))
+			if len(fields) < len(format) {
+				return nil, fmt.Errorf("%w: invalid dialogue %q", ErrInvalidSubtitle, line)
+			}
+			cue := subtitleCue{}
+			for i, name := range format {
+				var err error
+				switch name {
+				case "start":
+					cue.Start, err = parseSubtitleTime(strings.TrimSpace(fields[i]))
+				case "end":
+					cue.End, err = parseSubtitleTime(strings.TrimSpace(fields[i]))
+				case "text":
+					cue.Text = assText(fields[i])
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			if strings.TrimSpace(cue.Text) != "" {
+				cues = append(cues, cue)
+			}
+		}
+	}
+	return cues, nil
+}
+
+// assText returns the plain text of an ASS dialogue, its line breaks being \N or \n and its hard spaces \h
+func assText(text string) string {
+	text = assOverride.ReplaceAllString(text, "")
+	text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// formatVTT returns the WebVTT file of the cues. A cue cannot contain blank lines nor "-->", so they are removed.
+func formatVTT(cues []subtitleCue) []byte {
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&vtt, "\n%s --> %s", formatSubtitleTime(cue.Start, '.'), formatSubtitleTime(cue.End, '.'))
+		if cue.Settings != "" {
+			vtt.WriteString(" " + cue.Settings)
+		}
+		vtt.WriteString("\n")
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if line = strings.TrimSpace(strings.ReplaceAll(line, "-->", "->")); line != "" {
+				vtt.WriteString(line + "\n")
+			}
+		}
+	}
+	return []byte(vtt.String())
+}
+
+// formatSubtitleTime formats a time as hh:mm:ss followed by the separator and the milliseconds
+func formatSubtitleTime(value time.Duration, separator rune) string {
+	value = max(value, 0)
+	hours := value / time.Hour
+	minutes := value % time.Hour / time.Minute
+	seconds := value % time.Minute / time.Second
+	milliseconds := value % time.Second / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", hours, minutes, seconds, separator, milliseconds)
+}
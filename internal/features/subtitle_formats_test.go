@@ -0,0 +1,158 @@
+package features
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSubtitleTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		// SRT
+		{"00:01:02,345", time.Minute + 2*time.Second + 345*time.Millisecond},
+		{"01:00:00,000", time.Hour},
+		{"00:00:01,5", time.Second + 500*time.Millisecond},
+		// WebVTT, the hours being optional
+		{"00:01:02.345", time.Minute + 2*time.Second + 345*time.Millisecond},
+		{"01:02.345", time.Minute + 2*time.Second + 345*time.Millisecond},
+		{"100:00:00.001", 100*time.Hour + time.Millisecond},
+		// ASS, in centiseconds
+		{"0:00:01.50", time.Second + 500*time.Millisecond},
+		{"1:02:03.04", time.Hour + 2*time.Minute + 3*time.Second + 40*time.Millisecond},
+		{"0:00:01", time.Second},
+		{"0:00:01.", time.Second},
+	}
+	for _, test := range tests {
+		got, err := parseSubtitleTime(test.value)
+		if err != nil || got != test.want {
+			t.Errorf("parseSubtitleTime(%q) = %v, %v, want %v", test.value, got, err, test.want)
+		}
+	}
+}
+
+func TestParseSubtitleTimeInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "00:aa:01", "00::01.000", "-1:00:00.000", "00:00:01.-50", "00:00:01.5x", "+1:00:00"} {
+		if got, err := parseSubtitleTime(value); !errors.Is(err, ErrInvalidSubtitle) {
+			t.Errorf("parseSubtitleTime(%q) = %v, %v, want ErrInvalidSubtitle", value, got, err)
+		}
+	}
+}
+
+func TestParseSubtitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  SubtitleFormat
+		content string
+		want    []subtitleCue
+	}{
+		{
+			name:   "srt",
+			format: SubtitleSRT,
+			content: "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\n<font color=\"red\">Hello</font>\r\n\r\n" +
+				"2\r\n00:00:03,000 --> 00:00:04,000\r\nSecond\r\nline\r\n",
+			want: []subtitleCue{
+				{Start: time.Second, End: 2500 * time.Millisecond, Text: "Hello"},
+				{Start: 3 * time.Second, End: 4 * time.Second, Text: "Second\nline"},
+			},
+		},
+		{
+			name:   "srt sorted by start",
+			format: SubtitleSRT,
+			content: "1\n00:00:05,000 --> 00:00:06,000\nLater\n\n" +
+				"2\n00:00:01,000 --> 00:00:02,000\nSooner\n",
+			want: []subtitleCue{
+				{Start: time.Second, End: 2 * time.Second, Text: "Sooner"},
+				{Start: 5 * time.Second, End: 6 * time.Second, Text: "Later"},
+			},
+		},
+		{
+			name:    "srt in windows-1252",
+			format:  SubtitleSRT,
+			content: "1\n00:00:01,000 --> 00:00:02,000\nD\xe9j\xe0 vu\n",
+			want:    []subtitleCue{{Start: time.Second, End: 2 * time.Second, Text: "Déjà vu"}},
+		},
+		{
+			name:   "vtt",
+			format: SubtitleVTT,
+			content: "WEBVTT - title\n\nNOTE a comment\n\nSTYLE\n::cue { color: red }\n\n" +
+				"intro\n00:01.000 --> 00:02.000 line:0   align:start\nHello\n\n" +
+				"00:00:03.000 --> 00:00:04.000\nWorld\n",
+			want: []subtitleCue{
+				{Start: time.Second, End: 2 * time.Second, Settings: "line:0 align:start", Text: "Hello"},
+				{Start: 3 * time.Second, End: 4 * time.Second, Text: "World"},
+			},
+		},
+		{
+			name:   "ass",
+			format: SubtitleASS,
+			content: "[Script Info]\nTitle: test\n\n[V4+ Styles]\nFormat: Name, Fontname\nStyle: Default,Arial\n\n" +
+				"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+				"Dialogue: 0,0:00:01.50,0:00:03.00,Default,,0,0,0,,{\\i1}Hello{\\i0}, you\\Nthere\n" +
+				"Comment: 0,0:00:04.00,0:00:05.00,Default,,0,0,0,,ignored\n" +
+				"Dialogue: 0,0:00:06,0:00:07,Default,,0,0,0,,No\\hfraction\n",
+			want: []subtitleCue{
+				{Start: 1500 * time.Millisecond, End: 3 * time.Second, Text: "Hello, you\nthere"},
+				{Start: 6 * time.Second, End: 7 * time.Second, Text: "No\u00a0fraction"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseSubtitle([]byte(test.content), test.format)
+			if err != nil {
+				t.Fatalf("parseSubtitle() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseSubtitle() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSubtitleInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  SubtitleFormat
+		content string
+	}{
+		{"empty srt", SubtitleSRT, ""},
+		{"invalid srt timing", SubtitleSRT, "1\n00:00:01 --> 00:00:02,000\nHello\n"},
+		{"vtt without header", SubtitleVTT, "00:01.000 --> 00:02.000\nHello\n"},
+		{"ass without events format", SubtitleASS, "[Events]\nDialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,Hello\n"},
+		{"ass with an invalid time", SubtitleASS, "[Events]\nFormat: Start, End, Text\nDialogue: 0:00:aa.00,0:00:02.00,Hello\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseSubtitle([]byte(test.content), test.format); !errors.Is(err, ErrInvalidSubtitle) {
+				t.Errorf("parseSubtitle() error = %v, want ErrInvalidSubtitle", err)
+			}
+		})
+	}
+}
+
+func TestFormatVTT(t *testing.T) {
+	cues := []subtitleCue{
+		{Start: 1500 * time.Millisecond, End: time.Hour + 3*time.Second, Settings: "line:0", Text: "Hello\n\nworld"},
+		{Start: 4 * time.Second, End: 5 * time.Second, Text: "a --> b"},
+	}
+	want := "WEBVTT\n\n00:00:01.500 --> 01:00:03.000 line:0\nHello\nworld\n\n00:00:04.000 --> 00:00:05.000\na -> b\n"
+	if got := string(formatVTT(cues)); got != want {
+		t.Errorf("formatVTT() = %q, want %q", got, want)
+	}
+}
+
+func TestParseSubtitleFormat(t *testing.T) {
+	tests := map[string]SubtitleFormat{"srt": SubtitleSRT, ".SRT": SubtitleSRT, "ssa": SubtitleASS, "ass": SubtitleASS, "WebVTT": SubtitleVTT}
+	for name, want := range tests {
+		if got, err := ParseSubtitleFormat(name); err != nil || got != want {
+			t.Errorf("ParseSubtitleFormat(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseSubtitleFormat("sub"); !errors.Is(err, ErrInvalidSubtitleFormat) {
+		t.Errorf("ParseSubtitleFormat(\"sub\") error = %v, want ErrInvalidSubtitleFormat", err)
+	}
+}
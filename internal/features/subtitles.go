@@ -0,0 +1,179 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/logging"
+	"github.com/bingemate/media-service/internal/repository"
+	"github.com/bingemate/media-service/internal/storage"
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// Subtitles manages the subtitles of the media files besides the ones of the ingestion: the uploaded files
+// are converted to WebVTT and stored next to the video, as the ingested subtitles are. They are served back
+// converted to SRT or WebVTT, see GetSubtitleContent.
+type Subtitles struct {
+	mediaRepository *repository.MediaRepository
+	bucket          *storage.Bucket
+	cache           *subtitleCache
+	logger          *slog.Logger
+}
+
+func NewSubtitles(mediaRepository *repository.MediaRepository, bucket *storage.Bucket) *Subtitles {
+	return &Subtitles{
+		mediaRepository: mediaRepository,
+		bucket:          bucket,
+		cache:           newSubtitleCache(),
+		logger:          slog.Default(),
+	}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (s *Subtitles) WithContext(ctx context.Context) *Subtitles {
+	return &Subtitles{
+		mediaRepository: s.mediaRepository.WithContext(ctx),
+		bucket:          s.bucket,
+		cache:           s.cache,
+		logger:          logging.FromContext(ctx),
+	}
+}
+
+// GetSubtitles returns the subtitles of a media file
+func (s *Subtitles) GetSubtitles(fileID string) ([]repository2.Subtitle, error) {
+	if !s.mediaRepository.IsMediaFilePresent(fileID) {
+		return nil, ErrMediaNotFound
+	}
+	return s.mediaRepository.GetSubtitles(fileID)
+}
+
+// AddSubtitle converts a subtitle file to WebVTT and adds it to a media file, in the language given by its
+// ISO 639-2 code
+func (s *Subtitles) AddSubtitle(fileID, languageCode string, format SubtitleFormat, content []byte) (*repository2.Subtitle, error) {
+	languageCode, err := parseLanguageCode(languageCode)
+	if err != nil {
+		return nil, err
+	}
+	cues, err := parseSubtitle(content, format)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := s.mediaFilePrefix(fileID)
+	if err != nil {
+		return nil, err
+	}
+	// the ingested subtitles are named subtitle_{index}.vtt, a random name cannot collide with them
+	// nor with the concurrent uploads
+	subtitle := &repository2.Subtitle{
+		Filename:    fmt.Sprintf("subtitle_%s.vtt", uuid.NewString()),
+		Language:    languageCode,
+		MediaFileID: fileID,
+	}
+	key := path.Join(prefix, subtitle.Filename)
+	if err := s.bucket.PutObject(key, formatVTT(cues), "text/vtt"); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepository.AddSubtitle(subtitle); err != nil {
+		if deleteErr := s.bucket.DeleteObjects([]string{key}); deleteErr != nil {
+			s.logger.Error("error deleting uploaded subtitle", "key", key, "error", deleteErr)
+		}
+		return nil, err
+	}
+	return subtitle, nil
+}
+
+// ReplaceSubtitle replaces the content and the language of a subtitle of a media file, see AddSubtitle.
+// The new content is uploaded under a new name, so that the subtitle and its cached conversions keep
+// pointing to the previous content until the subtitle is updated. The previous object is deleted afterwards,
+// or left to the storage reconciliation if it cannot be.
+func (s *Subtitles) ReplaceSubtitle(fileID, subtitleID, languageCode string, format SubtitleFormat, content []byte) (*repository2.Subtitle, error) {
+	languageCode, err := parseLanguageCode(languageCode)
+	if err != nil {
+		return nil, err
+	}
+	cues, err := parseSubtitle(content, format)
+	if err != nil {
+		return nil, err
+	}
+	subtitle, err := s.getSubtitle(fileID, subtitleID)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := s.mediaFilePrefix(fileID)
+	if err != nil {
+		return nil, err
+	}
+	previousKey := path.Join(prefix, subtitle.Filename)
+	filename := fmt.Sprintf("subtitle_%s.vtt", uuid.NewString())
+	key := path.Join(prefix, filename)
+	if err := s.bucket.PutObject(key, formatVTT(cues), "text/vtt"); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepository.ReplaceSubtitle(subtitle, filename, languageCode); err != nil {
+		if deleteErr := s.bucket.DeleteObjects([]string{key}); deleteErr != nil {
+			s.logger.Error("error deleting uploaded subtitle", "key", key, "error", deleteErr)
+		}
+		return nil, err
+	}
+	if err := s.bucket.DeleteObjects([]string{previousKey}); err != nil {
+		s.logger.Warn("error deleting replaced subtitle from the storage", "key", previousKey, "error", err)
+	}
+	return subtitle, nil
+}
+
+// DeleteSubtitle removes a subtitle from a media file and deletes it from the storage.
+// An object that could not be deleted is left to the storage reconciliation.
+func (s *Subtitles) DeleteSubtitle(fileID, subtitleID string) error {
+	subtitle, err := s.getSubtitle(fileID, subtitleID)
+	if err != nil {
+		return err
+	}
+	prefix, err := s.mediaFilePrefix(fileID)
+	if err != nil {
+		return err
+	}
+	if err := s.mediaRepository.DeleteSubtitle(subtitle.ID); err != nil {
+		return err
+	}
+	key := path.Join(prefix, subtitle.Filename)
+	if err := s.bucket.DeleteObjects([]string{key}); err != nil {
+		s.logger.Warn("error deleting subtitle from the storage", "key", key, "error", err)
+	}
+	return nil
+}
+
+func (s *Subtitles) getSubtitle(fileID, subtitleID string) (*repository2.Subtitle, error) {
+	subtitle, err := s.mediaRepository.GetSubtitle(fileID, subtitleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubtitleNotFound
+	}
+	return subtitle, err
+}
+
+// mediaFilePrefix returns the storage directory of a media file, ErrMediaNotFound if it is not linked to any media
+func (s *Subtitles) mediaFilePrefix(fileID string) (string, error) {
+	prefix, err := mediaFilePrefix(s.mediaRepository, fileID)
+	if err == nil && prefix == "" {
+		return "", ErrMediaNotFound
+	}
+	return prefix, err
+}
+
+// parseLanguageCode returns the ISO 639-2 code of a language in lower case, such as "fre" or "fra" for French,
+// the code the ingestion stores
+func parseLanguageCode(code string) (string, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q is not an ISO 639-2 code", ErrInvalidLanguage, code)
+	}
+	if _, err := language.ParseBase(code); err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLanguage, code)
+	}
+	return code, nil
+}
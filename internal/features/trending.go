@@ -0,0 +1,206 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/repository"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TrendingWindow string
+
+const (
+	TrendingDay   TrendingWindow = "day"
+	TrendingWeek  TrendingWindow = "week"
+	TrendingMonth TrendingWindow = "month"
+)
+
+// trendingPeriod is the activity taken into account by a window, an activity losing half of its weight every halfLife
+type trendingPeriod struct {
+	span     time.Duration
+	halfLife time.Duration
+}
+
+var trendingPeriods = map[TrendingWindow]trendingPeriod{
+	TrendingDay:   {span: 24 * time.Hour, halfLife: 6 * time.Hour},
+	TrendingWeek:  {span: 7 * 24 * time.Hour, halfLife: 36 * time.Hour},
+	TrendingMonth: {span: 30 * 24 * time.Hour, halfLife: 7 * 24 * time.Hour},
+}
+
+const (
+	// trendingRefreshInterval is the time between two computations of the trending scores
+	trendingRefreshInterval = 15 * time.Minute
+	// weights of each kind of activity in a trending score, writing a comment taking more than playing a title
+	trendingCommentWeight = 3
+	trendingRatingWeight  = 2
+	trendingViewerWeight  = 1
+)
+
+// TrendingTitle is a movie or a tv show of the library with its trending score in a window
+type TrendingTitle struct {
+	Type  repository.SavedMediaType
+	ID    int
+	Score float64
+}
+
+// TrendingIndex ranks the movies and tv shows of the library by their recent comments, ratings and viewers.
+// Each activity weighs less as it gets older, so that the titles drawing attention now come first.
+// The rankings are computed in memory from the database when started, then again every 15 minutes.
+type TrendingIndex struct {
+	mutex           sync.RWMutex
+	mediaRepository *repository.MediaRepository
+	rankings        map[TrendingWindow]map[repository.SavedMediaType][]TrendingTitle
+}
+
+// NewTrendingIndex returns the index of the library, empty until it is started
+func NewTrendingIndex(mediaRepository *repository.MediaRepository) *TrendingIndex {
+	return &TrendingIndex{mediaRepository: mediaRepository}
+}
+
+// ParseTrendingWindow returns the trending window matching name, ignoring its case
+func ParseTrendingWindow(name string) (TrendingWindow, error) {
+	for window := range trendingPeriods {
+		if strings.EqualFold(name, string(window)) {
+			return window, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidTrendingWindow, name)
+}
+
+// Start computes the rankings now, then refreshes them in the background every trendingRefreshInterval
+// until ctx is done
+func (t *TrendingIndex) Start(ctx context.Context) error {
+	if err := t.Refresh(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(trendingRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.Refresh(); err != nil {
+					slog.Error("error refreshing trending titles", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Refresh computes the rankings again from the activity of the longest window
+func (t *TrendingIndex) Refresh() error {
+	now := time.Now()
+	var longest time.Duration
+	for _, period := range trendingPeriods {
+		longest = max(longest, period.span)
+	}
+	activities, err := t.mediaRepository.GetTrendingActivity(now.Add(-longest))
+	if err != nil {
+		return err
+	}
+	rankings := make(map[TrendingWindow]map[repository.SavedMediaType][]TrendingTitle, len(trendingPeriods))
+	for window, period := range trendingPeriods {
+		rankings[window] = rankTrending(activities, period, now)
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rankings = rankings
+	return nil
+}
+
+// Page returns a page of the titles of a type trending in a window, and the number of trending titles
+func (t *TrendingIndex) Page(window TrendingWindow, mediaType repository.SavedMediaType, page, size int) ([]TrendingTitle, int) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	titles := t.rankings[window][mediaType]
+	start := min(max(page-1, 0)*size, len(titles))
+	end := min(start+size, len(titles))
+	return titles[start:end], len(titles)
+}
+
+// rankTrending sums the decayed activity of each title within the span of period, the highest score first
+func rankTrending(activities []repository.TrendingActivity, period trendingPeriod, now time.Time) map[repository.SavedMediaType][]TrendingTitle {
+	scores := make(map[title]float64)
+	for _, activity := range activities {
+		age := max(now.Sub(activity.Time), 0)
+		if age > period.span {
+			continue
+		}
+		weight := float64(activity.Comments*trendingCommentWeight + activity.Ratings*trendingRatingWeight + activity.Viewers*trendingViewerWeight)
+		scores[title{activity.Type, activity.MediaID}] += weight * math.Exp2(-age.Hours()/period.halfLife.Hours())
+	}
+	rankings := make(map[repository.SavedMediaType][]TrendingTitle)
+	for t, score := range scores {
+		rankings[t.Type] = append(rankings[t.Type], TrendingTitle{Type: t.Type, ID: t.ID, Score: score})
+	}
+	for _, titles := range rankings {
+		sort.Slice(titles, func(i, j int) bool {
+			if titles[i].Score != titles[j].Score {
+				return titles[i].Score > titles[j].Score
+			}
+			return titles[i].ID < titles[j].ID
+		})
+	}
+	return rankings
+}
+
+// GetTrendingMovies returns a page of the available movies trending in a window
+func (m *MediaDiscovery) GetTrendingMovies(window TrendingWindow, page int) (*tmdb.PaginatedMovieResults, *[]bool, error) {
+	titles, total := m.trendingIndex.Page(window, repository.SavedMovie, page, libraryPageSize)
+	presence := make([]bool, len(titles))
+	results := make([]*tmdb.Movie, len(titles))
+	for i, movie := range titles {
+		result, err := m.mediaClient.GetMovieShort(movie.ID)
+		if err != nil {
+			m.logger.Error("error getting movie", "movie_id", movie.ID, "error", err)
+			return nil, nil, err
+		}
+		voteAverage, voteCount, err := m.mediaRepository.GetMovieRating(movie.ID)
+		if err == nil {
+			result.VoteAverage = voteAverage
+			result.VoteCount = voteCount
+		}
+		results[i] = result
+		presence[i] = true
+	}
+	return &tmdb.PaginatedMovieResults{
+		Results:     results,
+		TotalResult: total,
+		TotalPage:   int(math.Ceil(float64(total) / libraryPageSize)),
+	}, &presence, nil
+}
+
+// GetTrendingTvShows returns a page of the available tv shows trending in a window
+func (m *MediaDiscovery) GetTrendingTvShows(window TrendingWindow, page int) (*tmdb.PaginatedTVShowResults, *[]bool, error) {
+	titles, total := m.trendingIndex.Page(window, repository.SavedTvShow, page, libraryPageSize)
+	presence := make([]bool, len(titles))
+	results := make([]*tmdb.TVShow, len(titles))
+	for i, show := range titles {
+		result, err := m.mediaClient.GetTVShowShort(show.ID)
+		if err != nil {
+			m.logger.Error("error getting show", "tv_show_id", show.ID, "error", err)
+			return nil, nil, err
+		}
+		voteAverage, voteCount, err := m.mediaRepository.GetTvShowRating(show.ID)
+		if err == nil {
+			result.VoteAverage = voteAverage
+			result.VoteCount = voteCount
+		}
+		results[i] = result
+		presence[i] = true
+	}
+	return &tmdb.PaginatedTVShowResults{
+		Results:     results,
+		TotalResult: total,
+		TotalPage:   int(math.Ceil(float64(total) / libraryPageSize)),
+	}, &presence, nil
+}
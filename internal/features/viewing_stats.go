@@ -0,0 +1,59 @@
+package features
+
+import (
+	"fmt"
+	"github.com/bingemate/media-service/internal/repository"
+	"time"
+)
+
+// ViewingStats sums up what a user watched between two days, both included
+type ViewingStats struct {
+	Start   time.Time
+	End     time.Time
+	Totals  *repository.ViewingTotals
+	Days    []repository.ViewingDay
+	Genres  []repository.GenreViewing
+	TvShows []repository.TvShowViewing
+}
+
+// GetViewingStats returns the viewing statistics of a user between two dates (YYYY-MM-DD), both included,
+// with the limit genres and tv shows the user spent the most time watching. Invalid dates return ErrInvalidDate.
+func (s *WatchProgressService) GetViewingStats(userID string, start, end string, limit int) (*ViewingStats, error) {
+	startTime, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start %q is not a YYYY-MM-DD date", ErrInvalidDate, start)
+	}
+	endTime, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end %q is not a YYYY-MM-DD date", ErrInvalidDate, end)
+	}
+	if startTime.After(endTime) {
+		return nil, fmt.Errorf("%w: start %q is after end %q", ErrInvalidDate, start, end)
+	}
+	limit = min(pageSize(limit, 5), 20)
+
+	totals, err := s.mediaRepository.GetUserViewingTotals(userID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.mediaRepository.GetUserViewingDays(userID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := s.mediaRepository.GetUserTopGenres(userID, startTime, endTime, limit)
+	if err != nil {
+		return nil, err
+	}
+	tvShows, err := s.mediaRepository.GetUserTopTvShows(userID, startTime, endTime, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ViewingStats{
+		Start:   startTime,
+		End:     endTime,
+		Totals:  totals,
+		Days:    days,
+		Genres:  genres,
+		TvShows: tvShows,
+	}, nil
+}
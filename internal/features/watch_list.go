@@ -0,0 +1,197 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/repository"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// maxWatchListImport bounds the number of items of a single watch list import
+const maxWatchListImport = 5000
+
+var watchListStatuses = []repository2.WatchListStatus{
+	repository2.WatchListStatusPlanToWatch,
+	repository2.WatchListStatusWatching,
+	repository2.WatchListStatusFinished,
+	repository2.WatchListStatusAbandoned,
+}
+
+type WatchListService struct {
+	mediaRepository *repository.MediaRepository
+}
+
+func NewWatchListService(mediaRepository *repository.MediaRepository) *WatchListService {
+	return &WatchListService{mediaRepository}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (s *WatchListService) WithContext(ctx context.Context) *WatchListService {
+	return &WatchListService{s.mediaRepository.WithContext(ctx)}
+}
+
+// WatchListEntry is a movie or a tv show to import into a watch list
+type WatchListEntry struct {
+	MediaID int
+	Status  string
+}
+
+// ParseWatchListStatus returns the watch list status matching name, ignoring its case
+func ParseWatchListStatus(name string) (repository2.WatchListStatus, error) {
+	for _, status := range watchListStatuses {
+		if strings.EqualFold(name, string(status)) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidWatchListStatus, name)
+}
+
+// GetMovieWatchList returns the movies of the watch list of a user having one of the statuses, all of them if empty.
+// If available is set, only the movies of the library are returned.
+func (s *WatchListService) GetMovieWatchList(userID string, statuses []string, available bool) ([]*repository2.MovieWatchListItem, error) {
+	filter, err := watchListFilter(statuses, available)
+	if err != nil {
+		return nil, err
+	}
+	return s.mediaRepository.GetMovieWatchList(userID, filter)
+}
+
+// GetTvShowWatchList returns the tv shows of the watch list of a user having one of the statuses, all of them if empty.
+// If available is set, only the tv shows having an episode in the library are returned.
+func (s *WatchListService) GetTvShowWatchList(userID string, statuses []string, available bool) ([]*repository2.TvShowWatchListItem, error) {
+	filter, err := watchListFilter(statuses, available)
+	if err != nil {
+		return nil, err
+	}
+	return s.mediaRepository.GetTvShowWatchList(userID, filter)
+}
+
+func (s *WatchListService) GetMovieWatchListItem(userID string, movieID int) (*repository2.MovieWatchListItem, error) {
+	item, err := s.mediaRepository.GetMovieWatchListItem(userID, movieID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInWatchList
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *WatchListService) GetTvShowWatchListItem(userID string, tvShowID int) (*repository2.TvShowWatchListItem, error) {
+	item, err := s.mediaRepository.GetTvShowWatchListItem(userID, tvShowID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInWatchList
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// SaveMovieWatchListItem adds a movie to the watch list of a user, or changes its status
+func (s *WatchListService) SaveMovieWatchListItem(userID string, movieID int, status string) (*repository2.MovieWatchListItem, error) {
+	watchListStatus, err := ParseWatchListStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	item := &repository2.MovieWatchListItem{UserID: userID, MovieID: movieID, Status: watchListStatus}
+	if err := s.mediaRepository.SaveMovieWatchListItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SaveTvShowWatchListItem adds a tv show to the watch list of a user, or changes its status
+func (s *WatchListService) SaveTvShowWatchListItem(userID string, tvShowID int, status string) (*repository2.TvShowWatchListItem, error) {
+	watchListStatus, err := ParseWatchListStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	item := &repository2.TvShowWatchListItem{UserID: userID, TvShowID: tvShowID, Status: watchListStatus}
+	if err := s.mediaRepository.SaveTvShowWatchListItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *WatchListService) DeleteMovieWatchListItem(userID string, movieID int) error {
+	err := s.mediaRepository.DeleteMovieWatchListItem(userID, movieID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotInWatchList
+	}
+	return err
+}
+
+func (s *WatchListService) DeleteTvShowWatchListItem(userID string, tvShowID int) error {
+	err := s.mediaRepository.DeleteTvShowWatchListItem(userID, tvShowID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotInWatchList
+	}
+	return err
+}
+
+// ImportWatchList adds movies and tv shows to the watch list of a user at once, updating the status of those already in it.
+// Nothing is imported if an entry is invalid. When a media is given several times, its last status is kept.
+func (s *WatchListService) ImportWatchList(userID string, movies, tvShows []WatchListEntry) (int, int, error) {
+	if len(movies)+len(tvShows) > maxWatchListImport {
+		return 0, 0, fmt.Errorf("%w: at most %d items can be imported at once", ErrInvalidWatchListImport, maxWatchListImport)
+	}
+	movieStatuses, err := watchListEntryStatuses(movies)
+	if err != nil {
+		return 0, 0, err
+	}
+	tvShowStatuses, err := watchListEntryStatuses(tvShows)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	movieItems := make([]*repository2.MovieWatchListItem, 0, len(movieStatuses))
+	for _, entry := range movies {
+		if status, ok := movieStatuses[entry.MediaID]; ok {
+			movieItems = append(movieItems, &repository2.MovieWatchListItem{UserID: userID, MovieID: entry.MediaID, Status: status})
+			delete(movieStatuses, entry.MediaID)
+		}
+	}
+	tvShowItems := make([]*repository2.TvShowWatchListItem, 0, len(tvShowStatuses))
+	for _, entry := range tvShows {
+		if status, ok := tvShowStatuses[entry.MediaID]; ok {
+			tvShowItems = append(tvShowItems, &repository2.TvShowWatchListItem{UserID: userID, TvShowID: entry.MediaID, Status: status})
+			delete(tvShowStatuses, entry.MediaID)
+		}
+	}
+	if err := s.mediaRepository.ImportWatchList(movieItems, tvShowItems); err != nil {
+		return 0, 0, err
+	}
+	return len(movieItems), len(tvShowItems), nil
+}
+
+// watchListEntryStatuses validates entries and returns the last status of each media
+func watchListEntryStatuses(entries []WatchListEntry) (map[int]repository2.WatchListStatus, error) {
+	statuses := make(map[int]repository2.WatchListStatus, len(entries))
+	for _, entry := range entries {
+		if entry.MediaID <= 0 {
+			return nil, fmt.Errorf("%w: media ID %d must be a positive number", ErrInvalidWatchListImport, entry.MediaID)
+		}
+		status, err := ParseWatchListStatus(entry.Status)
+		if err != nil {
+			return nil, err
+		}
+		statuses[entry.MediaID] = status
+	}
+	return statuses, nil
+}
+
+func watchListFilter(statuses []string, available bool) (repository.WatchListFilter, error) {
+	filter := repository.WatchListFilter{Available: available}
+	for _, name := range statuses {
+		status, err := ParseWatchListStatus(name)
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	return filter, nil
+}
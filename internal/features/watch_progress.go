@@ -0,0 +1,337 @@
+package features
+
+import (
+	"context"
+	"errors"
+	repository2 "github.com/bingemate/media-go-pkg/repository"
+	"github.com/bingemate/media-service/internal/repository"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// watchedThreshold is the part of a file after which it is considered watched, leaving out the end credits
+const watchedThreshold = 0.9
+
+// maxPlaybackRate bounds the playback speed, so that seeking forward is not counted as time spent watching
+const maxPlaybackRate = 2
+
+// maxReportInterval is the longest time between two position reports of a playback: past it, the playback was paused
+// or stopped in between, so the time elapsed tells nothing of the time spent watching
+const maxReportInterval = 5 * time.Minute
+
+type WatchProgressService struct {
+	mediaRepository *repository.MediaRepository
+}
+
+func NewWatchProgressService(mediaRepository *repository.MediaRepository) *WatchProgressService {
+	return &WatchProgressService{mediaRepository}
+}
+
+// WithContext returns a copy of the service bound to the given request context
+func (s *WatchProgressService) WithContext(ctx context.Context) *WatchProgressService {
+	return &WatchProgressService{s.mediaRepository.WithContext(ctx)}
+}
+
+// WatchProgress is the position of a user in a movie or an episode and the duration of its file, in seconds
+type WatchProgress struct {
+	Position  float64
+	Duration  float64
+	Watched   bool
+	UpdatedAt *time.Time
+}
+
+// ContinueWatchingItem is a movie or an episode a user can resume, only the field matching Type is set.
+// For a tv show, it is either its last unfinished episode or the one following the last watched episode.
+type ContinueWatchingItem struct {
+	Type    repository.SavedMediaType
+	Movie   *repository2.Movie
+	Episode *repository2.Episode
+	WatchProgress
+}
+
+// SaveMovieProgress saves the position of a user in a movie, marking it watched past the watched threshold
+func (s *WatchProgressService) SaveMovieProgress(userID string, movieID int, position float64) (*WatchProgress, error) {
+	previous, err := s.GetMovieProgress(userID, movieID)
+	if err != nil {
+		return nil, err
+	}
+	return saveProgress(previous, position, func(position float64, watched bool) (*WatchProgress, error) {
+		progress, err := s.mediaRepository.SaveMovieProgress(userID, movieID, position, watched)
+		if err != nil {
+			return nil, err
+		}
+		return &WatchProgress{progress.Position, previous.Duration, progress.Watched, &progress.UpdatedAt}, nil
+	}, func(day time.Time, seconds float64, finished bool) error {
+		return s.mediaRepository.LogMovieView(&repository.MovieView{
+			UserID:   userID,
+			MovieID:  movieID,
+			Day:      day,
+			Seconds:  seconds,
+			Finished: finished,
+		})
+	})
+}
+
+// SaveEpisodeProgress saves the position of a user in an episode, marking it watched past the watched threshold
+func (s *WatchProgressService) SaveEpisodeProgress(userID string, episodeID int, position float64) (*WatchProgress, error) {
+	episode, err := s.mediaRepository.GetEpisode(episodeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+	previous, err := s.GetEpisodeProgress(userID, episodeID)
+	if err != nil {
+		return nil, err
+	}
+	return saveProgress(previous, position, func(position float64, watched bool) (*WatchProgress, error) {
+		progress, err := s.mediaRepository.SaveEpisodeProgress(userID, episodeID, episode.TvShowID, position, watched)
+		if err != nil {
+			return nil, err
+		}
+		return &WatchProgress{progress.Position, previous.Duration, progress.Watched, &progress.UpdatedAt}, nil
+	}, func(day time.Time, seconds float64, finished bool) error {
+		return s.mediaRepository.LogEpisodeView(&repository.EpisodeView{
+			UserID:    userID,
+			EpisodeID: episodeID,
+			TvShowID:  episode.TvShowID,
+			Day:       day,
+			Seconds:   seconds,
+			Finished:  finished,
+		})
+	})
+}
+
+// saveProgress saves a position following the previous progress of a user with save, and logs the view since the
+// previous report with logView. The position is bounded by the duration of the file, when it is known.
+func saveProgress(previous *WatchProgress, position float64, save func(position float64, watched bool) (*WatchProgress, error), logView func(day time.Time, seconds float64, finished bool) error) (*WatchProgress, error) {
+	if previous.Duration > 0 {
+		position = min(position, previous.Duration)
+	}
+	progress, err := save(position, isWatched(position, previous.Duration))
+	if err != nil {
+		return nil, err
+	}
+	seconds, finished := viewed(previous, position, *progress.UpdatedAt)
+	if seconds > 0 || finished {
+		if err := logView(viewDay(*progress.UpdatedAt), seconds, finished); err != nil {
+			return nil, err
+		}
+	}
+	return progress, nil
+}
+
+// GetMovieProgress returns the position of a user in a movie, at the start if the user never played it
+func (s *WatchProgressService) GetMovieProgress(userID string, movieID int) (*WatchProgress, error) {
+	file, err := s.mediaRepository.GetMovieFileInfo(movieID)
+	if (err != nil && errors.Is(err, gorm.ErrRecordNotFound)) || file == nil {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.mediaRepository.GetMovieProgress(userID, movieID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &WatchProgress{Duration: file.Duration}, nil
+		}
+		return nil, err
+	}
+	return &WatchProgress{progress.Position, file.Duration, progress.Watched, &progress.UpdatedAt}, nil
+}
+
+// GetEpisodeProgress returns the position of a user in an episode, at the start if the user never played it
+func (s *WatchProgressService) GetEpisodeProgress(userID string, episodeID int) (*WatchProgress, error) {
+	file, err := s.mediaRepository.GetEpisodeFileInfo(episodeID)
+	if (err != nil && errors.Is(err, gorm.ErrRecordNotFound)) || file == nil {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.mediaRepository.GetEpisodeProgress(userID, episodeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &WatchProgress{Duration: file.Duration}, nil
+		}
+		return nil, err
+	}
+	return &WatchProgress{progress.Position, file.Duration, progress.Watched, &progress.UpdatedAt}, nil
+}
+
+// GetNextEpisode returns the available episode of a tv show a user should play next, nil when there is none left.
+// It is the most recently played episode if it is unfinished, else the first unwatched episode following it.
+// A user who never played the tv show starts with its first episode, the specials (season 0) coming last.
+func (s *WatchProgressService) GetNextEpisode(userID string, tvShowID int) (*ContinueWatchingItem, error) {
+	progresses, err := s.mediaRepository.GetTvShowProgresses(userID, tvShowID)
+	if err != nil {
+		return nil, err
+	}
+	var last *repository.EpisodeProgress
+	for _, progress := range progresses {
+		if last == nil || progress.UpdatedAt.After(last.UpdatedAt) {
+			last = progress
+		}
+	}
+	return s.nextEpisode(tvShowID, progresses, last)
+}
+
+// GetContinueWatching returns the unfinished movies and the next episodes of the tv shows a user started,
+// the most recently played first
+func (s *WatchProgressService) GetContinueWatching(userID string, limit int) ([]*ContinueWatchingItem, error) {
+	limit = pageSize(limit, 20)
+	movies, err := s.mediaRepository.GetUnfinishedMovies(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	lastEpisodes, err := s.mediaRepository.GetLastEpisodeProgresses(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*ContinueWatchingItem, 0, len(movies)+len(lastEpisodes))
+	for _, progress := range movies {
+		items = append(items, &ContinueWatchingItem{
+			Type:  repository.SavedMovie,
+			Movie: &progress.Movie,
+			WatchProgress: WatchProgress{
+				Position:  progress.Position,
+				Duration:  progress.Movie.MediaFile.Duration,
+				UpdatedAt: &progress.UpdatedAt,
+			},
+		})
+	}
+	lastByTvShow := make(map[int]*repository.EpisodeProgress, len(lastEpisodes))
+	var played []int
+	for _, last := range lastEpisodes {
+		if last.Episode.MediaFile != nil && !last.Watched {
+			items = append(items, unfinishedEpisode(last))
+			continue
+		}
+		lastByTvShow[last.TvShowID] = last
+		played = append(played, last.EpisodeID)
+	}
+	nextEpisodes, err := s.mediaRepository.GetNextEpisodes(userID, played)
+	if err != nil {
+		return nil, err
+	}
+	for _, next := range nextEpisodes {
+		item := unfinishedEpisode(next)
+		// the tv show is as recent as its last played episode
+		item.UpdatedAt = &lastByTvShow[next.TvShowID].UpdatedAt
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt.After(*items[j].UpdatedAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (s *WatchProgressService) nextEpisode(tvShowID int, progresses []*repository.EpisodeProgress, last *repository.EpisodeProgress) (*ContinueWatchingItem, error) {
+	episodes, err := s.mediaRepository.GetAvailableTvShowEpisodes(tvShowID)
+	if err != nil {
+		return nil, err
+	}
+	if len(episodes) == 0 {
+		return nil, nil
+	}
+
+	if last == nil {
+		first := episodes[0]
+		for _, episode := range episodes {
+			if episode.NbSeason > 0 {
+				first = episode
+				break
+			}
+		}
+		return &ContinueWatchingItem{
+			Type:          repository.SavedEpisode,
+			Episode:       first,
+			WatchProgress: WatchProgress{Duration: first.MediaFile.Duration},
+		}, nil
+	}
+
+	byEpisode := make(map[int]*repository.EpisodeProgress, len(progresses))
+	for _, progress := range progresses {
+		byEpisode[progress.EpisodeID] = progress
+	}
+	if !last.Watched {
+		for _, episode := range episodes {
+			if episode.ID == last.EpisodeID {
+				last.Episode = *episode
+				return unfinishedEpisode(last), nil
+			}
+		}
+	}
+	for _, episode := range episodes {
+		if !isAfter(episode, last) {
+			continue
+		}
+		progress, ok := byEpisode[episode.ID]
+		if !ok {
+			return &ContinueWatchingItem{
+				Type:          repository.SavedEpisode,
+				Episode:       episode,
+				WatchProgress: WatchProgress{Duration: episode.MediaFile.Duration},
+			}, nil
+		}
+		if !progress.Watched {
+			progress.Episode = *episode
+			return unfinishedEpisode(progress), nil
+		}
+	}
+	return nil, nil
+}
+
+// unfinishedEpisode returns the item resuming an episode, whose file must be loaded
+func unfinishedEpisode(progress *repository.EpisodeProgress) *ContinueWatchingItem {
+	return &ContinueWatchingItem{
+		Type:    repository.SavedEpisode,
+		Episode: &progress.Episode,
+		WatchProgress: WatchProgress{
+			Position:  progress.Position,
+			Duration:  progress.Episode.MediaFile.Duration,
+			UpdatedAt: &progress.UpdatedAt,
+		},
+	}
+}
+
+// isAfter tells whether episode comes after the episode of progress, by season then number
+func isAfter(episode *repository2.Episode, progress *repository.EpisodeProgress) bool {
+	if episode.NbSeason != progress.Episode.NbSeason {
+		return episode.NbSeason > progress.Episode.NbSeason
+	}
+	return episode.NbEpisode > progress.Episode.NbEpisode
+}
+
+// viewed returns the seconds watched from the previous progress of a user to the position reported at now, and
+// whether it reached the watched threshold. The time spent is bounded by the time elapsed since the previous report.
+// A first report, or one more than maxReportInterval after the previous one, counts no time as where the user started
+// playing is unknown, but finishes the title if it is past the threshold.
+func viewed(previous *WatchProgress, position float64, now time.Time) (float64, bool) {
+	finished := isWatched(position, previous.Duration) && !isWatched(previous.Position, previous.Duration)
+	if previous.UpdatedAt == nil {
+		return 0, finished
+	}
+	elapsed := now.Sub(*previous.UpdatedAt)
+	if elapsed > maxReportInterval {
+		return 0, finished
+	}
+	seconds := min(max(0, position-previous.Position), elapsed.Seconds()*maxPlaybackRate)
+	return seconds, finished
+}
+
+// viewDay returns the UTC day of a view
+func viewDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+func isWatched(position, duration float64) bool {
+	return duration > 0 && position >= duration*watchedThreshold
+}
@@ -0,0 +1,98 @@
+package features
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestViewed(t *testing.T) {
+	now := time.Date(2023, 5, 7, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		previousPosition float64
+		previousTime     time.Time
+		position         float64
+		wantSeconds      float64
+		wantFinished     bool
+	}{
+		{"first report", 0, time.Time{}, 120, 0, false},
+		{"first report past the threshold", 0, time.Time{}, 950, 0, true},
+		{"playing", 100, now.Add(-time.Minute), 160, 60, false},
+		{"seeking forward", 100, now.Add(-time.Minute), 700, 120, false},
+		{"seeking backward", 500, now.Add(-time.Minute), 100, 0, false},
+		{"finishing", 880, now.Add(-time.Minute), 920, 40, true},
+		{"already finished", 920, now.Add(-time.Minute), 960, 40, false},
+		{"resuming the next day", 100, now.Add(-24 * time.Hour), 700, 0, false},
+		{"finishing the next day", 100, now.Add(-24 * time.Hour), 950, 0, true},
+		{"last report interval", 100, now.Add(-maxReportInterval), 400, 300, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previous := &WatchProgress{Position: test.previousPosition, Duration: 1000}
+			if !test.previousTime.IsZero() {
+				previous.UpdatedAt = &test.previousTime
+			}
+			seconds, finished := viewed(previous, test.position, now)
+			if seconds != test.wantSeconds || finished != test.wantFinished {
+				t.Errorf("viewed() = %v, %v, want %v, %v", seconds, finished, test.wantSeconds, test.wantFinished)
+			}
+		})
+	}
+}
+
+func TestSaveProgressPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		position float64
+		want     float64
+	}{
+		{"within the file", 1000, 600, 600},
+		{"past the end", 1000, 1200, 1000},
+		{"unknown duration", 0, 600, 600},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previous := &WatchProgress{Duration: test.duration}
+			progress, err := saveProgress(previous, test.position, func(position float64, watched bool) (*WatchProgress, error) {
+				now := time.Now()
+				return &WatchProgress{Position: position, Duration: test.duration, Watched: watched, UpdatedAt: &now}, nil
+			}, func(time.Time, float64, bool) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("saveProgress() error = %v", err)
+			}
+			if progress.Position != test.want {
+				t.Errorf("saveProgress() position = %v, want %v", progress.Position, test.want)
+			}
+		})
+	}
+}
+
+func TestIsWatched(t *testing.T) {
+	if isWatched(899, 1000) || !isWatched(900, 1000) || isWatched(0, 0) {
+		t.Error("isWatched does not use the watched threshold")
+	}
+}
+
+func TestGetViewingStatsInvalidDates(t *testing.T) {
+	service := &WatchProgressService{}
+	tests := []struct {
+		name  string
+		start string
+		end   string
+	}{
+		{"invalid start", "2023-13-01", "2023-12-31"},
+		{"invalid end", "2023-01-01", "31/12/2023"},
+		{"start after end", "2023-12-31", "2023-01-01"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := service.GetViewingStats("user", test.start, test.end, 0); !errors.Is(err, ErrInvalidDate) {
+				t.Errorf("GetViewingStats() error = %v, want %v", err, ErrInvalidDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS tv_show_watch_list_item;
+DROP TABLE IF EXISTS movie_watch_list_item;
+DROP TABLE IF EXISTS tv_show_comments;
+DROP TABLE IF EXISTS movie_comments;
+DROP TABLE IF EXISTS tv_show_ratings;
+DROP TABLE IF EXISTS movie_ratings;
+DROP TABLE IF EXISTS category_tv_show;
+DROP TABLE IF EXISTS category_movie;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS subtitles;
+DROP TABLE IF EXISTS audios;
+DROP TABLE IF EXISTS movies;
+DROP TABLE IF EXISTS episodes;
+DROP TABLE IF EXISTS tv_shows;
+DROP TABLE IF EXISTS media_files;
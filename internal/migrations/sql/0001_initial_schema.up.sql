@@ -0,0 +1,158 @@
+-- Schema previously created by gorm AutoMigrate (media-go-pkg repository.Migrate).
+-- Every statement is idempotent so that databases created before versioned migrations can adopt it.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+
+CREATE TABLE IF NOT EXISTS media_files
+(
+    id         uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    filename   text,
+    duration   decimal,
+    size       bigint
+);
+
+CREATE TABLE IF NOT EXISTS tv_shows
+(
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    name         text,
+    release_date date
+);
+
+CREATE TABLE IF NOT EXISTS episodes
+(
+    id            bigserial PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    name          text,
+    nb_episode    bigint,
+    nb_season     bigint,
+    release_date  date,
+    tv_show_id    bigint NOT NULL,
+    media_file_id uuid,
+    CONSTRAINT fk_tv_shows_episodes FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE,
+    CONSTRAINT fk_episodes_media_file FOREIGN KEY (media_file_id) REFERENCES media_files (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS movies
+(
+    id            bigserial PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    name          text,
+    release_date  date,
+    media_file_id uuid,
+    CONSTRAINT fk_movies_media_file FOREIGN KEY (media_file_id) REFERENCES media_files (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS audios
+(
+    id            uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    filename      text,
+    language      text,
+    media_file_id uuid NOT NULL,
+    CONSTRAINT fk_media_files_audios FOREIGN KEY (media_file_id) REFERENCES media_files (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS subtitles
+(
+    id            uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    filename      text,
+    language      text,
+    media_file_id uuid NOT NULL,
+    CONSTRAINT fk_media_files_subtitles FOREIGN KEY (media_file_id) REFERENCES media_files (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS categories
+(
+    id         uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name       text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS category_movie
+(
+    movie_id    bigint,
+    category_id uuid,
+    PRIMARY KEY (movie_id, category_id),
+    CONSTRAINT fk_category_movie_movie FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE,
+    CONSTRAINT fk_category_movie_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS category_tv_show
+(
+    tv_show_id  bigint,
+    category_id uuid,
+    PRIMARY KEY (tv_show_id, category_id),
+    CONSTRAINT fk_category_tv_show_tv_show FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE,
+    CONSTRAINT fk_category_tv_show_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS movie_ratings
+(
+    user_id    uuid,
+    movie_id   bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    rating     bigint,
+    PRIMARY KEY (user_id, movie_id),
+    CONSTRAINT fk_movies_ratings FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tv_show_ratings
+(
+    user_id    uuid,
+    tv_show_id bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    rating     bigint,
+    PRIMARY KEY (user_id, tv_show_id),
+    CONSTRAINT fk_tv_shows_ratings FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS movie_comments
+(
+    id         uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    content    text,
+    user_id    uuid   NOT NULL,
+    movie_id   bigint NOT NULL,
+    CONSTRAINT fk_movies_comments FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tv_show_comments
+(
+    id         uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    content    text,
+    user_id    uuid   NOT NULL,
+    tv_show_id bigint NOT NULL,
+    CONSTRAINT fk_tv_shows_comments FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS movie_watch_list_item
+(
+    user_id  uuid,
+    movie_id bigint,
+    status   varchar NOT NULL,
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE TABLE IF NOT EXISTS tv_show_watch_list_item
+(
+    user_id    uuid,
+    tv_show_id bigint,
+    status     varchar NOT NULL,
+    PRIMARY KEY (user_id, tv_show_id)
+);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_tv_show_ratings_tv_show_id;
+DROP INDEX IF EXISTS idx_movie_ratings_movie_id;
+DROP INDEX IF EXISTS idx_tv_show_comments_user_id;
+DROP INDEX IF EXISTS idx_movie_comments_user_id;
+DROP INDEX IF EXISTS idx_tv_show_comments_tv_show_id;
+DROP INDEX IF EXISTS idx_movie_comments_movie_id;
+DROP INDEX IF EXISTS idx_episodes_tv_show_id;
+DROP INDEX IF EXISTS idx_episodes_media_file_id;
+DROP INDEX IF EXISTS idx_movies_media_file_id;
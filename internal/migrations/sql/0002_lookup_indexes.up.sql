@@ -0,0 +1,10 @@
+-- Indexes for the lookups done on every discovery and file request
+CREATE INDEX IF NOT EXISTS idx_movies_media_file_id ON movies (media_file_id);
+CREATE INDEX IF NOT EXISTS idx_episodes_media_file_id ON episodes (media_file_id);
+CREATE INDEX IF NOT EXISTS idx_episodes_tv_show_id ON episodes (tv_show_id, nb_season, nb_episode);
+CREATE INDEX IF NOT EXISTS idx_movie_comments_movie_id ON movie_comments (movie_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_tv_show_id ON tv_show_comments (tv_show_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_comments_user_id ON movie_comments (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_user_id ON tv_show_comments (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_ratings_movie_id ON movie_ratings (movie_id);
+CREATE INDEX IF NOT EXISTS idx_tv_show_ratings_tv_show_id ON tv_show_ratings (tv_show_id);
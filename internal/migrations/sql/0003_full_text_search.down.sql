@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_episodes_name_trgm;
+DROP INDEX IF EXISTS idx_tv_shows_name_trgm;
+DROP INDEX IF EXISTS idx_movies_name_trgm;
+DROP INDEX IF EXISTS idx_episodes_search_vector;
+DROP INDEX IF EXISTS idx_tv_shows_search_vector;
+DROP INDEX IF EXISTS idx_movies_search_vector;
+
+ALTER TABLE episodes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tv_shows DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE movies DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
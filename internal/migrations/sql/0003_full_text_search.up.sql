@@ -0,0 +1,28 @@
+-- Full-text search over the media names, with a trigram fallback for typos and partial words
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+-- unaccent is only STABLE, as its dictionary could change, so it cannot be used in indexes and generated columns
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS
+$$
+SELECT public.unaccent('public.unaccent'::regdictionary, $1)
+$$;
+
+ALTER TABLE movies
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', immutable_unaccent(coalesce(name, '')))) STORED;
+ALTER TABLE tv_shows
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', immutable_unaccent(coalesce(name, '')))) STORED;
+ALTER TABLE episodes
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', immutable_unaccent(coalesce(name, '')))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_movies_search_vector ON movies USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_tv_shows_search_vector ON tv_shows USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_episodes_search_vector ON episodes USING gin (search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_movies_name_trgm ON movies USING gin (immutable_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tv_shows_name_trgm ON tv_shows USING gin (immutable_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_episodes_name_trgm ON episodes USING gin (immutable_unaccent(lower(name)) gin_trgm_ops);
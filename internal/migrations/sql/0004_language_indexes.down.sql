@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_subtitles_media_file_id_language;
+DROP INDEX IF EXISTS idx_audios_media_file_id_language;
@@ -0,0 +1,3 @@
+-- Indexes for the language filters of the file search and discovery requests
+CREATE INDEX IF NOT EXISTS idx_audios_media_file_id_language ON audios (media_file_id, language);
+CREATE INDEX IF NOT EXISTS idx_subtitles_media_file_id_language ON subtitles (media_file_id, language);
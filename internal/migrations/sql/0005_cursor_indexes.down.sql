@@ -0,0 +1,12 @@
+CREATE INDEX IF NOT EXISTS idx_movie_comments_movie_id ON movie_comments (movie_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_tv_show_id ON tv_show_comments (tv_show_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_comments_user_id ON movie_comments (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_user_id ON tv_show_comments (user_id, created_at DESC);
+DROP INDEX IF EXISTS idx_tv_show_ratings_user_id_cursor;
+DROP INDEX IF EXISTS idx_movie_ratings_user_id_cursor;
+DROP INDEX IF EXISTS idx_tv_show_ratings_tv_show_id_cursor;
+DROP INDEX IF EXISTS idx_movie_ratings_movie_id_cursor;
+DROP INDEX IF EXISTS idx_tv_show_comments_user_id_cursor;
+DROP INDEX IF EXISTS idx_movie_comments_user_id_cursor;
+DROP INDEX IF EXISTS idx_tv_show_comments_tv_show_id_cursor;
+DROP INDEX IF EXISTS idx_movie_comments_movie_id_cursor;
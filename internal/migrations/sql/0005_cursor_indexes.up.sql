@@ -0,0 +1,14 @@
+-- Indexes matching the keyset order (created_at, id) of the cursor paginated lists
+CREATE INDEX IF NOT EXISTS idx_movie_comments_movie_id_cursor ON movie_comments (movie_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_tv_show_id_cursor ON tv_show_comments (tv_show_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_comments_user_id_cursor ON movie_comments (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_user_id_cursor ON tv_show_comments (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_ratings_movie_id_cursor ON movie_ratings (movie_id, created_at DESC, user_id DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_ratings_tv_show_id_cursor ON tv_show_ratings (tv_show_id, created_at DESC, user_id DESC);
+CREATE INDEX IF NOT EXISTS idx_movie_ratings_user_id_cursor ON movie_ratings (user_id, created_at DESC, movie_id DESC);
+CREATE INDEX IF NOT EXISTS idx_tv_show_ratings_user_id_cursor ON tv_show_ratings (user_id, created_at DESC, tv_show_id DESC);
+-- The lookup indexes of the comments are prefixes of the cursor ones
+DROP INDEX IF EXISTS idx_movie_comments_movie_id;
+DROP INDEX IF EXISTS idx_tv_show_comments_tv_show_id;
+DROP INDEX IF EXISTS idx_movie_comments_user_id;
+DROP INDEX IF EXISTS idx_tv_show_comments_user_id;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS episode_progress;
+DROP TABLE IF EXISTS movie_progress;
@@ -0,0 +1,28 @@
+-- Playback positions reported by the users, in seconds
+CREATE TABLE IF NOT EXISTS movie_progress
+(
+    user_id    uuid,
+    movie_id   bigint,
+    position   decimal     NOT NULL DEFAULT 0,
+    watched    boolean     NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, movie_id),
+    CONSTRAINT fk_movie_progress_movie FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_movie_progress_user_id ON movie_progress (user_id, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS episode_progress
+(
+    user_id    uuid,
+    episode_id bigint,
+    tv_show_id bigint      NOT NULL,
+    position   decimal     NOT NULL DEFAULT 0,
+    watched    boolean     NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, episode_id),
+    CONSTRAINT fk_episode_progress_episode FOREIGN KEY (episode_id) REFERENCES episodes (id) ON DELETE CASCADE,
+    CONSTRAINT fk_episode_progress_tv_show FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_episode_progress_user_id_tv_show_id ON episode_progress (user_id, tv_show_id, updated_at DESC);
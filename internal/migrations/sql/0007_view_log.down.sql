@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS episode_view_log;
+DROP TABLE IF EXISTS movie_view_log;
@@ -0,0 +1,24 @@
+-- Seconds watched per user, media and day, fed by the playback position reports
+CREATE TABLE IF NOT EXISTS movie_view_log
+(
+    user_id  uuid,
+    movie_id bigint,
+    day      date,
+    seconds  decimal NOT NULL DEFAULT 0,
+    finished boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (user_id, day, movie_id),
+    CONSTRAINT fk_movie_view_log_movie FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS episode_view_log
+(
+    user_id    uuid,
+    episode_id bigint,
+    tv_show_id bigint  NOT NULL,
+    day        date,
+    seconds    decimal NOT NULL DEFAULT 0,
+    finished   boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (user_id, day, episode_id),
+    CONSTRAINT fk_episode_view_log_episode FOREIGN KEY (episode_id) REFERENCES episodes (id) ON DELETE CASCADE,
+    CONSTRAINT fk_episode_view_log_tv_show FOREIGN KEY (tv_show_id) REFERENCES tv_shows (id) ON DELETE CASCADE
+);
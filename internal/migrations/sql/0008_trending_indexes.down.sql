@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_episode_view_log_day;
+DROP INDEX IF EXISTS idx_movie_view_log_day;
+DROP INDEX IF EXISTS idx_tv_show_ratings_updated_at;
+DROP INDEX IF EXISTS idx_movie_ratings_updated_at;
+DROP INDEX IF EXISTS idx_tv_show_comments_created_at;
+DROP INDEX IF EXISTS idx_movie_comments_created_at;
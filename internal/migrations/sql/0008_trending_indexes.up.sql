@@ -0,0 +1,7 @@
+-- Indexes on the activity times read when computing the trending titles
+CREATE INDEX IF NOT EXISTS idx_movie_comments_created_at ON movie_comments (created_at);
+CREATE INDEX IF NOT EXISTS idx_tv_show_comments_created_at ON tv_show_comments (created_at);
+CREATE INDEX IF NOT EXISTS idx_movie_ratings_updated_at ON movie_ratings (updated_at);
+CREATE INDEX IF NOT EXISTS idx_tv_show_ratings_updated_at ON tv_show_ratings (updated_at);
+CREATE INDEX IF NOT EXISTS idx_movie_view_log_day ON movie_view_log (day);
+CREATE INDEX IF NOT EXISTS idx_episode_view_log_day ON episode_view_log (day);
@@ -0,0 +1,16 @@
+-- Deletions of media files, the file row being removed only once its objects are deleted from the storage.
+-- No foreign key on media_file_id: a job outlives the file it deleted.
+CREATE TABLE IF NOT EXISTS media_file_deletion_jobs
+(
+    id            uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    media_file_id uuid        NOT NULL,
+    prefix        text        NOT NULL DEFAULT '',
+    status        text        NOT NULL,
+    attempts      integer     NOT NULL DEFAULT 0,
+    error         text        NOT NULL DEFAULT '',
+    created_at    timestamptz,
+    updated_at    timestamptz
+);
+-- At most one deletion in progress per media file
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_file_deletion_jobs_active ON media_file_deletion_jobs (media_file_id)
+    WHERE status IN ('PENDING', 'RUNNING');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_episode_view_log_episode_id;
+DROP INDEX IF EXISTS idx_movie_view_log_movie_id;
@@ -0,0 +1,3 @@
+-- Indexes on the last view of each media read when evaluating the retention policy
+CREATE INDEX IF NOT EXISTS idx_movie_view_log_movie_id ON movie_view_log (movie_id, day DESC);
+CREATE INDEX IF NOT EXISTS idx_episode_view_log_episode_id ON episode_view_log (episode_id, day DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_media_files_filename_size_duration;
@@ -0,0 +1,2 @@
+-- Index on the columns compared when detecting the duplicated media files
+CREATE INDEX IF NOT EXISTS idx_media_files_filename_size_duration ON media_files (filename, size, duration);
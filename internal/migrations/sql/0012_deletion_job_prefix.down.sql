@@ -0,0 +1,3 @@
+UPDATE media_file_deletion_jobs
+SET prefix = rtrim(prefix, '/')
+WHERE prefix LIKE '%/';
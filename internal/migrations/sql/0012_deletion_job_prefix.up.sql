@@ -0,0 +1,6 @@
+-- The deletion jobs stored the directory of their media without a trailing slash, which also matched the directories
+-- of the IDs it prefixes, e.g. movies/12 and movies/120
+UPDATE media_file_deletion_jobs
+SET prefix = prefix || '/'
+WHERE prefix <> ''
+  AND prefix NOT LIKE '%/';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_media_file_deletion_jobs_undone;
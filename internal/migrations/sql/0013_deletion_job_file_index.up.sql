@@ -0,0 +1,3 @@
+-- The files being deleted, or whose deletion failed, are left out of the searches and the availability checks
+CREATE INDEX IF NOT EXISTS idx_media_file_deletion_jobs_undone ON media_file_deletion_jobs (media_file_id)
+    WHERE status <> 'DONE';
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of the last item of a page, in a list ordered by decreasing
+// creation date then ID, preceded by the relevance for the searches
+type Cursor struct {
+	Rank      float64   `json:"r,omitempty"`
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// String encodes the cursor as an opaque URL-safe token
+func (c Cursor) String() string {
+	content, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// ParseCursor decodes a token returned by Cursor.String, an empty token giving a nil cursor (the first page)
+func ParseCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(content, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Page selects the Limit items of a list following Cursor or, when there is no cursor, its Number-th page of
+// Limit items, numbered from 1
+type Page struct {
+	Cursor *Cursor
+	Number int
+	Limit  int
+}
+
+// offset returns the number of items preceding a numbered page
+func (p Page) offset() int {
+	if p.Cursor != nil || p.Number <= 1 {
+		return 0
+	}
+	return (p.Number - 1) * p.Limit
+}
+
+// CursorPage is a page of a list paginated with a cursor, Next is nil on the last page
+type CursorPage[T any] struct {
+	Items []T
+	Total int
+	Next  *Cursor
+}
+
+// keyset is the tie-breaking column of a list ordered by decreasing created_at, and the parser of its cursor values
+type keyset struct {
+	id      string
+	parseID func(string) (any, error)
+}
+
+func stringKey(column string) keyset {
+	return keyset{column, func(id string) (any, error) { return id, nil }}
+}
+
+func uuidKey(column string) keyset {
+	return keyset{column, func(id string) (any, error) {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		return id, nil
+	}}
+}
+
+func intKey(column string) keyset {
+	return keyset{column, func(id string) (any, error) { return strconv.Atoi(id) }}
+}
+
+// paginate counts the rows of query, then returns the rows of page, the most recent first.
+// cursorOf returns the cursor pointing to an item.
+func paginate[T any](query *gorm.DB, key keyset, page Page, cursorOf func(T) Cursor) (*CursorPage[T], error) {
+	var count int64
+	query = query.Count(&count)
+	if cursor := page.Cursor; cursor != nil {
+		id, err := key.parseID(cursor.ID)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(created_at, %s) < (?, ?)", key.id), cursor.CreatedAt, id)
+	}
+	var items []T
+	result := query.
+		Order("created_at DESC").
+		Order(key.id + " DESC").
+		Offset(page.offset()).
+		Limit(page.Limit + 1).
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return newCursorPage(items, int(count), page.Limit, cursorOf), nil
+}
+
+// newCursorPage returns the page of the first limit items, fetched with one more item to know if a next page exists
+func newCursorPage[T any](items []T, total, limit int, cursorOf func(T) Cursor) *CursorPage[T] {
+	page := &CursorPage[T]{Items: items, Total: total}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		next := cursorOf(items[limit-1])
+		page.Next = &next
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
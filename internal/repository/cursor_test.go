@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursors := []Cursor{
+		{CreatedAt: time.Date(2023, 5, 7, 20, 31, 28, 327382000, time.UTC), ID: "eec1d6b7-97c9-47e9-846b-6817d0e3d4ed"},
+		{Rank: 0.42, CreatedAt: time.Date(2023, 5, 7, 0, 0, 0, 0, time.FixedZone("CEST", 2*3600)), ID: "12"},
+	}
+	for _, cursor := range cursors {
+		parsed, err := ParseCursor(cursor.String())
+		if err != nil {
+			t.Fatalf("ParseCursor(%q) error = %v", cursor.String(), err)
+		}
+		if parsed.Rank != cursor.Rank || parsed.ID != cursor.ID || !parsed.CreatedAt.Equal(cursor.CreatedAt) {
+			t.Errorf("ParseCursor(%q) = %+v, want %+v", cursor.String(), *parsed, cursor)
+		}
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor, err := ParseCursor("")
+	if cursor != nil || err != nil {
+		t.Errorf("ParseCursor(\"\") = %v, %v, want the first page", cursor, err)
+	}
+	for _, token := range []string{
+		"not base64!",
+		"bm90IGpzb24",      // not json
+		"eyJjIjoiMjAyMyJ9", // {"c":"2023"}, invalid date
+		"eyJyIjoxfQ",       // {"r":1}, no ID
+	} {
+		if _, err := ParseCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("ParseCursor(%q) error = %v, want ErrInvalidCursor", token, err)
+		}
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	cursorOf := func(item int) Cursor {
+		return Cursor{ID: strconv.Itoa(item)}
+	}
+	tests := []struct {
+		name      string
+		items     []int
+		wantItems []int
+		wantNext  string
+	}{
+		{"empty", nil, []int{}, ""},
+		{"last page", []int{1, 2}, []int{1, 2}, ""},
+		{"full last page", []int{1, 2, 3}, []int{1, 2, 3}, ""},
+		{"next page", []int{1, 2, 3, 4}, []int{1, 2, 3}, "3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := newCursorPage(test.items, 10, 3, cursorOf)
+			if !reflect.DeepEqual(page.Items, test.wantItems) {
+				t.Errorf("Items = %v, want %v", page.Items, test.wantItems)
+			}
+			if page.Total != 10 {
+				t.Errorf("Total = %d, want 10", page.Total)
+			}
+			switch {
+			case test.wantNext == "" && page.Next != nil:
+				t.Errorf("Next = %+v, want none", *page.Next)
+			case test.wantNext != "" && (page.Next == nil || page.Next.ID != test.wantNext):
+				t.Errorf("Next = %v, want ID %s", page.Next, test.wantNext)
+			}
+		})
+	}
+}
+
+func TestKeysetParseID(t *testing.T) {
+	if id, err := intKey("movie_id").parseID("42"); err != nil || id != 42 {
+		t.Errorf("intKey parseID(42) = %v, %v", id, err)
+	}
+	if _, err := intKey("movie_id").parseID("eec1d6b7"); err == nil {
+		t.Error("intKey parseID(eec1d6b7) error = nil, want an error")
+	}
+	if id, err := stringKey("day").parseID("2023-05-07"); err != nil || id != "2023-05-07" {
+		t.Errorf("stringKey parseID(2023-05-07) = %v, %v", id, err)
+	}
+	id := "eec1d6b7-2a5e-4b1c-9f3e-4c8a1b2d3e4f"
+	if got, err := uuidKey("id").parseID(id); err != nil || got != id {
+		t.Errorf("uuidKey parseID(%s) = %v, %v", id, got, err)
+	}
+	if _, err := uuidKey("id").parseID("eec1d6b7"); err == nil {
+		t.Error("uuidKey parseID(eec1d6b7) error = nil, want an error")
+	}
+}
+
+func TestPageOffset(t *testing.T) {
+	tests := []struct {
+		page Page
+		want int
+	}{
+		{Page{Limit: 10}, 0},
+		{Page{Number: 1, Limit: 10}, 0},
+		{Page{Number: 3, Limit: 10}, 20},
+		{Page{Cursor: &Cursor{ID: "12"}, Number: 3, Limit: 10}, 0},
+	}
+	for _, test := range tests {
+		if got := test.page.offset(); got != test.want {
+			t.Errorf("%+v offset() = %d, want %d", test.page, got, test.want)
+		}
+	}
+}
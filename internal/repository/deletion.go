@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bingemate/media-go-pkg/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type DeletionJobStatus string
+
+const (
+	DeletionPending DeletionJobStatus = "PENDING"
+	DeletionRunning DeletionJobStatus = "RUNNING"
+	DeletionDone    DeletionJobStatus = "DONE"
+	DeletionFailed  DeletionJobStatus = "FAILED"
+)
+
+// DeletionJob tracks the deletion of a media file: its objects are deleted from the storage under Prefix,
+// then the file row is removed. While the job is pending or running, the file is pending deletion.
+// Prefix is empty when the file is not linked to any movie or episode, so it has no objects to delete.
+type DeletionJob struct {
+	ID          string `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	MediaFileID string `gorm:"type:uuid"`
+	Prefix      string
+	Status      DeletionJobStatus
+	Attempts    int
+	Error       string
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+func (DeletionJob) TableName() string {
+	return "media_file_deletion_jobs"
+}
+
+// Active reports whether the job is pending or running
+func (j *DeletionJob) Active() bool {
+	return j.Status == DeletionPending || j.Status == DeletionRunning
+}
+
+// availableFile returns the condition of a media file column referencing a file which can be served. The files
+// pending deletion are left out, as are the ones whose deletion failed, their objects being partially deleted.
+func availableFile(column string) string {
+	return "(" + column + " IS NOT NULL AND NOT EXISTS (SELECT 1 FROM media_file_deletion_jobs " +
+		"WHERE media_file_deletion_jobs.media_file_id = " + column + " AND media_file_deletion_jobs.status <> 'DONE'))"
+}
+
+// IsMediaFilePresent returns true if the media file is present in the database
+func (r *MediaRepository) IsMediaFilePresent(fileID string) bool {
+	var count int64
+	r.db.Model(&repository.MediaFile{}).Where("id = ?", fileID).Count(&count)
+	return count > 0
+}
+
+// activeDeletionJob skips the creation of a deletion job when the file already has one in progress,
+// the conflict target matching the partial unique index idx_media_file_deletion_jobs_active
+var activeDeletionJob = clause.OnConflict{
+	Columns:     []clause.Column{{Name: "media_file_id"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status IN ('PENDING', 'RUNNING')"}}},
+	DoNothing:   true,
+}
+
+// createDeletionJobAttempts bounds the attempts to create a deletion job whose conflicting job completes meanwhile
+const createDeletionJobAttempts = 3
+
+// CreateDeletionJob marks a media file pending deletion. If a deletion of the file is already in progress,
+// its job is returned instead and created is false, including when concurrent calls race to create it.
+func (r *MediaRepository) CreateDeletionJob(fileID, prefix string) (job *DeletionJob, created bool, err error) {
+	for attempt := 0; attempt < createDeletionJobAttempts; attempt++ {
+		job = &DeletionJob{MediaFileID: fileID, Prefix: prefix, Status: DeletionPending}
+		result := r.db.Clauses(activeDeletionJob).Create(job)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return job, true, nil
+		}
+		job = &DeletionJob{}
+		result = r.db.
+			Where("media_file_id = ? AND status IN ?", fileID, []DeletionJobStatus{DeletionPending, DeletionRunning}).
+			Limit(1).
+			Find(job)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return job, false, nil
+		}
+		// the job in progress completed since the insert, try again
+	}
+	return nil, false, fmt.Errorf("error creating the deletion job of media file %s: conflicting job in progress", fileID)
+}
+
+// GetDeletionJob returns a deletion job given its ID
+func (r *MediaRepository) GetDeletionJob(jobID string) (*DeletionJob, error) {
+	var job DeletionJob
+	result := r.db.First(&job, "id = ?", jobID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// GetActiveDeletionJobs returns the pending and running deletion jobs, the oldest first
+func (r *MediaRepository) GetActiveDeletionJobs() ([]*DeletionJob, error) {
+	var jobs []*DeletionJob
+	result := r.db.
+		Where("status IN ?", []DeletionJobStatus{DeletionPending, DeletionRunning}).
+		Order("created_at").
+		Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+// UpdateDeletionJob saves the status, attempts and error of a deletion job
+func (r *MediaRepository) UpdateDeletionJob(job *DeletionJob) error {
+	return r.db.Model(job).Select("status", "attempts", "error", "updated_at").Updates(job).Error
+}
+
+// CompleteDeletionJob removes the media file of a job and marks the job done
+func (r *MediaRepository) CompleteDeletionJob(job *DeletionJob) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Delete(&repository.MediaFile{}, "id = ?", job.MediaFileID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		job.Status = DeletionDone
+		job.Error = ""
+		return tx.Model(job).Select("status", "attempts", "error", "updated_at").Updates(job).Error
+	})
+}
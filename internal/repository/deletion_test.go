@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateDeletionJob(t *testing.T) {
+	r := newTestRepository(t)
+	fileID := "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e6f"
+
+	first, created, err := r.CreateDeletionJob(fileID, "movies/1/")
+	if err != nil || !created {
+		t.Fatalf("CreateDeletionJob() = %v, %v, want a created job", created, err)
+	}
+	again, created, err := r.CreateDeletionJob(fileID, "movies/1/")
+	if err != nil || created || again.ID != first.ID {
+		t.Fatalf("CreateDeletionJob() of a file being deleted = %v, %v, %v, want job %s", again.ID, created, err, first.ID)
+	}
+
+	first.Status = DeletionFailed
+	if err := r.UpdateDeletionJob(first); err != nil {
+		t.Fatal(err)
+	}
+	retried, created, err := r.CreateDeletionJob(fileID, "movies/1/")
+	if err != nil || !created || retried.ID == first.ID {
+		t.Errorf("CreateDeletionJob() after a failed job = %v, %v, %v, want a new job", retried.ID, created, err)
+	}
+}
+
+func TestAvailableFileDeletionJobs(t *testing.T) {
+	r := newTestRepository(t)
+	insertLibraryFixtures(t, r)
+	media := []SavedMedia{{Type: SavedMovie, ID: -1}, {Type: SavedMovie, ID: -2}, {Type: SavedMovie, ID: -4}}
+
+	// Alpha is being deleted, the deletion of Beta failed and the one of Delta is done
+	for id, status := range map[int]DeletionJobStatus{-1: DeletionRunning, -2: DeletionFailed, -4: DeletionDone} {
+		mustExec(t, r, "INSERT INTO media_file_deletion_jobs (media_file_id, status) SELECT media_file_id, ? FROM movies WHERE id = ?",
+			status, id)
+	}
+
+	available, err := r.GetAvailableMedia(media)
+	if err != nil {
+		t.Fatalf("GetAvailableMedia() error = %v", err)
+	}
+	if want := map[int]bool{-4: true}; !reflect.DeepEqual(available[SavedMovie], want) {
+		t.Errorf("available movies = %v, want %v", available[SavedMovie], want)
+	}
+	if r.IsMovieFilePresent(-1) || r.IsMovieFilePresent(-2) || !r.IsMovieFilePresent(-4) {
+		t.Error("IsMovieFilePresent() serves the files being deleted")
+	}
+	if _, err := r.GetMovieFileInfo(-2); err == nil {
+		t.Error("GetMovieFileInfo() serves a file whose deletion failed")
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+	"time"
+)
+
+// DuplicateFile is a media file sharing its filename, size and duration with another one, with the movie or
+// episode using it. Type is SavedMovie or SavedEpisode, empty with a MediaID of 0 when no media uses the file,
+// as the file a media used before being ingested again. Name is the name of the movie or of the tv show.
+type DuplicateFile struct {
+	MediaFileID string
+	Filename    string
+	Size        int64
+	Duration    float64
+	AddedAt     time.Time
+	Type        SavedMediaType
+	MediaID     int
+	Name        string
+	Season      int
+	Episode     int
+}
+
+// GetDuplicateFiles returns the media files whose filename, size and duration are shared by other files,
+// the largest first and the oldest first among the same ones
+func (r *MediaRepository) GetDuplicateFiles() ([]DuplicateFile, error) {
+	var files []DuplicateFile
+	shared := r.db.Model(&repository.MediaFile{}).
+		Select("filename, size, duration").
+		Group("filename, size, duration").
+		Having("COUNT(*) > 1")
+	result := r.db.Model(&repository.MediaFile{}).
+		Joins("LEFT JOIN (?) AS uses ON uses.media_file_id = media_files.id", r.mediaFileUses()).
+		Select("media_files.id AS media_file_id, media_files.filename, media_files.size, media_files.duration, "+
+			"media_files.created_at AS added_at, COALESCE(uses.type, '') AS type, COALESCE(uses.media_id, 0) AS media_id, "+
+			"COALESCE(uses.name, '') AS name, COALESCE(uses.season, 0) AS season, COALESCE(uses.episode, 0) AS episode").
+		Where("(media_files.filename, media_files.size, media_files.duration) IN (?)", shared).
+		Order("media_files.size DESC, media_files.filename, media_files.duration, media_files.created_at").
+		Scan(&files)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return files, nil
+}
+
+// LinkMediaFile makes a movie or an episode use another media file, see DuplicateFile
+func (r *MediaRepository) LinkMediaFile(mediaType SavedMediaType, mediaID int, fileID string) error {
+	var model any = &repository.Movie{}
+	if mediaType == SavedEpisode {
+		model = &repository.Episode{}
+	}
+	return r.db.Model(model).Where("id = ?", mediaID).Update("media_file_id", fileID).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import "sync"
+
+// SavedMediaType is the kind of row inserted by SaveMovie, SaveTvShow or SaveEpisode
+type SavedMediaType string
+
+const (
+	SavedMovie   SavedMediaType = "movie"
+	SavedTvShow  SavedMediaType = "tv"
+	SavedEpisode SavedMediaType = "episode"
+)
+
+// SavedMedia describes a movie, tv show or episode row, TvShowID is only set for episodes
+type SavedMedia struct {
+	Type     SavedMediaType `gorm:"-"`
+	ID       int
+	TvShowID int
+	Name     string
+}
+
+// saveHooks holds the callbacks of OnSave, shared by every copy of a repository
+type saveHooks struct {
+	mutex sync.RWMutex
+	hooks []func(SavedMedia)
+}
+
+func (h *saveHooks) saved(media SavedMedia) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for _, hook := range h.hooks {
+		hook(media)
+	}
+}
+
+// OnSave registers a callback run synchronously by SaveMovie, SaveTvShow and SaveEpisode once they saved a new media.
+// It must not call OnSave, and may run twice for a media saved twice at the same time.
+func (r *MediaRepository) OnSave(hook func(SavedMedia)) {
+	r.hooks.mutex.Lock()
+	defer r.hooks.mutex.Unlock()
+	r.hooks.hooks = append(r.hooks.hooks, hook)
+}
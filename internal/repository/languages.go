@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/bingemate/media-go-pkg/repository"
+)
+
+// LanguageFilter requires a media file to have one of the audio languages and one of the subtitle
+// languages. An empty list does not filter.
+type LanguageFilter struct {
+	Audios    []string
+	Subtitles []string
+}
+
+// condition returns the WHERE condition matching the media file referenced by fileColumn, and its arguments
+func (f LanguageFilter) condition(fileColumn string) (string, []any) {
+	condition, args := "TRUE", []any{}
+	if len(f.Audios) > 0 {
+		condition += " AND " + languageExists("audios", fileColumn)
+		args = append(args, f.Audios)
+	}
+	if len(f.Subtitles) > 0 {
+		condition += " AND " + languageExists("subtitles", fileColumn)
+		args = append(args, f.Subtitles)
+	}
+	return condition, args
+}
+
+// languageExists returns the condition requiring the media file referenced by fileColumn to have a row of table,
+// audios or subtitles, in one of the languages given as its argument
+func languageExists(table, fileColumn string) string {
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %[1]s WHERE %[1]s.media_file_id = %[2]s AND %[1]s.language IN ?)", table, fileColumn)
+}
+
+// LanguageCount is the number of movies and episodes available in a language
+type LanguageCount struct {
+	Language string
+	Movies   int
+	Episodes int
+}
+
+// GetAudioLanguages returns the audio languages of the media files, with the number of movies and episodes having them
+func (r *MediaRepository) GetAudioLanguages() ([]LanguageCount, error) {
+	return r.getLanguages(&repository.Audio{}, "audios")
+}
+
+// GetSubtitleLanguages returns the subtitle languages of the media files, with the number of movies and episodes having them
+func (r *MediaRepository) GetSubtitleLanguages() ([]LanguageCount, error) {
+	return r.getLanguages(&repository.Subtitle{}, "subtitles")
+}
+
+func (r *MediaRepository) getLanguages(model any, table string) ([]LanguageCount, error) {
+	var languages []LanguageCount
+	result := r.db.Model(model).
+		Select(fmt.Sprintf("%[1]s.language, COUNT(DISTINCT movies.id) AS movies, COUNT(DISTINCT episodes.id) AS episodes", table)).
+		Joins(fmt.Sprintf("LEFT JOIN movies ON movies.media_file_id = %s.media_file_id", table)).
+		Joins(fmt.Sprintf("LEFT JOIN episodes ON episodes.media_file_id = %s.media_file_id", table)).
+		Group(table + ".language").
+		Order("COUNT(DISTINCT movies.id) + COUNT(DISTINCT episodes.id) DESC, " + table + ".language").
+		Scan(&languages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return languages, nil
+}
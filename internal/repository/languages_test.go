@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLanguageFilterCondition(t *testing.T) {
+	r := newTestRepository(t)
+	french, english, both := "c7e1a3f2-5b4d-4e6f-8a9b-0c1d2e3f4a5b", "d8f2b4a3-6c5e-4f7a-9b0c-1d2e3f4a5b6c", "e9a3c5b4-7d6f-4a8b-0c1d-2e3f4a5b6c7d"
+	mustExec(t, r, "INSERT INTO media_files (id, filename) VALUES (?, 'french.mp4'), (?, 'english.mp4'), (?, 'both.mp4')", french, english, both)
+	mustExec(t, r, "INSERT INTO movies (id, name, media_file_id) VALUES (-1, 'french', ?), (-2, 'english', ?), (-3, 'both', ?), (-4, 'unavailable', NULL)",
+		french, english, both)
+	mustExec(t, r, "INSERT INTO audios (media_file_id, language) VALUES (?, 'fre'), (?, 'eng'), (?, 'fre'), (?, 'eng')", french, english, both, both)
+	mustExec(t, r, "INSERT INTO subtitles (media_file_id, language) VALUES (?, 'eng'), (?, 'fre'), (?, 'ger')", french, english, both)
+
+	tests := []struct {
+		name   string
+		filter LanguageFilter
+		want   []int
+	}{
+		{"empty", LanguageFilter{}, []int{-4, -3, -2, -1}},
+		{"audio", LanguageFilter{Audios: []string{"fre"}}, []int{-3, -1}},
+		{"one of the audios", LanguageFilter{Audios: []string{"eng", "ita"}}, []int{-3, -2}},
+		{"subtitle", LanguageFilter{Subtitles: []string{"ger", "fre"}}, []int{-3, -2}},
+		{"audio and subtitle", LanguageFilter{Audios: []string{"fre"}, Subtitles: []string{"eng"}}, []int{-1}},
+		{"no match", LanguageFilter{Audios: []string{"jpn"}}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition, args := test.filter.condition("movies.media_file_id")
+			var ids []int
+			result := r.db.Table("movies").
+				Where("movies.id < 0").
+				Where(condition, args...).
+				Order("movies.id").
+				Pluck("movies.id", &ids)
+			if result.Error != nil {
+				t.Fatalf("error filtering the movies: %v", result.Error)
+			}
+			if !slices.Equal(ids, test.want) {
+				t.Errorf("movies = %v, want %v", ids, test.want)
+			}
+		})
+	}
+}
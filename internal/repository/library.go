@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/bingemate/media-go-pkg/repository"
+	"gorm.io/gorm"
+	"time"
+)
+
+// LibraryFilter restricts the available movies or tv shows of the library. Zero values do not filter.
+// The values of a list are alternatives, while the different filters must all match.
+// For tv shows, the file filters (duration and languages) must be matched by an episode.
+type LibraryFilter struct {
+	Genres            []string
+	MinYear           int
+	MaxYear           int
+	MinDuration       time.Duration
+	MaxDuration       time.Duration
+	AudioLanguages    []string
+	SubtitleLanguages []string
+	MinRating         float64
+	MaxRating         float64
+}
+
+type LibrarySort string
+
+const (
+	LibrarySortName    LibrarySort = "name"
+	LibrarySortRating  LibrarySort = "rating"
+	LibrarySortRelease LibrarySort = "release"
+	LibrarySortAdded   LibrarySort = "added"
+)
+
+// FacetValue is the number of library items having a value of a facet
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+// LibraryFacets holds the counts of every value of each filter. The counts of a facet apply all the
+// filters except its own, so they tell how many items selecting a value, or one more, would give.
+type LibraryFacets struct {
+	Genres            []FacetValue
+	Years             []FacetValue
+	Durations         []FacetValue
+	AudioLanguages    []FacetValue
+	SubtitleLanguages []FacetValue
+	Ratings           []FacetValue
+}
+
+type libraryFacet int
+
+const (
+	noFacet libraryFacet = iota
+	genreFacet
+	yearFacet
+	durationFacet
+	audioFacet
+	subtitleFacet
+	ratingFacet
+)
+
+// durationBuckets are the upper bounds, in minutes, of the duration facet values
+var durationBuckets = []int{30, 60, 90, 120}
+
+// libraryTable describes how to reach the categories, ratings and files of movies or tv shows
+type libraryTable struct {
+	table      string
+	categories string
+	ratings    string
+	files      string
+}
+
+var movieLibrary = libraryTable{
+	table:      "movies",
+	categories: "JOIN category_movie ON category_movie.movie_id = movies.id JOIN categories ON categories.id = category_movie.category_id",
+	ratings:    "LEFT JOIN (SELECT movie_id, AVG(rating) AS average_rating FROM movie_ratings GROUP BY movie_id) AS ratings ON ratings.movie_id = movies.id",
+	files:      "JOIN media_files ON media_files.id = movies.media_file_id AND " + availableFile("movies.media_file_id"),
+}
+
+var tvShowLibrary = libraryTable{
+	table:      "tv_shows",
+	categories: "JOIN category_tv_show ON category_tv_show.tv_show_id = tv_shows.id JOIN categories ON categories.id = category_tv_show.category_id",
+	ratings:    "LEFT JOIN (SELECT tv_show_id, AVG(rating) AS average_rating FROM tv_show_ratings GROUP BY tv_show_id) AS ratings ON ratings.tv_show_id = tv_shows.id",
+	files:      "JOIN episodes ON episodes.tv_show_id = tv_shows.id JOIN media_files ON media_files.id = episodes.media_file_id AND " + availableFile("episodes.media_file_id"),
+}
+
+// FilterLibraryMovies returns a page of the available movies matching the filter, and their total number
+func (r *MediaRepository) FilterLibraryMovies(filter LibraryFilter, sort LibrarySort, descending bool, page, limit int) ([]repository.Movie, int, error) {
+	var movies []repository.Movie
+	count, err := r.filterLibrary(movieLibrary, filter, sort, descending, page, limit, &movies)
+	if err != nil {
+		return nil, 0, err
+	}
+	return movies, count, nil
+}
+
+// FilterLibraryTvShows returns a page of the available tv shows matching the filter, and their total number
+func (r *MediaRepository) FilterLibraryTvShows(filter LibraryFilter, sort LibrarySort, descending bool, page, limit int) ([]repository.TvShow, int, error) {
+	var tvShows []repository.TvShow
+	count, err := r.filterLibrary(tvShowLibrary, filter, sort, descending, page, limit, &tvShows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tvShows, count, nil
+}
+
+// GetLibraryMovieFacets returns the facet counts of the available movies for the filter
+func (r *MediaRepository) GetLibraryMovieFacets(filter LibraryFilter) (*LibraryFacets, error) {
+	return r.libraryFacets(movieLibrary, filter)
+}
+
+// GetLibraryTvShowFacets returns the facet counts of the available tv shows for the filter
+func (r *MediaRepository) GetLibraryTvShowFacets(filter LibraryFilter) (*LibraryFacets, error) {
+	return r.libraryFacets(tvShowLibrary, filter)
+}
+
+func (r *MediaRepository) filterLibrary(library libraryTable, filter LibraryFilter, sort LibrarySort, descending bool, page, limit int, dest any) (int, error) {
+	var order string
+	switch sort {
+	case LibrarySortName, "":
+		order = library.table + ".name"
+	case LibrarySortRating:
+		order = "ratings.average_rating"
+	case LibrarySortRelease:
+		order = library.table + ".release_date"
+	case LibrarySortAdded:
+		order = library.table + ".created_at"
+	default:
+		return 0, fmt.Errorf("unknown library sort %q", sort)
+	}
+	if descending {
+		order += " DESC NULLS LAST"
+	} else {
+		order += " ASC NULLS LAST"
+	}
+
+	var count int64
+	result := r.libraryQuery(library, filter, noFacet).
+		Count(&count).
+		Select(library.table + ".*").
+		Order(order).
+		Order(library.table + ".id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(dest)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(count), nil
+}
+
+func (r *MediaRepository) libraryFacets(library libraryTable, filter LibraryFilter) (*LibraryFacets, error) {
+	durationValue := "CASE"
+	previous := 0
+	for _, bound := range durationBuckets {
+		durationValue += fmt.Sprintf(" WHEN media_files.duration < %d THEN '%d-%d'", bound*60, previous, bound)
+		previous = bound
+	}
+	durationValue += fmt.Sprintf(" ELSE '%d+' END", previous)
+
+	facets := &LibraryFacets{}
+	for _, facet := range []struct {
+		facet libraryFacet
+		joins string
+		value string
+		order string
+		dest  *[]FacetValue
+	}{
+		{genreFacet, library.categories, "categories.name", "value", &facets.Genres},
+		{yearFacet, "", fmt.Sprintf("CAST(EXTRACT(YEAR FROM %s.release_date) AS integer)", library.table), "value", &facets.Years},
+		{durationFacet, library.files, durationValue, "MIN(media_files.duration)", &facets.Durations},
+		{audioFacet, library.files + " JOIN audios ON audios.media_file_id = media_files.id", "audios.language", "value", &facets.AudioLanguages},
+		{subtitleFacet, library.files + " JOIN subtitles ON subtitles.media_file_id = media_files.id", "subtitles.language", "value", &facets.SubtitleLanguages},
+		{ratingFacet, "", "CAST(FLOOR(ratings.average_rating) AS integer)", "value", &facets.Ratings},
+	} {
+		query := r.libraryQuery(library, filter, facet.facet)
+		if facet.joins != "" {
+			query = query.Joins(facet.joins)
+		}
+		result := query.
+			Select(fmt.Sprintf("CAST(%s AS text) AS value, COUNT(DISTINCT %s.id) AS count", facet.value, library.table)).
+			Where(facet.value + " IS NOT NULL").
+			Group("value").
+			Order(facet.order).
+			Scan(facet.dest)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+	}
+	return facets, nil
+}
+
+// libraryQuery returns the query of the available items of library matching filter, except for the filter of ignored
+func (r *MediaRepository) libraryQuery(library libraryTable, filter LibraryFilter, ignored libraryFacet) *gorm.DB {
+	query := r.db.Table(library.table).Joins(library.ratings)
+
+	if len(filter.Genres) > 0 && ignored != genreFacet {
+		query = query.Where(fmt.Sprintf("%s.id IN (SELECT %[1]s.id FROM %[1]s %s WHERE categories.name IN ?)", library.table, library.categories), filter.Genres)
+	}
+	if filter.MinYear > 0 && ignored != yearFacet {
+		query = query.Where(fmt.Sprintf("EXTRACT(YEAR FROM %s.release_date) >= ?", library.table), filter.MinYear)
+	}
+	if filter.MaxYear > 0 && ignored != yearFacet {
+		query = query.Where(fmt.Sprintf("EXTRACT(YEAR FROM %s.release_date) <= ?", library.table), filter.MaxYear)
+	}
+	if filter.MinRating > 0 && ignored != ratingFacet {
+		query = query.Where("ratings.average_rating >= ?", filter.MinRating)
+	}
+	if filter.MaxRating > 0 && ignored != ratingFacet {
+		query = query.Where("ratings.average_rating <= ?", filter.MaxRating)
+	}
+
+	// availability and the file filters, matched by a single file
+	files := r.db.Table(library.table).Select(library.table + ".id").Joins(library.files)
+	if filter.MinDuration > 0 && ignored != durationFacet {
+		files = files.Where("media_files.duration >= ?", filter.MinDuration.Seconds())
+	}
+	if filter.MaxDuration > 0 && ignored != durationFacet {
+		files = files.Where("media_files.duration <= ?", filter.MaxDuration.Seconds())
+	}
+	var languages LanguageFilter
+	if ignored != audioFacet {
+		languages.Audios = filter.AudioLanguages
+	}
+	if ignored != subtitleFacet {
+		languages.Subtitles = filter.SubtitleLanguages
+	}
+	languageCondition, languageArgs := languages.condition("media_files.id")
+	files = files.Where(languageCondition, languageArgs...)
+	return query.Where(library.table+".id IN (?)", files)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+// insertLibraryFixtures adds movies and tv shows of the test genres: three available movies, one without file,
+// a tv show having an episode file and one whose single episode has no file
+func insertLibraryFixtures(t *testing.T, r *MediaRepository) {
+	t.Helper()
+	alpha, beta, delta, pilot := "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "1b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e", "2c3d4e5f-6a7b-4c8d-0e9f-1a2b3c4d5e6f", "3d4e5f6a-7b8c-4d9e-1f0a-2b3c4d5e6f7a"
+	genreA, genreB := "4e5f6a7b-8c9d-4e0f-2a1b-3c4d5e6f7a8b", "5f6a7b8c-9d0e-4f1a-3b2c-4d5e6f7a8b9c"
+	user, otherUser := "6a7b8c9d-0e1f-4a2b-4c3d-5e6f7a8b9c0d", "7b8c9d0e-1f2a-4b3c-5d4e-6f7a8b9c0d1e"
+	mustExec(t, r, "INSERT INTO media_files (id, filename, duration) VALUES (?, 'alpha.mp4', 5400), (?, 'beta.mp4', 3000), (?, 'delta.mp4', 8000), (?, 'pilot.mp4', 2500)",
+		alpha, beta, delta, pilot)
+	mustExec(t, r, "INSERT INTO audios (media_file_id, language) VALUES (?, 'fre'), (?, 'eng'), (?, 'fre'), (?, 'eng'), (?, 'ger')",
+		alpha, beta, delta, delta, pilot)
+	mustExec(t, r, "INSERT INTO subtitles (media_file_id, language) VALUES (?, 'eng')", alpha)
+	mustExec(t, r, "INSERT INTO categories (id, name) VALUES (?, 'Test Genre A'), (?, 'Test Genre B')", genreA, genreB)
+	mustExec(t, r, "INSERT INTO movies (id, name, release_date, media_file_id) VALUES "+
+		"(-1, 'Alpha', '1999-03-31', ?), (-2, 'Beta', '2005-05-19', ?), (-3, 'Gamma', '2005-01-01', NULL), (-4, 'Delta', '2010-07-16', ?)",
+		alpha, beta, delta)
+	mustExec(t, r, "INSERT INTO category_movie (movie_id, category_id) VALUES (-1, ?), (-2, ?), (-2, ?), (-3, ?), (-4, ?)",
+		genreA, genreA, genreB, genreA, genreB)
+	mustExec(t, r, "INSERT INTO movie_ratings (user_id, movie_id, rating) VALUES (?, -1, 4), (?, -4, 2), (?, -4, 3)", user, user, otherUser)
+	mustExec(t, r, "INSERT INTO tv_shows (id, name, release_date) VALUES (-1, 'Available', '2011-04-17'), (-2, 'Unavailable', '2011-04-17')")
+	mustExec(t, r, "INSERT INTO episodes (id, tv_show_id, nb_season, nb_episode, media_file_id) VALUES (-1, -1, 1, 1, ?), (-2, -2, 1, 1, NULL)", pilot)
+	mustExec(t, r, "INSERT INTO category_tv_show (tv_show_id, category_id) VALUES (-1, ?), (-2, ?)", genreA, genreA)
+}
+
+func TestFilterLibrary(t *testing.T) {
+	r := newTestRepository(t)
+	insertLibraryFixtures(t, r)
+	genres := []string{"Test Genre A", "Test Genre B"}
+
+	tests := []struct {
+		name   string
+		filter LibraryFilter
+		want   []string
+	}{
+		{"available only", LibraryFilter{Genres: genres}, []string{"Alpha", "Beta", "Delta"}},
+		{"year", LibraryFilter{Genres: genres, MinYear: 2005, MaxYear: 2005}, []string{"Beta"}},
+		{"audio", LibraryFilter{Genres: genres, AudioLanguages: []string{"fre"}}, []string{"Alpha", "Delta"}},
+		{"audio and subtitle", LibraryFilter{Genres: genres, AudioLanguages: []string{"fre"}, SubtitleLanguages: []string{"eng"}}, []string{"Alpha"}},
+		{"rating", LibraryFilter{Genres: genres, MinRating: 3}, []string{"Alpha"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			movies, count, err := r.FilterLibraryMovies(test.filter, LibrarySortName, false, 1, 20)
+			if err != nil {
+				t.Fatalf("FilterLibraryMovies() error = %v", err)
+			}
+			names := make([]string, len(movies))
+			for i, movie := range movies {
+				names[i] = movie.Name
+			}
+			if !reflect.DeepEqual(names, test.want) || count != len(test.want) {
+				t.Errorf("movies = %v (%d), want %v", names, count, test.want)
+			}
+		})
+	}
+
+	tvShows, count, err := r.FilterLibraryTvShows(LibraryFilter{Genres: genres}, LibrarySortName, false, 1, 20)
+	if err != nil {
+		t.Fatalf("FilterLibraryTvShows() error = %v", err)
+	}
+	if count != 1 || len(tvShows) != 1 || tvShows[0].ID != -1 {
+		t.Errorf("tv shows = %+v (%d), want the tv show -1 having an episode file", tvShows, count)
+	}
+}
+
+func TestLibraryFacets(t *testing.T) {
+	r := newTestRepository(t)
+	insertLibraryFixtures(t, r)
+	// testGenres keeps the genre facet values of the fixtures
+	testGenres := func(values []FacetValue) []FacetValue {
+		var kept []FacetValue
+		for _, value := range values {
+			if value.Value == "Test Genre A" || value.Value == "Test Genre B" {
+				kept = append(kept, value)
+			}
+		}
+		return kept
+	}
+
+	facets, err := r.GetLibraryMovieFacets(LibraryFilter{Genres: []string{"Test Genre A", "Test Genre B"}})
+	if err != nil {
+		t.Fatalf("GetLibraryMovieFacets() error = %v", err)
+	}
+	want := &LibraryFacets{
+		Genres:            []FacetValue{{"Test Genre A", 2}, {"Test Genre B", 2}},
+		Years:             []FacetValue{{"1999", 1}, {"2005", 1}, {"2010", 1}},
+		Durations:         []FacetValue{{"30-60", 1}, {"90-120", 1}, {"120+", 1}},
+		AudioLanguages:    []FacetValue{{"eng", 2}, {"fre", 2}},
+		SubtitleLanguages: []FacetValue{{"eng", 1}},
+		Ratings:           []FacetValue{{"2", 1}, {"4", 1}},
+	}
+	facets.Genres = testGenres(facets.Genres)
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("facets = %+v, want %+v", facets, want)
+	}
+
+	// the audio facet ignores the audio filter, the others apply it
+	facets, err = r.GetLibraryMovieFacets(LibraryFilter{Genres: []string{"Test Genre A"}, AudioLanguages: []string{"fre"}})
+	if err != nil {
+		t.Fatalf("GetLibraryMovieFacets() error = %v", err)
+	}
+	if got := testGenres(facets.Genres); !reflect.DeepEqual(got, []FacetValue{{"Test Genre A", 1}, {"Test Genre B", 1}}) {
+		t.Errorf("genre facet = %+v, want 1 movie for each test genre", got)
+	}
+	if !reflect.DeepEqual(facets.AudioLanguages, []FacetValue{{"eng", 1}, {"fre", 1}}) {
+		t.Errorf("audio facet = %+v, want 1 movie for eng and fre", facets.AudioLanguages)
+	}
+	if !reflect.DeepEqual(facets.Years, []FacetValue{{"1999", 1}}) {
+		t.Errorf("year facet = %+v, want 1 movie in 1999", facets.Years)
+	}
+
+	facets, err = r.GetLibraryTvShowFacets(LibraryFilter{Genres: []string{"Test Genre A"}})
+	if err != nil {
+		t.Fatalf("GetLibraryTvShowFacets() error = %v", err)
+	}
+	if !reflect.DeepEqual(facets.Durations, []FacetValue{{"30-60", 1}}) || !reflect.DeepEqual(facets.AudioLanguages, []FacetValue{{"ger", 1}}) {
+		t.Errorf("tv show facets = %+v, want the available tv show only", facets)
+	}
+}
+
+func TestGetAvailableMedia(t *testing.T) {
+	r := newTestRepository(t)
+	insertLibraryFixtures(t, r)
+
+	available, err := r.GetAvailableMedia([]SavedMedia{
+		{Type: SavedMovie, ID: -1}, {Type: SavedMovie, ID: -3},
+		{Type: SavedTvShow, ID: -1}, {Type: SavedTvShow, ID: -2},
+		{Type: SavedEpisode, ID: -1}, {Type: SavedEpisode, ID: -2},
+	})
+	if err != nil {
+		t.Fatalf("GetAvailableMedia() error = %v", err)
+	}
+	want := map[SavedMediaType]map[int]bool{SavedMovie: {-1: true}, SavedTvShow: {-1: true}, SavedEpisode: {-1: true}}
+	if !reflect.DeepEqual(available, want) {
+		t.Errorf("GetAvailableMedia() = %v, want %v", available, want)
+	}
+}
@@ -1,24 +1,38 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"github.com/bingemate/media-go-pkg/repository"
 	"github.com/bingemate/media-go-pkg/tmdb"
+	"github.com/bingemate/media-service/internal/logging"
 	"gorm.io/gorm"
 	"log"
+	"log/slog"
 	"math"
+	"strconv"
 	"time"
 )
 
 type MediaRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	hooks *saveHooks
 }
 
 func NewMediaRepository(db *gorm.DB) *MediaRepository {
 	if db == nil {
 		log.Fatal("db is nil")
 	}
-	return &MediaRepository{db}
+	return &MediaRepository{db, &saveHooks{}}
+}
+
+// WithContext returns a copy of the repository whose queries run within the given context
+func (r *MediaRepository) WithContext(ctx context.Context) *MediaRepository {
+	return &MediaRepository{r.db.WithContext(ctx), r.hooks}
+}
+
+func (r *MediaRepository) logger() *slog.Logger {
+	return logging.FromContext(r.db.Statement.Context)
 }
 
 //// GetMediaRating returns the average rating and the number of ratings for a media given the mediaID (TMDB ID)
@@ -136,6 +150,7 @@ func (r *MediaRepository) GetEpisodeFileInfo(episodeID int) (*repository.MediaFi
 		Preload("MediaFile.Audios").
 		Preload("MediaFile.Subtitles").
 		Where("episodes.id = ?", episodeID).
+		Where(availableFile("episodes.media_file_id")).
 		First(&episode).Error
 	if err != nil {
 		return nil, err
@@ -151,6 +166,7 @@ func (r *MediaRepository) GetMovieFileInfo(movieID int) (*repository.MediaFile,
 		Preload("MediaFile.Audios").
 		Preload("MediaFile.Subtitles").
 		Where("movies.id = ?", movieID).
+		Where(availableFile("movies.media_file_id")).
 		First(&mediaFile).Error
 	if err != nil {
 		return nil, err
@@ -158,60 +174,76 @@ func (r *MediaRepository) GetMovieFileInfo(movieID int) (*repository.MediaFile,
 	return mediaFile.MediaFile, nil
 }
 
-// SearchEpisodeFiles returns a list of episodes given a query
-func (r *MediaRepository) SearchEpisodeFiles(query string, page, limit int) ([]*repository.Episode, int, error) {
-	var (
-		episodes []*repository.Episode
-		count    int64
+// SearchEpisodeFiles returns a page of the available episodes matching the query on their name or the name of their tv show,
+// ordered by relevance then by decreasing creation date
+func (r *MediaRepository) SearchEpisodeFiles(query string, languages LanguageFilter, page Page) (*CursorPage[*repository.Episode], error) {
+	search := newTextSearch(query)
+	episodeCondition, episodeArgs := search.condition("episodes")
+	tvShowCondition, tvShowArgs := search.condition(`"TvShow"`)
+	episodeRank, episodeRankArgs := search.rank("episodes")
+	tvShowRank, tvShowRankArgs := search.rank(`"TvShow"`)
+	languageCondition, languageArgs := languages.condition("episodes.media_file_id")
+
+	keys, total, err := r.searchFileKeys(
+		r.db.Model(&repository.Episode{}).
+			Joins("TvShow").
+			Where(availableFile("episodes.media_file_id")).
+			Where(episodeCondition+" OR "+tvShowCondition, append(episodeArgs, tvShowArgs...)...).
+			Where(languageCondition, languageArgs...),
+		"episodes",
+		"GREATEST("+episodeRank+", "+tvShowRank+")", append(episodeRankArgs, tvShowRankArgs...),
+		page,
 	)
-	offset := (page - 1) * limit
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.
-		Model(&repository.Episode{}).
+	var episodes []*repository.Episode
+	err = r.db.
 		Joins("TvShow").
 		Joins("MediaFile").
-		Where("media_file_id IS NOT NULL").
-		Where(`unaccent(episodes.name) ILIKE unaccent(?) OR "TvShow".name ILIKE unaccent(?)`, "%"+query+"%", "%"+query+"%").
 		Preload("TvShow").
 		Preload("MediaFile.Audios").
 		Preload("MediaFile.Subtitles").
-		Order("episodes.created_at DESC, episodes.updated_at DESC").
-		Group("episodes.id").
-		Count(&count).
-		Offset(offset).
-		Limit(limit).
+		Where("episodes.id IN ?", fileKeyIDs(keys)).
 		Find(&episodes).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	return episodes, int(count), nil
+	return newFileSearchPage(keys, episodes, total, page.Limit, func(episode *repository.Episode) int { return episode.ID }), nil
 }
 
-// SearchMovieFiles returns a list of movies given a query
-func (r *MediaRepository) SearchMovieFiles(query string, page, limit int) ([]*repository.Movie, int, error) {
-	var (
-		movies []*repository.Movie
-		count  int64
+// SearchMovieFiles returns a page of the available movies matching the query, ordered by relevance then by decreasing creation date
+func (r *MediaRepository) SearchMovieFiles(query string, languages LanguageFilter, page Page) (*CursorPage[*repository.Movie], error) {
+	search := newTextSearch(query)
+	condition, args := search.condition("movies")
+	rank, rankArgs := search.rank("movies")
+	languageCondition, languageArgs := languages.condition("movies.media_file_id")
+
+	keys, total, err := r.searchFileKeys(
+		r.db.Model(&repository.Movie{}).
+			Where(availableFile("movies.media_file_id")).
+			Where(condition, args...).
+			Where(languageCondition, languageArgs...),
+		"movies",
+		rank, rankArgs,
+		page,
 	)
-	offset := (page - 1) * limit
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.
-		Model(&repository.Movie{}).
+	var movies []*repository.Movie
+	err = r.db.
 		Joins("MediaFile").
-		Where("media_file_id IS NOT NULL").
-		Where(`unaccent(movies.name) ILIKE unaccent(?)`, "%"+query+"%").
 		Preload("MediaFile.Audios").
 		Preload("MediaFile.Subtitles").
-		Order("movies.created_at DESC, movies.updated_at DESC").
-		Group("movies.id").
-		Count(&count).
-		Offset(offset).
-		Limit(limit).
+		Where("movies.id IN ?", fileKeyIDs(keys)).
 		Find(&movies).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	return movies, int(count), nil
+	return newFileSearchPage(keys, movies, total, page.Limit, func(movie *repository.Movie) int { return movie.ID }), nil
 }
 
 // MediaFilesTotalSize returns the total size of all media files
@@ -270,35 +302,66 @@ func (r *MediaRepository) IsEpisodePresent(episodeID int) bool {
 // IsMovieFilePresent returns true if the movie file is present in the database
 func (r *MediaRepository) IsMovieFilePresent(movieID int) bool {
 	var count int64
-	r.db.Model(&repository.Movie{}).Where("id = ? AND media_file_id IS NOT NULL", movieID).Count(&count)
+	r.db.Model(&repository.Movie{}).Where("movies.id = ? AND "+availableFile("movies.media_file_id"), movieID).Count(&count)
 	return count > 0
 }
 
 // IsEpisodeFilePresent returns true if the episode file is present in the database
 func (r *MediaRepository) IsEpisodeFilePresent(episodeID int) bool {
 	var count int64
-	r.db.Model(&repository.Episode{}).Where("id = ? AND media_file_id IS NOT NULL", episodeID).Count(&count)
+	r.db.Model(&repository.Episode{}).Where("episodes.id = ? AND "+availableFile("episodes.media_file_id"), episodeID).Count(&count)
 	return count > 0
 }
 
 // IsTvShowHasEpisodeFiles returns true if the tv show has episode files in the database
 func (r *MediaRepository) IsTvShowHasEpisodeFiles(tvShowID int) bool {
 	var count int64
-	r.db.Model(&repository.Episode{}).Where("tv_show_id = ? AND media_file_id IS NOT NULL", tvShowID).Count(&count)
+	r.db.Model(&repository.Episode{}).Where("episodes.tv_show_id = ? AND "+availableFile("episodes.media_file_id"), tvShowID).Count(&count)
 	return count > 0
 }
 
+// GetAvailableMedia returns which of the given movies, tv shows and episodes have a file, one of their episodes
+// for the tv shows
+func (r *MediaRepository) GetAvailableMedia(media []SavedMedia) (map[SavedMediaType]map[int]bool, error) {
+	available := map[SavedMediaType]map[int]bool{SavedMovie: {}, SavedTvShow: {}, SavedEpisode: {}}
+	if len(media) == 0 {
+		return available, nil
+	}
+	ids := map[SavedMediaType][]int{}
+	for _, m := range media {
+		ids[m.Type] = append(ids[m.Type], m.ID)
+	}
+	var rows []struct {
+		Type SavedMediaType
+		ID   int
+	}
+	result := r.db.Raw("SELECT ? AS type, id FROM movies WHERE id IN ? AND "+availableFile("movies.media_file_id")+
+		" UNION SELECT ?, tv_show_id FROM episodes WHERE tv_show_id IN ? AND "+availableFile("episodes.media_file_id")+
+		" UNION SELECT ?, id FROM episodes WHERE id IN ? AND "+availableFile("episodes.media_file_id"),
+		SavedMovie, ids[SavedMovie], SavedTvShow, ids[SavedTvShow], SavedEpisode, ids[SavedEpisode]).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		available[row.Type][row.ID] = true
+	}
+	return available, nil
+}
+
 // GetAvailableMoviesByRating returns a list of movies ordered by rating
 // Return also the total number of results
-func (r *MediaRepository) GetAvailableMoviesByRating(page, limit, days int) ([]repository.Movie, int, error) {
+func (r *MediaRepository) GetAvailableMoviesByRating(languages LanguageFilter, page, limit, days int) ([]repository.Movie, int, error) {
 	var movies []repository.Movie
 	offset := (page - 1) * limit
 
 	var count int64
+	languageCondition, languageArgs := languages.condition("movies.media_file_id")
 	result := r.db.Table("movies").
 		Select("movies.*, AVG(movie_ratings.rating) as average_rating").
 		Joins("LEFT JOIN movie_ratings ON movie_ratings.movie_id = movies.id AND movie_ratings.created_at > ?", time.Now().AddDate(0, 0, -days)).
-		Where("movies.media_file_id IS NOT NULL").
+		Where(availableFile("movies.media_file_id")).
+		Where(languageCondition, languageArgs...).
 		Group("movies.id").
 		Count(&count).
 		Order("average_rating DESC").
@@ -314,15 +377,17 @@ func (r *MediaRepository) GetAvailableMoviesByRating(page, limit, days int) ([]r
 
 // GetAvailableTvShowsByRating returns a list of tv shows ordered by rating
 // Return also the total number of results
-func (r *MediaRepository) GetAvailableTvShowsByRating(page, limit, days int) ([]repository.TvShow, int, error) {
+func (r *MediaRepository) GetAvailableTvShowsByRating(languages LanguageFilter, page, limit, days int) ([]repository.TvShow, int, error) {
 	var tvShows []repository.TvShow
 	offset := (page - 1) * limit
 	var count int64
+	languageCondition, languageArgs := languages.condition("episodes.media_file_id")
 	result := r.db.Table("tv_shows").
 		Select("tv_shows.*, AVG(tv_show_ratings.rating) as average_rating").
 		Joins("LEFT JOIN tv_show_ratings ON tv_show_ratings.tv_show_id = tv_shows.id AND tv_show_ratings.created_at > ?", time.Now().AddDate(0, 0, -days)).
 		Joins("JOIN episodes ON episodes.tv_show_id = tv_shows.id").
-		Where("episodes.media_file_id IS NOT NULL").
+		Where(availableFile("episodes.media_file_id")).
+		Where(languageCondition, languageArgs...).
 		Group("tv_shows.id").
 		Having("COUNT(DISTINCT episodes.id) > 0").
 		Count(&count).
@@ -340,18 +405,23 @@ func (r *MediaRepository) GetAvailableTvShowsByRating(page, limit, days int) ([]
 // SearchAvailableMovies returns a list of movies matching the search query
 // Return also the total number of results
 // Results are ordered by pertinence and / or rating
-func (r *MediaRepository) SearchAvailableMovies(page, limit int, query string) ([]repository.Movie, int, error) {
+func (r *MediaRepository) SearchAvailableMovies(page, limit int, query string, languages LanguageFilter) ([]repository.Movie, int, error) {
 	var movies []repository.Movie
 	offset := (page - 1) * limit
 
 	var count int64
+	search := newTextSearch(query)
+	condition, args := search.condition("movies")
+	languageCondition, languageArgs := languages.condition("movies.media_file_id")
 	result := r.db.Table("movies").
 		Select("movies.*, AVG(movie_ratings.rating) as average_rating").
 		Joins("LEFT JOIN movie_ratings ON movie_ratings.movie_id = movies.id").
-		Where("movies.media_file_id IS NOT NULL AND unaccent(movies.name) ILIKE unaccent(?)", "%"+query+"%").
+		Where(availableFile("movies.media_file_id")).
+		Where(condition, args...).
+		Where(languageCondition, languageArgs...).
 		Group("movies.id").
 		Count(&count).
-		Order("average_rating DESC, movies.name ASC").
+		Clauses(search.orderBy("movies", "average_rating DESC, movies.name ASC")).
 		Offset(offset).
 		Limit(limit).
 		Find(&movies)
@@ -365,21 +435,25 @@ func (r *MediaRepository) SearchAvailableMovies(page, limit int, query string) (
 // SearchAvailableTvShows returns a list of tv shows matching the search query
 // Return also the total number of results
 // Results are ordered by pertinence and / or rating
-func (r *MediaRepository) SearchAvailableTvShows(page, limit int, query string) ([]repository.TvShow, int, error) {
+func (r *MediaRepository) SearchAvailableTvShows(page, limit int, query string, languages LanguageFilter) ([]repository.TvShow, int, error) {
 	var tvShows []repository.TvShow
 	offset := (page - 1) * limit
 
 	var count int64
+	search := newTextSearch(query)
+	condition, args := search.condition("tv_shows")
+	languageCondition, languageArgs := languages.condition("episodes.media_file_id")
 	result := r.db.Table("tv_shows").
 		Select("tv_shows.*, AVG(tv_show_ratings.rating) as average_rating").
 		Joins("LEFT JOIN tv_show_ratings ON tv_show_ratings.tv_show_id = tv_shows.id").
 		Joins("JOIN episodes ON episodes.tv_show_id = tv_shows.id").
-		Where("unaccent(tv_shows.name) ILIKE unaccent(?)", "%"+query+"%").
-		Where("episodes.media_file_id IS NOT NULL").
+		Where(condition, args...).
+		Where(availableFile("episodes.media_file_id")).
+		Where(languageCondition, languageArgs...).
 		Group("tv_shows.id").
 		Having("COUNT(DISTINCT episodes.id) > 0").
 		Count(&count).
-		Order("average_rating DESC, tv_shows.name ASC").
+		Clauses(search.orderBy("tv_shows", "average_rating DESC, tv_shows.name ASC")).
 		Offset(offset).
 		Limit(limit).
 		Find(&tvShows)
@@ -391,13 +465,15 @@ func (r *MediaRepository) SearchAvailableTvShows(page, limit int, query string)
 }
 
 // GetAvailableRecentMovies returns a list of recently added movies
-func (r *MediaRepository) GetAvailableRecentMovies(page, limit int) ([]repository.Movie, int, error) {
+func (r *MediaRepository) GetAvailableRecentMovies(languages LanguageFilter, page, limit int) ([]repository.Movie, int, error) {
 	var movies []repository.Movie
 	offset := (page - 1) * limit
 	var count int64
+	languageCondition, languageArgs := languages.condition("movies.media_file_id")
 	result := r.db.Table("movies").
 		Select("*").
-		Where("movies.media_file_id IS NOT NULL").
+		Where(availableFile("movies.media_file_id")).
+		Where(languageCondition, languageArgs...).
 		Count(&count).
 		Order("movies.updated_at DESC, movies.created_at DESC").
 		Offset(offset).
@@ -411,13 +487,15 @@ func (r *MediaRepository) GetAvailableRecentMovies(page, limit int) ([]repositor
 }
 
 // GetAvailableRecentTvShows returns a list of recently added tv shows
-func (r *MediaRepository) GetAvailableRecentTvShows(page, limit int) ([]repository.TvShow, int, error) {
+func (r *MediaRepository) GetAvailableRecentTvShows(languages LanguageFilter, page, limit int) ([]repository.TvShow, int, error) {
 	var tvShows []repository.TvShow
 	offset := (page - 1) * limit
 	var count int64
+	languageCondition, languageArgs := languages.condition("episodes.media_file_id")
 	result := r.db.Table("tv_shows").
 		Joins("JOIN episodes ON episodes.tv_show_id = tv_shows.id").
-		Where("episodes.media_file_id IS NOT NULL").
+		Where(availableFile("episodes.media_file_id")).
+		Where(languageCondition, languageArgs...).
 		Group("tv_shows.id").
 		Having("COUNT(DISTINCT episodes.id) > 0").
 		Having("MAX(episodes.updated_at) IS NOT NULL").
@@ -470,7 +548,7 @@ func (r *MediaRepository) GetMoviesByComments(present bool) (*[]int, error) {
 
 	if present {
 		// Add WHERE clause to check if movie_id exists in the "movie" table
-		query = query.Joins("JOIN movies ON movie_comments.movie_id = movies.id").Where("movies.id = movie_comments.movie_id AND movies.media_file_id IS NOT NULL")
+		query = query.Joins("JOIN movies ON movie_comments.movie_id = movies.id").Where("movies.id = movie_comments.movie_id AND " + availableFile("movies.media_file_id"))
 	}
 
 	err := query.Find(&movieIds).Error
@@ -573,41 +651,17 @@ func (r *MediaRepository) GetFollowedTvShowsReleases(userID string) (*[]int, err
 //}
 
 // GetMovieComments returns a list of comments for a movie
-func (r *MediaRepository) GetMovieComments(movieID, size, page int) ([]*repository.MovieComment, int, error) {
-	var comments []*repository.MovieComment
-	var count int64
-	offset := (page - 1) * size
-	result := r.db.Model(&repository.MovieComment{}).
-		Where("movie_id = ?", movieID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(size).
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return comments, int(count), nil
+func (r *MediaRepository) GetMovieComments(movieID int, page Page) (*CursorPage[*repository.MovieComment], error) {
+	query := r.db.Model(&repository.MovieComment{}).
+		Where("movie_id = ?", movieID)
+	return paginate(query, uuidKey("id"), page, movieCommentCursor)
 }
 
 // GetTvShowComments returns a list of comments for a tv show
-func (r *MediaRepository) GetTvShowComments(tvShowID, size, page int) ([]*repository.TvShowComment, int, error) {
-	var comments []*repository.TvShowComment
-	var count int64
-	offset := (page - 1) * size
-	result := r.db.Model(&repository.TvShowComment{}).
-		Where("tv_show_id = ?", tvShowID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(size).
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return comments, int(count), nil
+func (r *MediaRepository) GetTvShowComments(tvShowID int, page Page) (*CursorPage[*repository.TvShowComment], error) {
+	query := r.db.Model(&repository.TvShowComment{}).
+		Where("tv_show_id = ?", tvShowID)
+	return paginate(query, uuidKey("id"), page, tvShowCommentCursor)
 }
 
 //func (r *MediaRepository) GetUserComments(userID string, size, page int) ([]*repository.Comment, int, error) {
@@ -629,97 +683,72 @@ func (r *MediaRepository) GetTvShowComments(tvShowID, size, page int) ([]*reposi
 //}
 
 // GetUserMovieComments returns a list of comments for a movie
-func (r *MediaRepository) GetUserMovieComments(userID string, size, page int) ([]*repository.MovieComment, int, error) {
-	var comments []*repository.MovieComment
-	var count int64
-	offset := (page - 1) * size
-	result := r.db.Model(&repository.MovieComment{}).
-		Where("user_id = ?", userID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(size).
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return comments, int(count), nil
+func (r *MediaRepository) GetUserMovieComments(userID string, page Page) (*CursorPage[*repository.MovieComment], error) {
+	query := r.db.Model(&repository.MovieComment{}).
+		Where("user_id = ?", userID)
+	return paginate(query, uuidKey("id"), page, movieCommentCursor)
 }
 
 // GetUserTvShowComments returns a list of comments for a tv show
-func (r *MediaRepository) GetUserTvShowComments(userID string, size, page int) ([]*repository.TvShowComment, int, error) {
-	var comments []*repository.TvShowComment
-	var count int64
-	offset := (page - 1) * size
-	result := r.db.Model(&repository.TvShowComment{}).
-		Where("user_id = ?", userID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(size).
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return comments, int(count), nil
+func (r *MediaRepository) GetUserTvShowComments(userID string, page Page) (*CursorPage[*repository.TvShowComment], error) {
+	query := r.db.Model(&repository.TvShowComment{}).
+		Where("user_id = ?", userID)
+	return paginate(query, uuidKey("id"), page, tvShowCommentCursor)
 }
 
-// GetUserMovieCommentsByRange returns a list of comments for a movie
-func (r *MediaRepository) GetUserMovieCommentsByRange(userID string, start, end time.Time) ([]*repository.MovieComment, error) {
-	var comments []*repository.MovieComment
-	result := r.db.Model(&repository.MovieComment{}).
-		Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, start, end).
-		Order("created_at DESC").
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return comments, nil
+func movieCommentCursor(comment *repository.MovieComment) Cursor {
+	return Cursor{CreatedAt: comment.CreatedAt, ID: comment.ID}
 }
 
-// GetUserTvShowCommentsByRange returns a list of comments for a tv show
-func (r *MediaRepository) GetUserTvShowCommentsByRange(userID string, start, end time.Time) ([]*repository.TvShowComment, error) {
-	var comments []*repository.TvShowComment
-	result := r.db.Model(&repository.TvShowComment{}).
-		Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, start, end).
-		Order("created_at DESC").
-		Find(&comments)
+func tvShowCommentCursor(comment *repository.TvShowComment) Cursor {
+	return Cursor{CreatedAt: comment.CreatedAt, ID: comment.ID}
+}
 
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return comments, nil
+// CommentDay is the number of movie and tv show comments posted on a UTC day, Day being its YYYY-MM-DD date
+type CommentDay struct {
+	CreatedAt time.Time
+	Day       string
+	Count     int
 }
 
-// GetMovieCommentsByRange returns a list of comments for a movie
-func (r *MediaRepository) GetMovieCommentsByRange(start, end time.Time) ([]*repository.MovieComment, error) {
-	var comments []*repository.MovieComment
-	result := r.db.Model(&repository.MovieComment{}).
-		Where("created_at BETWEEN ? AND ?", start, end).
-		Order("created_at DESC").
-		Find(&comments)
+// GetCommentHistory returns a page of the days from start to end, both included, having comments, with their number
+// of comments, the most recent first. The comments are the ones of userID, or of every user if it is empty.
+func (r *MediaRepository) GetCommentHistory(userID string, start, end time.Time, page Page) (*CursorPage[*CommentDay], error) {
+	return paginate(r.db.Table("(?) AS days", r.commentDays(userID, start, end)), stringKey("day"), page, func(day *CommentDay) Cursor {
+		return Cursor{CreatedAt: day.CreatedAt, ID: day.Day}
+	})
+}
 
+// GetCommentDays returns all the days from start to end, both included, having comments, with their number of
+// comments, the oldest first. The comments are the ones of userID, or of every user if it is empty.
+func (r *MediaRepository) GetCommentDays(userID string, start, end time.Time) ([]*CommentDay, error) {
+	var days []*CommentDay
+	result := r.db.Table("(?) AS days", r.commentDays(userID, start, end)).
+		Order("created_at").
+		Find(&days)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return comments, nil
+	return days, nil
 }
 
-// GetTvShowCommentsByRange returns a list of comments for a tv show
-func (r *MediaRepository) GetTvShowCommentsByRange(start, end time.Time) ([]*repository.TvShowComment, error) {
-	var comments []*repository.TvShowComment
-	result := r.db.Model(&repository.TvShowComment{}).
-		Where("created_at BETWEEN ? AND ?", start, end).
-		Order("created_at DESC").
-		Find(&comments)
-
-	if result.Error != nil {
-		return nil, result.Error
+// commentDays returns the query of the days from start to end having comments of userID, or of every user if it is
+// empty, with their number of comments
+func (r *MediaRepository) commentDays(userID string, start, end time.Time) *gorm.DB {
+	comments := func(model any) *gorm.DB {
+		query := r.db.Model(model).
+			Select("created_at").
+			Where("created_at >= ? AND created_at < ?", start, end.AddDate(0, 0, 1))
+		if userID != "" {
+			query = query.Where("user_id = ?", userID)
+		}
+		return query
 	}
-	return comments, nil
+	// the days are UTC ones, as the ones of the view log, whatever the time zone of the database session
+	return r.db.Raw("SELECT DATE_TRUNC('day', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS created_at, "+
+		"TO_CHAR(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*) AS count "+
+		"FROM (? UNION ALL ?) AS comments GROUP BY 1, 2",
+		comments(&repository.MovieComment{}), comments(&repository.TvShowComment{}))
 }
 
 // CountUserMovieComments returns the number of comments for a movie
@@ -906,45 +935,21 @@ func (r *MediaRepository) UpdateTvShowComment(commentID string, content string)
 //}
 
 // GetMovieRatings returns movie ratings
-func (r *MediaRepository) GetMovieRatings(movieID, limit, page int) ([]*repository.MovieRating, int, error) {
-	offset := (page - 1) * limit
-
-	var ratings []*repository.MovieRating
-	var count int64
-	result := r.db.
-		Model(&repository.MovieRating{}).
-		Where("movie_id = ?", movieID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&ratings)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return ratings, int(count), nil
+func (r *MediaRepository) GetMovieRatings(movieID int, page Page) (*CursorPage[*repository.MovieRating], error) {
+	query := r.db.Model(&repository.MovieRating{}).
+		Where("movie_id = ?", movieID)
+	return paginate(query, uuidKey("user_id"), page, func(rating *repository.MovieRating) Cursor {
+		return Cursor{CreatedAt: rating.CreatedAt, ID: rating.UserID}
+	})
 }
 
 // GetTvShowRatings returns tv show ratings
-func (r *MediaRepository) GetTvShowRatings(tvShowID, limit, page int) ([]*repository.TvShowRating, int, error) {
-	offset := (page - 1) * limit
-
-	var ratings []*repository.TvShowRating
-	var count int64
-	result := r.db.
-		Model(&repository.TvShowRating{}).
-		Where("tv_show_id = ?", tvShowID).
-		Count(&count).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&ratings)
-
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return ratings, int(count), nil
+func (r *MediaRepository) GetTvShowRatings(tvShowID int, page Page) (*CursorPage[*repository.TvShowRating], error) {
+	query := r.db.Model(&repository.TvShowRating{}).
+		Where("tv_show_id = ?", tvShowID)
+	return paginate(query, uuidKey("user_id"), page, func(rating *repository.TvShowRating) Cursor {
+		return Cursor{CreatedAt: rating.CreatedAt, ID: rating.UserID}
+	})
 }
 
 //func (r *MediaRepository) GetUserMediaRating(userID string, mediaID int) (*repository.Rating, error) {
@@ -995,41 +1000,21 @@ func (r *MediaRepository) GetUserTvShowRating(userID string, tvShowID int) (*rep
 //}
 
 // GetUserMovieRatings returns a user's movie ratings
-func (r *MediaRepository) GetUserMovieRatings(userID string, limit, page int) ([]*repository.MovieRating, int, error) {
-	offset := (page - 1) * limit
-
-	var ratings []*repository.MovieRating
-	var count int64
-	result := r.db.
-		Model(&repository.MovieRating{}).
-		Where("user_id = ?", userID).
-		Count(&count).
-		Limit(limit).
-		Offset(offset).
-		Find(&ratings)
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return ratings, int(count), nil
+func (r *MediaRepository) GetUserMovieRatings(userID string, page Page) (*CursorPage[*repository.MovieRating], error) {
+	query := r.db.Model(&repository.MovieRating{}).
+		Where("user_id = ?", userID)
+	return paginate(query, intKey("movie_id"), page, func(rating *repository.MovieRating) Cursor {
+		return Cursor{CreatedAt: rating.CreatedAt, ID: strconv.Itoa(rating.MovieID)}
+	})
 }
 
 // GetUserTvShowRatings returns a user's tv show ratings
-func (r *MediaRepository) GetUserTvShowRatings(userID string, limit, page int) ([]*repository.TvShowRating, int, error) {
-	offset := (page - 1) * limit
-
-	var ratings []*repository.TvShowRating
-	var count int64
-	result := r.db.
-		Model(&repository.TvShowRating{}).
-		Where("user_id = ?", userID).
-		Count(&count).
-		Limit(limit).
-		Offset(offset).
-		Find(&ratings)
-	if result.Error != nil {
-		return nil, 0, result.Error
-	}
-	return ratings, int(count), nil
+func (r *MediaRepository) GetUserTvShowRatings(userID string, page Page) (*CursorPage[*repository.TvShowRating], error) {
+	query := r.db.Model(&repository.TvShowRating{}).
+		Where("user_id = ?", userID)
+	return paginate(query, intKey("tv_show_id"), page, func(rating *repository.TvShowRating) Cursor {
+		return Cursor{CreatedAt: rating.CreatedAt, ID: strconv.Itoa(rating.TvShowID)}
+	})
 }
 
 //func (r *MediaRepository) SaveMediaRating(mediaID int, userID string, rating int) (*repository.Rating, error) {
@@ -1094,7 +1079,7 @@ func (r *MediaRepository) SaveMovie(movie *tmdb.Movie) error {
 	}
 	releaseDate, err := time.Parse("2006-01-02", movie.ReleaseDate)
 	if err != nil {
-		log.Printf("error parsing release date: %v", err)
+		r.logger().Warn("error parsing release date", "movie_id", movie.ID, "error", err)
 		releaseDate = time.Unix(0, 0)
 	}
 
@@ -1112,6 +1097,7 @@ func (r *MediaRepository) SaveMovie(movie *tmdb.Movie) error {
 	if err != nil {
 		return err
 	}
+	r.hooks.saved(SavedMedia{Type: SavedMovie, ID: movie.ID, Name: movie.Title})
 	return nil
 }
 
@@ -1121,7 +1107,7 @@ func (r *MediaRepository) SaveTvShow(tvShow *tmdb.TVShow) error {
 	}
 	releaseDate, err := time.Parse("2006-01-02", tvShow.ReleaseDate)
 	if err != nil {
-		log.Printf("error parsing release date: %v", err)
+		r.logger().Warn("error parsing release date", "tv_show_id", tvShow.ID, "error", err)
 		releaseDate = time.Unix(0, 0)
 	}
 
@@ -1139,6 +1125,7 @@ func (r *MediaRepository) SaveTvShow(tvShow *tmdb.TVShow) error {
 	if err != nil {
 		return err
 	}
+	r.hooks.saved(SavedMedia{Type: SavedTvShow, ID: tvShow.ID, Name: tvShow.Title})
 	return nil
 }
 
@@ -1151,7 +1138,7 @@ func (r *MediaRepository) SaveEpisode(episode *tmdb.TVEpisode) error {
 	}
 	releaseDate, err := time.Parse("2006-01-02", episode.AirDate)
 	if err != nil {
-		log.Printf("error parsing release date: %v", err)
+		r.logger().Warn("error parsing release date", "episode_id", episode.ID, "error", err)
 		releaseDate = time.Unix(0, 0)
 	}
 	episodeEntity := &repository.Episode{
@@ -1162,7 +1149,12 @@ func (r *MediaRepository) SaveEpisode(episode *tmdb.TVEpisode) error {
 		NbEpisode:   episode.EpisodeNumber,
 		ReleaseDate: releaseDate,
 	}
-	return r.db.Save(episodeEntity).Error
+	err = r.db.Save(episodeEntity).Error
+	if err != nil {
+		return err
+	}
+	r.hooks.saved(SavedMedia{Type: SavedEpisode, ID: episode.ID, TvShowID: episode.TVShowID, Name: episode.Name})
+	return nil
 }
 
 func (r *MediaRepository) extractCategories(pkgCategories *[]tmdb.Genre) *[]repository.Category {
@@ -1204,7 +1196,7 @@ func (r *MediaRepository) AvailableEpisodes(tvShowID int) (*[]int, error) {
 
 	result := r.db.Model(&repository.Episode{}).
 		Select("id").
-		Where("tv_show_id = ? AND media_file_id IS NOT NULL", tvShowID).
+		Where("episodes.tv_show_id = ? AND "+availableFile("episodes.media_file_id"), tvShowID).
 		Order("nb_season, nb_episode").
 		Find(&episodeIDs)
 	if result.Error != nil {
@@ -1337,3 +1329,216 @@ func (r *MediaRepository) CountEpisodesTotalDuration() (int64, error) {
 	}
 	return int64(math.Round(duration)), nil
 }
+
+// GetMovieIDs returns the IDs (TMDB ID) of all the movies stored in the database
+func (r *MediaRepository) GetMovieIDs() ([]int, error) {
+	var movieIDs []int
+	result := r.db.Model(&repository.Movie{}).
+		Order("id").
+		Pluck("id", &movieIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return movieIDs, nil
+}
+
+// GetTvShowIDs returns the IDs (TMDB ID) of all the tv shows stored in the database
+func (r *MediaRepository) GetTvShowIDs() ([]int, error) {
+	var tvShowIDs []int
+	result := r.db.Model(&repository.TvShow{}).
+		Order("id").
+		Pluck("id", &tvShowIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tvShowIDs, nil
+}
+
+// GetTvShowEpisodes returns all the episodes of a tv show stored in the database, ordered by season and number
+func (r *MediaRepository) GetTvShowEpisodes(tvShowID int) ([]repository.Episode, error) {
+	var episodes []repository.Episode
+	result := r.db.
+		Where("tv_show_id = ?", tvShowID).
+		Order("nb_season, nb_episode").
+		Find(&episodes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return episodes, nil
+}
+
+// UpdateMovie overwrites the metadata of a stored movie (name, release date and categories), keeping its file
+func (r *MediaRepository) UpdateMovie(movie *tmdb.Movie) error {
+	releaseDate, err := time.Parse("2006-01-02", movie.ReleaseDate)
+	if err != nil {
+		r.logger().Warn("error parsing release date", "movie_id", movie.ID, "error", err)
+		releaseDate = time.Unix(0, 0)
+	}
+	movieEntity := &repository.Movie{ID: movie.ID}
+	err = r.db.Model(movieEntity).Updates(map[string]any{
+		"name":         movie.Title,
+		"release_date": releaseDate,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Model(movieEntity).Association("Categories").Replace(*r.extractCategories(&movie.Genres))
+}
+
+// UpdateTvShow overwrites the metadata of a stored tv show (name, release date and categories)
+func (r *MediaRepository) UpdateTvShow(tvShow *tmdb.TVShow) error {
+	releaseDate, err := time.Parse("2006-01-02", tvShow.ReleaseDate)
+	if err != nil {
+		r.logger().Warn("error parsing release date", "tv_show_id", tvShow.ID, "error", err)
+		releaseDate = time.Unix(0, 0)
+	}
+	tvShowEntity := &repository.TvShow{ID: tvShow.ID}
+	err = r.db.Model(tvShowEntity).Updates(map[string]any{
+		"name":         tvShow.Title,
+		"release_date": releaseDate,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Model(tvShowEntity).Association("Categories").Replace(*r.extractCategories(&tvShow.Genres))
+}
+
+// UpdateEpisode overwrites the metadata of a stored episode (name, season, number and air date), keeping its file
+func (r *MediaRepository) UpdateEpisode(episode *tmdb.TVEpisode) error {
+	releaseDate, err := time.Parse("2006-01-02", episode.AirDate)
+	if err != nil {
+		r.logger().Warn("error parsing release date", "episode_id", episode.ID, "error", err)
+		releaseDate = time.Unix(0, 0)
+	}
+	return r.db.Model(&repository.Episode{ID: episode.ID}).Updates(map[string]any{
+		"name":         episode.Name,
+		"nb_season":    episode.SeasonNumber,
+		"nb_episode":   episode.EpisodeNumber,
+		"release_date": releaseDate,
+	}).Error
+}
+
+// GetLibraryMovies returns all the movies with their categories and files, only the ones with a file if availableOnly is set
+func (r *MediaRepository) GetLibraryMovies(availableOnly bool) ([]repository.Movie, error) {
+	var movies []repository.Movie
+	query := r.db.
+		Preload("Categories").
+		Preload("MediaFile.Audios").
+		Preload("MediaFile.Subtitles").
+		Order("id")
+	if availableOnly {
+		query = query.Where("media_file_id IS NOT NULL")
+	}
+	if err := query.Find(&movies).Error; err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// GetLibraryTvShows returns all the tv shows with their categories and episodes files,
+// only the ones with at least one episode file if availableOnly is set
+func (r *MediaRepository) GetLibraryTvShows(availableOnly bool) ([]repository.TvShow, error) {
+	var tvShows []repository.TvShow
+	episodes := func(db *gorm.DB) *gorm.DB {
+		if availableOnly {
+			db = db.Where("media_file_id IS NOT NULL")
+		}
+		return db.Order("nb_season, nb_episode")
+	}
+	query := r.db.
+		Preload("Categories").
+		Preload("Episodes", episodes).
+		Order("id")
+	if availableOnly {
+		query = query.Where("EXISTS (SELECT 1 FROM episodes WHERE episodes.tv_show_id = tv_shows.id AND episodes.media_file_id IS NOT NULL)")
+	}
+	err := query.
+		Preload("Episodes.MediaFile.Audios").
+		Preload("Episodes.MediaFile.Subtitles").
+		Find(&tvShows).Error
+	if err != nil {
+		return nil, err
+	}
+	return tvShows, nil
+}
+
+// GetMovieFileIDs returns the media file ID of every movie having a file, by movie ID (TMDB ID)
+func (r *MediaRepository) GetMovieFileIDs() (map[int]string, error) {
+	var rows []struct {
+		ID          int
+		MediaFileID string
+	}
+	result := r.db.Model(&repository.Movie{}).
+		Select("id, media_file_id").
+		Where("media_file_id IS NOT NULL").
+		Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	fileIDs := make(map[int]string, len(rows))
+	for _, row := range rows {
+		fileIDs[row.ID] = row.MediaFileID
+	}
+	return fileIDs, nil
+}
+
+// GetEpisodeFileIDs returns the media file ID of every episode having a file, by episode ID (TMDB ID)
+func (r *MediaRepository) GetEpisodeFileIDs() (map[int]string, error) {
+	var rows []struct {
+		ID          int
+		MediaFileID string
+	}
+	result := r.db.Model(&repository.Episode{}).
+		Select("id, media_file_id").
+		Where("media_file_id IS NOT NULL").
+		Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	fileIDs := make(map[int]string, len(rows))
+	for _, row := range rows {
+		fileIDs[row.ID] = row.MediaFileID
+	}
+	return fileIDs, nil
+}
+
+// ReindexSearch rebuilds the indexes of the searchable tables and refreshes their planner statistics.
+// The indexes are rebuilt concurrently so that the tables stay writable, which cannot run within a transaction.
+func (r *MediaRepository) ReindexSearch() error {
+	for _, table := range []string{"movies", "tv_shows", "episodes", "categories"} {
+		if err := r.db.Exec("REINDEX TABLE CONCURRENTLY " + table).Error; err != nil {
+			return err
+		}
+		if err := r.db.Exec("ANALYZE " + table).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMediaNames returns the ID and name of every movie, tv show and episode stored in the database
+func (r *MediaRepository) GetMediaNames() ([]SavedMedia, error) {
+	var media []SavedMedia
+	for _, query := range []struct {
+		mediaType SavedMediaType
+		model     any
+		columns   string
+	}{
+		{SavedMovie, &repository.Movie{}, "id, name"},
+		{SavedTvShow, &repository.TvShow{}, "id, name"},
+		{SavedEpisode, &repository.Episode{}, "id, name, tv_show_id"},
+	} {
+		var rows []SavedMedia
+		result := r.db.Model(query.model).
+			Select(query.columns).
+			Find(&rows)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		for i := range rows {
+			rows[i].Type = query.mediaType
+		}
+		media = append(media, rows...)
+	}
+	return media, nil
+}
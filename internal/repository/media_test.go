@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetCommentDaysTimeZone(t *testing.T) {
+	r := newTestRepository(t)
+	user := "1f2a3b4c-5d6e-4f7a-9b8c-0d1e2f3a4b5c"
+	mustExec(t, r, "INSERT INTO movies (id, name) VALUES (-1, 'Alpha')")
+	// late on May 6 in New York, but May 7 in UTC
+	mustExec(t, r, "INSERT INTO movie_comments (user_id, movie_id, content, created_at) VALUES (?, -1, 'late', '2023-05-07 02:00:00+00')", user)
+	mustExec(t, r, "SET LOCAL TIME ZONE 'America/New_York'")
+
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	days, err := r.GetCommentDays(user, start, start.AddDate(0, 0, 9))
+	if err != nil {
+		t.Fatalf("GetCommentDays() error = %v", err)
+	}
+	if len(days) != 1 || days[0].Day != "2023-05-07" || !days[0].CreatedAt.Equal(time.Date(2023, 5, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetCommentDays() = %+v, want one comment on 2023-05-07", days)
+	}
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MovieProgress is the playback position of a user in a movie, in seconds
+type MovieProgress struct {
+	UserID    string           `gorm:"type:uuid;primaryKey"`
+	MovieID   int              `gorm:"primaryKey"`
+	Movie     repository.Movie `gorm:"reference:MovieID"`
+	Position  float64
+	Watched   bool
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (MovieProgress) TableName() string {
+	return "movie_progress"
+}
+
+// EpisodeProgress is the playback position of a user in an episode, in seconds
+type EpisodeProgress struct {
+	UserID    string             `gorm:"type:uuid;primaryKey"`
+	EpisodeID int                `gorm:"primaryKey"`
+	Episode   repository.Episode `gorm:"reference:EpisodeID"`
+	TvShowID  int
+	Position  float64
+	Watched   bool
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (EpisodeProgress) TableName() string {
+	return "episode_progress"
+}
+
+// progressUpsert updates the position of an existing progress, an item once watched staying watched
+func progressUpsert(table string, key string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: key}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "position"}, Value: gorm.Expr("excluded.position")},
+			{Column: clause.Column{Name: "watched"}, Value: gorm.Expr(table + ".watched OR excluded.watched")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("excluded.updated_at")},
+		},
+	}
+}
+
+// SaveMovieProgress saves the position of a user in a movie
+func (r *MediaRepository) SaveMovieProgress(userID string, movieID int, position float64, watched bool) (*MovieProgress, error) {
+	progress := &MovieProgress{UserID: userID, MovieID: movieID, Position: position, Watched: watched}
+	result := r.db.Omit("Movie").Clauses(progressUpsert("movie_progress", "movie_id")).Create(progress)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.GetMovieProgress(userID, movieID)
+}
+
+// SaveEpisodeProgress saves the position of a user in an episode of a tv show
+func (r *MediaRepository) SaveEpisodeProgress(userID string, episodeID, tvShowID int, position float64, watched bool) (*EpisodeProgress, error) {
+	progress := &EpisodeProgress{UserID: userID, EpisodeID: episodeID, TvShowID: tvShowID, Position: position, Watched: watched}
+	result := r.db.Omit("Episode").Clauses(progressUpsert("episode_progress", "episode_id")).Create(progress)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.GetEpisodeProgress(userID, episodeID)
+}
+
+// GetMovieProgress returns the position of a user in a movie
+func (r *MediaRepository) GetMovieProgress(userID string, movieID int) (*MovieProgress, error) {
+	var progress MovieProgress
+	result := r.db.
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		First(&progress)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &progress, nil
+}
+
+// GetEpisodeProgress returns the position of a user in an episode
+func (r *MediaRepository) GetEpisodeProgress(userID string, episodeID int) (*EpisodeProgress, error) {
+	var progress EpisodeProgress
+	result := r.db.
+		Where("user_id = ? AND episode_id = ?", userID, episodeID).
+		First(&progress)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &progress, nil
+}
+
+// GetUnfinishedMovies returns the movies a user started without watching them, the most recently played first.
+// Only the movies still having a file are returned.
+func (r *MediaRepository) GetUnfinishedMovies(userID string, limit int) ([]*MovieProgress, error) {
+	var progresses []*MovieProgress
+	result := r.db.
+		Preload("Movie.MediaFile").
+		Where("user_id = ? AND NOT watched AND position > 0", userID).
+		Where("movie_id IN (SELECT movies.id FROM movies WHERE " + availableFile("movies.media_file_id") + ")").
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&progresses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return progresses, nil
+}
+
+// GetLastEpisodeProgresses returns the most recently played episode of each tv show a user started,
+// the most recently played first
+func (r *MediaRepository) GetLastEpisodeProgresses(userID string, limit int) ([]*EpisodeProgress, error) {
+	var progresses []*EpisodeProgress
+	last := r.db.
+		Table("episode_progress").
+		Select("DISTINCT ON (tv_show_id) *").
+		Where("user_id = ?", userID).
+		Order("tv_show_id, updated_at DESC")
+	result := r.db.
+		Table("(?) AS episode_progress", last).
+		Preload("Episode.TvShow").
+		Preload("Episode.MediaFile").
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&progresses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return progresses, nil
+}
+
+// GetTvShowProgresses returns the positions of a user in the episodes of a tv show
+func (r *MediaRepository) GetTvShowProgresses(userID string, tvShowID int) ([]*EpisodeProgress, error) {
+	var progresses []*EpisodeProgress
+	result := r.db.
+		Preload("Episode").
+		Where("user_id = ? AND tv_show_id = ?", userID, tvShowID).
+		Find(&progresses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return progresses, nil
+}
+
+// GetNextEpisodes returns the episode following each of the played episodes in its tv show: the first episode having
+// a file after it, by season then number, which the user did not watch. The returned progresses have the position
+// of the user in these episodes, 0 when they were never played, and the episodes with their tv show and file.
+// A tv show without such an episode is left out.
+func (r *MediaRepository) GetNextEpisodes(userID string, playedEpisodeIDs []int) ([]*EpisodeProgress, error) {
+	var progresses []*EpisodeProgress
+	if len(playedEpisodeIDs) == 0 {
+		return progresses, nil
+	}
+	result := r.db.
+		Table("episodes").
+		Select("DISTINCT ON (episodes.tv_show_id) ? AS user_id, episodes.id AS episode_id, episodes.tv_show_id, "+
+			"COALESCE(episode_progress.position, 0) AS position", userID).
+		Joins("JOIN episodes AS played ON played.tv_show_id = episodes.tv_show_id").
+		Joins("LEFT JOIN episode_progress ON episode_progress.episode_id = episodes.id AND episode_progress.user_id = ?", userID).
+		Where("played.id IN ? AND "+availableFile("episodes.media_file_id"), playedEpisodeIDs).
+		Where("(episodes.nb_season, episodes.nb_episode) > (played.nb_season, played.nb_episode)").
+		Where("episode_progress.watched IS NOT TRUE").
+		Order("episodes.tv_show_id, episodes.nb_season, episodes.nb_episode").
+		Scan(&progresses)
+	if result.Error != nil || len(progresses) == 0 {
+		return progresses, result.Error
+	}
+
+	episodeIDs := make([]int, len(progresses))
+	for i, progress := range progresses {
+		episodeIDs[i] = progress.EpisodeID
+	}
+	var episodes []repository.Episode
+	result = r.db.
+		Preload("TvShow").
+		Preload("MediaFile").
+		Where("id IN ?", episodeIDs).
+		Find(&episodes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	byID := make(map[int]repository.Episode, len(episodes))
+	for _, episode := range episodes {
+		byID[episode.ID] = episode
+	}
+	for _, progress := range progresses {
+		progress.Episode = byID[progress.EpisodeID]
+	}
+	return progresses, nil
+}
+
+// GetAvailableTvShowEpisodes returns the episodes of a tv show having a file, ordered by season and number
+func (r *MediaRepository) GetAvailableTvShowEpisodes(tvShowID int) ([]*repository.Episode, error) {
+	var episodes []*repository.Episode
+	result := r.db.
+		Preload("TvShow").
+		Preload("MediaFile").
+		Where("episodes.tv_show_id = ? AND "+availableFile("episodes.media_file_id"), tvShowID).
+		Order("nb_season, nb_episode").
+		Find(&episodes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return episodes, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+)
+
+// UserRating is the rating of a movie or a tv show by a user, Type is SavedMovie or SavedTvShow
+type UserRating struct {
+	UserID  string
+	Type    SavedMediaType
+	MediaID int
+	Rating  int
+}
+
+// GetAllRatings returns every movie and tv show rating
+func (r *MediaRepository) GetAllRatings() ([]UserRating, error) {
+	return r.getRatings("")
+}
+
+// GetUserRatings returns the movie and tv show ratings of a user
+func (r *MediaRepository) GetUserRatings(userID string) ([]UserRating, error) {
+	return r.getRatings(userID)
+}
+
+// getRatings returns the movie and tv show ratings of a user, of every user if userID is empty
+func (r *MediaRepository) getRatings(userID string) ([]UserRating, error) {
+	var ratings []UserRating
+	movies := r.db.Model(&repository.MovieRating{}).
+		Select("user_id, CAST(? AS text) AS type, movie_id AS media_id, rating", SavedMovie)
+	tvShows := r.db.Model(&repository.TvShowRating{}).
+		Select("user_id, CAST(? AS text) AS type, tv_show_id AS media_id, rating", SavedTvShow)
+	if userID != "" {
+		movies = movies.Where("user_id = ?", userID)
+		tvShows = tvShows.Where("user_id = ?", userID)
+	}
+	result := r.db.Raw("? UNION ALL ?", movies, tvShows).Scan(&ratings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ratings, nil
+}
+
+// GetAvailableTitles returns the ID and name of the movies having a file and of the tv shows having an episode file
+func (r *MediaRepository) GetAvailableTitles() ([]SavedMedia, error) {
+	return r.getTitles(availableFile("movies.media_file_id"),
+		"EXISTS (SELECT 1 FROM episodes WHERE episodes.tv_show_id = tv_shows.id AND "+availableFile("episodes.media_file_id")+")")
+}
+
+// GetRatedTitles returns the ID and name of the movies and tv shows having a rating, available or not
+func (r *MediaRepository) GetRatedTitles() ([]SavedMedia, error) {
+	return r.getTitles("EXISTS (SELECT 1 FROM movie_ratings WHERE movie_ratings.movie_id = movies.id)",
+		"EXISTS (SELECT 1 FROM tv_show_ratings WHERE tv_show_ratings.tv_show_id = tv_shows.id)")
+}
+
+// getTitles returns the ID and name of the movies matching movieCondition and of the tv shows matching tvShowCondition
+func (r *MediaRepository) getTitles(movieCondition, tvShowCondition string) ([]SavedMedia, error) {
+	var movies, tvShows []SavedMedia
+	result := r.db.Model(&repository.Movie{}).
+		Select("id, name").
+		Where(movieCondition).
+		Find(&movies)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	result = r.db.Model(&repository.TvShow{}).
+		Select("id, name").
+		Where(tvShowCondition).
+		Find(&tvShows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range movies {
+		movies[i].Type = SavedMovie
+	}
+	for i := range tvShows {
+		tvShows[i].Type = SavedTvShow
+	}
+	return append(movies, tvShows...), nil
+}
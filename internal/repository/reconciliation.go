@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+	"time"
+)
+
+type StoredFileKind string
+
+const (
+	StoredVideo    StoredFileKind = "video"
+	StoredAudio    StoredFileKind = "audio"
+	StoredSubtitle StoredFileKind = "subtitle"
+)
+
+// StoredFile is a file expected in the storage directory of a media: the video playlist of a media file,
+// or the playlist of one of its audios or one of its subtitles. ID is the ID of the row referencing it,
+// CreatedAt when the row was saved.
+type StoredFile struct {
+	Kind        StoredFileKind
+	ID          string
+	MediaFileID string
+	Filename    string
+	CreatedAt   time.Time
+}
+
+// GetStoredFiles returns the video, audio and subtitle files of every media file, by media file ID
+func (r *MediaRepository) GetStoredFiles() (map[string][]StoredFile, error) {
+	files := make(map[string][]StoredFile)
+	for _, table := range []struct {
+		model  any
+		kind   StoredFileKind
+		fileID string
+	}{
+		{&repository.MediaFile{}, StoredVideo, "id"},
+		{&repository.Audio{}, StoredAudio, "media_file_id"},
+		{&repository.Subtitle{}, StoredSubtitle, "media_file_id"},
+	} {
+		var rows []StoredFile
+		result := r.db.Model(table.model).
+			Select("id, " + table.fileID + " AS media_file_id, filename, created_at").
+			Find(&rows)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		for _, row := range rows {
+			row.Kind = table.kind
+			files[row.MediaFileID] = append(files[row.MediaFileID], row)
+		}
+	}
+	return files, nil
+}
+
+// DeleteStoredFile deletes the row referencing a stored file, the whole media file for a video
+func (r *MediaRepository) DeleteStoredFile(file StoredFile) error {
+	switch file.Kind {
+	case StoredAudio:
+		return r.db.Delete(&repository.Audio{}, "id = ?", file.ID).Error
+	case StoredSubtitle:
+		return r.db.Delete(&repository.Subtitle{}, "id = ?", file.ID).Error
+	default:
+		return r.DeleteMediaFile(file.MediaFileID)
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RetentionFile is the media file of a movie or an episode with what the retention policy needs to judge it,
+// Type is SavedMovie or SavedEpisode. Name is the name of the movie or of the tv show of the episode, Rating the
+// average rating of the movie or of the tv show. LastViewed is the last day it was watched, nil if it never was.
+type RetentionFile struct {
+	MediaFileID string
+	Type        SavedMediaType
+	MediaID     int
+	TvShowID    int
+	Name        string
+	Season      int
+	Episode     int
+	Size        int64
+	AddedAt     time.Time
+	LastViewed  *time.Time
+	Rating      float64
+	Ratings     int
+}
+
+// viewLogMigration is the version of the migration creating the view logs, 0007_view_log
+const viewLogMigration = 7
+
+// GetViewTrackingStart returns when the views started being logged, the time the view log migration was applied,
+// nil if it was not recorded
+func (r *MediaRepository) GetViewTrackingStart() (*time.Time, error) {
+	var appliedAt []time.Time
+	result := r.db.Table("schema_migrations").Where("version = ?", viewLogMigration).Pluck("applied_at", &appliedAt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(appliedAt) == 0 {
+		return nil, nil
+	}
+	return &appliedAt[0], nil
+}
+
+// GetRetentionFiles returns the files of every movie and episode
+func (r *MediaRepository) GetRetentionFiles() ([]RetentionFile, error) {
+	var files []RetentionFile
+	result := r.db.Table("(?) AS uses", r.mediaFileUses()).
+		Joins("JOIN media_files ON media_files.id = uses.media_file_id").
+		Select("uses.*, media_files.size, media_files.created_at AS added_at, "+
+			"CASE WHEN uses.type = @movie "+
+			"THEN (SELECT MAX(day) FROM movie_view_log WHERE movie_view_log.movie_id = uses.media_id) "+
+			"ELSE (SELECT MAX(day) FROM episode_view_log WHERE episode_view_log.episode_id = uses.media_id) END AS last_viewed, "+
+			"CASE WHEN uses.type = @movie "+
+			"THEN (SELECT COALESCE(AVG(rating), 0) FROM movie_ratings WHERE movie_ratings.movie_id = uses.media_id) "+
+			"ELSE (SELECT COALESCE(AVG(rating), 0) FROM tv_show_ratings WHERE tv_show_ratings.tv_show_id = uses.tv_show_id) END AS rating, "+
+			"CASE WHEN uses.type = @movie "+
+			"THEN (SELECT COUNT(*) FROM movie_ratings WHERE movie_ratings.movie_id = uses.media_id) "+
+			"ELSE (SELECT COUNT(*) FROM tv_show_ratings WHERE tv_show_ratings.tv_show_id = uses.tv_show_id) END AS ratings",
+			sql.Named("movie", SavedMovie)).
+		Order("type, tv_show_id, season, episode, media_id").
+		Scan(&files)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return files, nil
+}
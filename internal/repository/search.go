@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// textSearch matches the name of a table against a user query, using the search_vector column
+// (every word of the query as a prefix, in any order) or, for typos, the trigram word similarity.
+// Both ignore accents and case, and are backed by the indexes of the full_text_search migration.
+type textSearch struct {
+	query   string
+	tsQuery string
+}
+
+func newTextSearch(query string) textSearch {
+	return textSearch{
+		query:   strings.ToLower(strings.TrimSpace(query)),
+		tsQuery: prefixTsQuery(query),
+	}
+}
+
+// condition returns the WHERE condition matching the name of table, and its arguments.
+// An empty query matches every row.
+func (s textSearch) condition(table string) (string, []any) {
+	if s.query == "" {
+		return "TRUE", nil
+	}
+	similar := fmt.Sprintf("immutable_unaccent(?) <%% immutable_unaccent(lower(%s.name))", table)
+	if s.tsQuery == "" {
+		return similar, []any{s.query}
+	}
+	return fmt.Sprintf("(%s.search_vector @@ to_tsquery('simple', immutable_unaccent(?)) OR %s)", table, similar),
+		[]any{s.tsQuery, s.query}
+}
+
+// rank returns the relevance of the name of table for the query, higher being better, and its arguments
+func (s textSearch) rank(table string) (string, []any) {
+	if s.query == "" {
+		return "0", nil
+	}
+	similarity := fmt.Sprintf("word_similarity(immutable_unaccent(?), immutable_unaccent(lower(%s.name)))", table)
+	if s.tsQuery == "" {
+		return similarity, []any{s.query}
+	}
+	return fmt.Sprintf("(ts_rank(%s.search_vector, to_tsquery('simple', immutable_unaccent(?))) + %s)", table, similarity),
+		[]any{s.tsQuery, s.query}
+}
+
+// orderBy returns the ORDER BY clause sorting the rows by decreasing rank then by the given columns
+func (s textSearch) orderBy(table string, then string) clause.OrderBy {
+	rank, args := s.rank(table)
+	return clause.OrderBy{Expression: clause.Expr{SQL: rank + " DESC, " + then, Vars: args, WithoutParentheses: true}}
+}
+
+// prefixTsQuery turns a user query into a tsquery matching all its words as prefixes,
+// e.g. "Star wa" into "star:* & wa:*". Characters other than letters and digits separate words.
+func prefixTsQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// fileKey is the position of a file search result in the relevance order
+type fileKey struct {
+	ID         int
+	CreatedAt  time.Time
+	SearchRank float64
+}
+
+// searchFileKeys returns the keys of the rows of page of query, with one more row, ordered by decreasing rank
+// then creation date and ID, and the total number of rows. The rows are loaded afterwards by their IDs.
+func (r *MediaRepository) searchFileKeys(query *gorm.DB, table, rank string, rankArgs []any, page Page) ([]fileKey, int, error) {
+	var count int64
+	query = query.Count(&count)
+	if cursor := page.Cursor; cursor != nil {
+		id, err := strconv.Atoi(cursor.ID)
+		if err != nil {
+			return nil, 0, ErrInvalidCursor
+		}
+		query = query.Where(
+			fmt.Sprintf("(%s, %s.created_at, %[2]s.id) < (?, ?, ?)", rank, table),
+			append(rankArgs, cursor.Rank, cursor.CreatedAt, id)...,
+		)
+	}
+	var keys []fileKey
+	result := query.
+		Clauses(clause.Select{Expression: clause.Expr{
+			SQL:  fmt.Sprintf("%s.id, %[1]s.created_at, %s AS search_rank", table, rank),
+			Vars: rankArgs,
+		}}).
+		Order("search_rank DESC").
+		Order(table + ".created_at DESC").
+		Order(table + ".id DESC").
+		Offset(page.offset()).
+		Limit(page.Limit + 1).
+		Scan(&keys)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return keys, int(count), nil
+}
+
+func fileKeyIDs(keys []fileKey) []int {
+	ids := make([]int, len(keys))
+	for i, key := range keys {
+		ids[i] = key.ID
+	}
+	return ids
+}
+
+// newFileSearchPage orders the rows loaded for keys and returns their page
+func newFileSearchPage[T any](keys []fileKey, rows []T, total, limit int, idOf func(T) int) *CursorPage[T] {
+	byID := make(map[int]T, len(rows))
+	for _, row := range rows {
+		byID[idOf(row)] = row
+	}
+	positions := make(map[int]fileKey, len(keys))
+	ordered := make([]T, 0, len(keys))
+	for _, key := range keys {
+		if row, ok := byID[key.ID]; ok {
+			ordered = append(ordered, row)
+			positions[key.ID] = key
+		}
+	}
+	return newCursorPage(ordered, total, limit, func(row T) Cursor {
+		key := positions[idOf(row)]
+		return Cursor{Rank: key.SearchRank, CreatedAt: key.CreatedAt, ID: strconv.Itoa(key.ID)}
+	})
+}
+
+// LibraryMatch is an available movie, tv show or episode matching a library search. TvShowID, Season and Episode
+// are only set for the episodes.
+type LibraryMatch struct {
+	Type       SavedMediaType
+	ID         int
+	TvShowID   int
+	Season     int
+	Episode    int
+	SearchRank float64
+}
+
+// SearchLibrary returns the page-th page of limit available movies, tv shows and episodes matching query, ranked
+// together by relevance then by decreasing creation date, and the number of matches of each type. Only the given
+// types are searched, all of them if it is empty, and the media files must have the given languages.
+func (r *MediaRepository) SearchLibrary(query string, languages LanguageFilter, types []SavedMediaType, page, limit int) ([]LibraryMatch, map[SavedMediaType]int, error) {
+	search := newTextSearch(query)
+	searched := func(mediaType SavedMediaType) bool {
+		return len(types) == 0 || slices.Contains(types, mediaType)
+	}
+	var parts []any
+	if searched(SavedMovie) {
+		condition, args := search.condition("movies")
+		rank, rankArgs := search.rank("movies")
+		languageCondition, languageArgs := languages.condition("movies.media_file_id")
+		parts = append(parts, r.db.Table("movies").
+			Select("?::text AS type, movies.id, 0 AS tv_show_id, 0 AS season, 0 AS episode, "+rank+" AS search_rank, movies.created_at",
+				append([]any{SavedMovie}, rankArgs...)...).
+			Where(availableFile("movies.media_file_id")).
+			Where(condition, args...).
+			Where(languageCondition, languageArgs...))
+	}
+	if searched(SavedTvShow) {
+		condition, args := search.condition("tv_shows")
+		rank, rankArgs := search.rank("tv_shows")
+		languageCondition, languageArgs := languages.condition("episodes.media_file_id")
+		parts = append(parts, r.db.Table("tv_shows").
+			Select("?::text AS type, tv_shows.id, 0 AS tv_show_id, 0 AS season, 0 AS episode, "+rank+" AS search_rank, tv_shows.created_at",
+				append([]any{SavedTvShow}, rankArgs...)...).
+			Where(condition, args...).
+			Where("EXISTS (SELECT 1 FROM episodes WHERE episodes.tv_show_id = tv_shows.id AND "+availableFile("episodes.media_file_id")+" AND "+languageCondition+")",
+				languageArgs...))
+	}
+	if searched(SavedEpisode) {
+		condition, args := search.condition("episodes")
+		rank, rankArgs := search.rank("episodes")
+		languageCondition, languageArgs := languages.condition("episodes.media_file_id")
+		parts = append(parts, r.db.Table("episodes").
+			Select("?::text AS type, episodes.id, episodes.tv_show_id, episodes.nb_season AS season, episodes.nb_episode AS episode, "+rank+" AS search_rank, episodes.created_at",
+				append([]any{SavedEpisode}, rankArgs...)...).
+			Where(availableFile("episodes.media_file_id")).
+			Where(condition, args...).
+			Where(languageCondition, languageArgs...))
+	}
+	if len(parts) == 0 {
+		return []LibraryMatch{}, map[SavedMediaType]int{}, nil
+	}
+	union := r.db.Raw(strings.Repeat("? UNION ALL ", len(parts)-1)+"?", parts...)
+
+	var counts []struct {
+		Type  SavedMediaType
+		Count int
+	}
+	result := r.db.Table("(?) AS matches", union).
+		Select("type, COUNT(*) AS count").
+		Group("type").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	totals := make(map[SavedMediaType]int, len(counts))
+	for _, count := range counts {
+		totals[count.Type] = count.Count
+	}
+
+	matches := make([]LibraryMatch, 0, limit)
+	result = r.db.Table("(?) AS matches", union).
+		Order("search_rank DESC, created_at DESC, type, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&matches)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	return matches, totals, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefixTsQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"words", "Star wa", "star:* & wa:*"},
+		{"single word", "matrix", "matrix:*"},
+		{"punctuation", "Wall-E: the movie!", "wall:* & e:* & the:* & movie:*"},
+		{"apostrophe", "Amélie's", "amélie:* & s:*"},
+		{"accents kept for unaccent", "Éléphant", "éléphant:*"},
+		{"digits", "2001 a space odyssey", "2001:* & a:* & space:* & odyssey:*"},
+		{"operators", "star & wars | !trek:*(x)", "star:* & wars:* & trek:* & x:*"},
+		{"only operators", "&|!:*()", ""},
+		{"spaces", "  ", ""},
+		{"empty", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := prefixTsQuery(test.query); got != test.want {
+				t.Errorf("prefixTsQuery(%q) = %q, want %q", test.query, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearchLibrary(t *testing.T) {
+	r := newTestRepository(t)
+	file, episodeFile := "8c9d0e1f-2a3b-4c4d-6e5f-7a8b9c0d1e2f", "9d0e1f2a-3b4c-4d5e-7f6a-8b9c0d1e2f3a"
+	mustExec(t, r, "INSERT INTO media_files (id, filename, duration) VALUES (?, 'quest.mp4', 5400), (?, 'returns.mp4', 2500)", file, episodeFile)
+	mustExec(t, r, "INSERT INTO movies (id, name, media_file_id) VALUES (-1, 'Zyxwvu Quest', ?), (-2, 'Zyxwvu Lost', NULL)", file)
+	mustExec(t, r, "INSERT INTO tv_shows (id, name) VALUES (-1, 'Zyxwvu Saga')")
+	mustExec(t, r, "INSERT INTO episodes (id, tv_show_id, name, nb_season, nb_episode, media_file_id) VALUES (-1, -1, 'Zyxwvu Returns', 2, 3, ?)", episodeFile)
+
+	matches, totals, err := r.SearchLibrary("zyxwvu", LanguageFilter{}, nil, 1, 2)
+	if err != nil {
+		t.Fatalf("SearchLibrary() error = %v", err)
+	}
+	if want := map[SavedMediaType]int{SavedMovie: 1, SavedTvShow: 1, SavedEpisode: 1}; !reflect.DeepEqual(totals, want) {
+		t.Errorf("totals = %v, want %v", totals, want)
+	}
+	next, _, err := r.SearchLibrary("zyxwvu", LanguageFilter{}, nil, 2, 2)
+	if err != nil {
+		t.Fatalf("SearchLibrary() error = %v", err)
+	}
+	if len(matches) != 2 || len(next) != 1 {
+		t.Fatalf("pages = %v, %v, want 2 then 1 matches", matches, next)
+	}
+	for _, match := range append(matches, next...) {
+		if match.Type == SavedEpisode && (match.TvShowID != -1 || match.Season != 2 || match.Episode != 3) {
+			t.Errorf("episode = %+v, want episode 3 of season 2 of tv show -1", match)
+		}
+	}
+	if matches[0].SearchRank < matches[1].SearchRank || matches[1].SearchRank < next[0].SearchRank {
+		t.Errorf("matches are not ordered by rank: %v, %v", matches, next)
+	}
+
+	movies, totals, err := r.SearchLibrary("zyxwvu", LanguageFilter{}, []SavedMediaType{SavedMovie}, 1, 20)
+	if err != nil {
+		t.Fatalf("SearchLibrary(movie) error = %v", err)
+	}
+	if len(movies) != 1 || movies[0].ID != -1 || totals[SavedTvShow] != 0 {
+		t.Errorf("SearchLibrary(movie) = %v, %v, want the available movie only", movies, totals)
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+)
+
+// GetSubtitles returns the subtitles of a media file, by filename
+func (r *MediaRepository) GetSubtitles(fileID string) ([]repository.Subtitle, error) {
+	var subtitles []repository.Subtitle
+	result := r.db.
+		Where("media_file_id = ?", fileID).
+		Order("filename").
+		Find(&subtitles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subtitles, nil
+}
+
+// GetSubtitle returns a subtitle of a media file
+func (r *MediaRepository) GetSubtitle(fileID, subtitleID string) (*repository.Subtitle, error) {
+	var subtitle repository.Subtitle
+	result := r.db.
+		Where("id = ? AND media_file_id = ?", subtitleID, fileID).
+		First(&subtitle)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &subtitle, nil
+}
+
+// AddSubtitle adds a subtitle to a media file, setting its ID
+func (r *MediaRepository) AddSubtitle(subtitle *repository.Subtitle) error {
+	return r.db.Create(subtitle).Error
+}
+
+// ReplaceSubtitle points a subtitle to a new file in the given language, marking it updated
+func (r *MediaRepository) ReplaceSubtitle(subtitle *repository.Subtitle, filename, language string) error {
+	return r.db.Model(subtitle).Updates(map[string]any{"filename": filename, "language": language}).Error
+}
+
+// DeleteSubtitle deletes a subtitle given its ID
+func (r *MediaRepository) DeleteSubtitle(subtitleID string) error {
+	return r.db.Delete(&repository.Subtitle{}, "id = ?", subtitleID).Error
+}
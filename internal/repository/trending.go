@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// TrendingActivity counts the comments, ratings and viewers of a movie or a tv show during an hour,
+// Type is SavedMovie or SavedTvShow. Views are logged per day, so their Time is the start of the day.
+type TrendingActivity struct {
+	Type     SavedMediaType
+	MediaID  int
+	Time     time.Time
+	Comments int
+	Ratings  int
+	Viewers  int
+}
+
+// GetTrendingActivity returns the activity on the available movies and tv shows since a given time,
+// ratings counting from their last update and views from the start of the day of since
+func (r *MediaRepository) GetTrendingActivity(since time.Time) ([]TrendingActivity, error) {
+	var activities []TrendingActivity
+	// from is the time of the rows, ? standing for their table, and bucket groups it, ? standing for from
+	activity := func(table string, mediaType SavedMediaType, media, bucket, counts, from string, since time.Time) *gorm.DB {
+		from = strings.ReplaceAll(from, "?", table)
+		bucket = strings.ReplaceAll(bucket, "?", from)
+		query := r.db.Table(table).
+			Select("CAST(? AS text) AS type, "+table+"."+media+" AS media_id, "+bucket+" AS time, "+counts, mediaType).
+			Where(from+" >= ?", since).
+			Group(table + "." + media + ", " + bucket)
+		if mediaType == SavedMovie {
+			return query.Where("EXISTS (SELECT 1 FROM movies WHERE movies.id = " + table + ".movie_id AND " + availableFile("movies.media_file_id") + ")")
+		}
+		return query.Where("EXISTS (SELECT 1 FROM episodes WHERE episodes.tv_show_id = " + table + ".tv_show_id AND " + availableFile("episodes.media_file_id") + ")")
+	}
+	hour := "date_trunc('hour', ?)"
+	day := "?"
+	// the view log days are UTC ones, whatever the time zone of the database session
+	viewDay := "(?.day AT TIME ZONE 'UTC')"
+	comments := "COUNT(*) AS comments, 0 AS ratings, 0 AS viewers"
+	ratings := "0 AS comments, COUNT(*) AS ratings, 0 AS viewers"
+	viewers := "0 AS comments, 0 AS ratings, COUNT(DISTINCT user_id) AS viewers"
+	queries := []*gorm.DB{
+		activity("movie_comments", SavedMovie, "movie_id", hour, comments, "?.created_at", since),
+		activity("movie_ratings", SavedMovie, "movie_id", hour, ratings, "?.updated_at", since),
+		activity("movie_view_log", SavedMovie, "movie_id", day, viewers, viewDay, since.UTC().Truncate(24*time.Hour)),
+		activity("tv_show_comments", SavedTvShow, "tv_show_id", hour, comments, "?.created_at", since),
+		activity("tv_show_ratings", SavedTvShow, "tv_show_id", hour, ratings, "?.updated_at", since),
+		activity("episode_view_log", SavedTvShow, "tv_show_id", day, viewers, viewDay, since.UTC().Truncate(24*time.Hour)),
+	}
+	sql := "?"
+	args := []any{queries[0]}
+	for _, query := range queries[1:] {
+		sql += " UNION ALL ?"
+		args = append(args, query)
+	}
+	result := r.db.Raw(sql, args...).Scan(&activities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return activities, nil
+}
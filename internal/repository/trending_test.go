@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTrendingActivity(t *testing.T) {
+	r := newTestRepository(t)
+	now := time.Now()
+	user := "6d9bd0b2-4b3e-4f0b-9d7e-3c1f4b0c8a11"
+	mustExec(t, r, "INSERT INTO media_files (id, filename) VALUES ('a8f4e2b6-0c3d-4f57-9a8e-1f2b3c4d5e6f', 'movie.mp4'), ('b1c2d3e4-f5a6-4b7c-8d9e-0f1a2b3c4d5e', 'episode.mp4')")
+	mustExec(t, r, "INSERT INTO movies (id, name, media_file_id) VALUES (-1, 'available', 'a8f4e2b6-0c3d-4f57-9a8e-1f2b3c4d5e6f'), (-2, 'unavailable', NULL)")
+	mustExec(t, r, "INSERT INTO tv_shows (id, name) VALUES (-1, 'available')")
+	mustExec(t, r, "INSERT INTO episodes (id, tv_show_id, nb_season, nb_episode, media_file_id) VALUES (-1, -1, 1, 1, 'b1c2d3e4-f5a6-4b7c-8d9e-0f1a2b3c4d5e')")
+	mustExec(t, r, "INSERT INTO movie_comments (user_id, movie_id, content, created_at) VALUES (?, -1, 'first', ?), (?, -1, 'second', ?), (?, -2, 'hidden', ?), (?, -1, 'old', ?)",
+		user, now, user, now, user, now, user, now.Add(-48*time.Hour))
+	mustExec(t, r, "INSERT INTO movie_ratings (user_id, movie_id, rating, created_at, updated_at) VALUES (?, -1, 4, ?, ?)", user, now, now)
+	mustExec(t, r, "INSERT INTO tv_show_comments (user_id, tv_show_id, content, created_at) VALUES (?, -1, 'episode', ?)", user, now)
+	mustExec(t, r, "INSERT INTO episode_view_log (user_id, episode_id, tv_show_id, day, seconds) VALUES (?, -1, -1, ?, 60)", user, now)
+
+	activities, err := r.GetTrendingActivity(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetTrendingActivity() error = %v", err)
+	}
+	totals := make(map[SavedMediaType]map[int]TrendingActivity)
+	for _, activity := range activities {
+		if activity.MediaID >= 0 {
+			// activity of the data already in the database
+			continue
+		}
+		if totals[activity.Type] == nil {
+			totals[activity.Type] = make(map[int]TrendingActivity)
+		}
+		total := totals[activity.Type][activity.MediaID]
+		total.Comments += activity.Comments
+		total.Ratings += activity.Ratings
+		total.Viewers += activity.Viewers
+		totals[activity.Type][activity.MediaID] = total
+	}
+	if movie := totals[SavedMovie][-1]; movie.Comments != 2 || movie.Ratings != 1 || movie.Viewers != 0 {
+		t.Errorf("activity of the available movie = %+v, want 2 comments and 1 rating", movie)
+	}
+	if movie, ok := totals[SavedMovie][-2]; ok {
+		t.Errorf("activity of the unavailable movie = %+v, want none", movie)
+	}
+	if show := totals[SavedTvShow][-1]; show.Comments != 1 || show.Ratings != 0 || show.Viewers != 1 {
+		t.Errorf("activity of the available tv show = %+v, want 1 comment and 1 viewer", show)
+	}
+}
+
+func TestGetTrendingActivityViewDayTimeZone(t *testing.T) {
+	r := newTestRepository(t)
+	user := "2a3b4c5d-6e7f-4a8b-0c9d-1e2f3a4b5c6d"
+	mustExec(t, r, "INSERT INTO media_files (id, filename) VALUES ('c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f', 'episode.mp4')")
+	mustExec(t, r, "INSERT INTO tv_shows (id, name) VALUES (-1, 'available')")
+	mustExec(t, r, "INSERT INTO episodes (id, tv_show_id, nb_season, nb_episode, media_file_id) VALUES (-1, -1, 1, 1, 'c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f')")
+	mustExec(t, r, "INSERT INTO episode_view_log (user_id, episode_id, tv_show_id, day, seconds) VALUES (?, -1, -1, '2023-05-07', 60)", user)
+	// the start of May 7 in this time zone is still May 6 in UTC
+	mustExec(t, r, "SET LOCAL TIME ZONE 'Pacific/Kiritimati'")
+
+	activities, err := r.GetTrendingActivity(time.Date(2023, 5, 7, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetTrendingActivity() error = %v", err)
+	}
+	day := time.Date(2023, 5, 7, 0, 0, 0, 0, time.UTC)
+	for _, activity := range activities {
+		if activity.Type == SavedTvShow && activity.MediaID == -1 {
+			if activity.Viewers != 1 || !activity.Time.Equal(day) {
+				t.Errorf("activity of the tv show = %+v, want 1 viewer on %v", activity, day)
+			}
+			return
+		}
+	}
+	t.Errorf("GetTrendingActivity() = %+v, want the view of the tv show on %v", activities, day)
+}
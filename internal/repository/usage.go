@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+	"gorm.io/gorm"
+	"time"
+)
+
+// StorageUsage is the number of media files of a group and their total size in bytes
+type StorageUsage struct {
+	Files int64
+	Size  int64
+}
+
+// TvShowUsage is the storage used by the episode files of a tv show
+type TvShowUsage struct {
+	TvShowID int
+	Name     string
+	Files    int64
+	Size     int64
+}
+
+// SeasonUsage is the storage used by the episode files of a season of a tv show
+type SeasonUsage struct {
+	TvShowID int
+	Name     string
+	Season   int
+	Files    int64
+	Size     int64
+}
+
+// YearUsage is the storage used by the movie or episode files added during a year, Type is SavedMovie or SavedEpisode
+type YearUsage struct {
+	Year  int
+	Type  SavedMediaType
+	Files int64
+	Size  int64
+}
+
+// FileUsage is the size of the media file of a movie or an episode, Type is SavedMovie or SavedEpisode.
+// Name is the name of the movie or of the tv show of the episode.
+type FileUsage struct {
+	MediaFileID string
+	Type        SavedMediaType
+	MediaID     int
+	TvShowID    int
+	Name        string
+	Season      int
+	Episode     int
+	Size        int64
+	CreatedAt   time.Time
+}
+
+// GetMoviesUsage returns the storage used by the movie files
+func (r *MediaRepository) GetMoviesUsage() (StorageUsage, error) {
+	var usage StorageUsage
+	result := r.db.Model(&repository.Movie{}).
+		Joins("JOIN media_files ON media_files.id = movies.media_file_id").
+		Select("COUNT(*) AS files, COALESCE(SUM(media_files.size), 0) AS size").
+		Scan(&usage)
+	return usage, result.Error
+}
+
+// GetEpisodesUsage returns the storage used by the episode files
+func (r *MediaRepository) GetEpisodesUsage() (StorageUsage, error) {
+	var usage StorageUsage
+	result := r.db.Model(&repository.Episode{}).
+		Joins("JOIN media_files ON media_files.id = episodes.media_file_id").
+		Select("COUNT(*) AS files, COALESCE(SUM(media_files.size), 0) AS size").
+		Scan(&usage)
+	return usage, result.Error
+}
+
+// GetTvShowsUsage returns the tv shows using the most storage, the largest first
+func (r *MediaRepository) GetTvShowsUsage(limit int) ([]TvShowUsage, error) {
+	var usages []TvShowUsage
+	result := r.db.Model(&repository.Episode{}).
+		Joins("JOIN media_files ON media_files.id = episodes.media_file_id").
+		Joins("JOIN tv_shows ON tv_shows.id = episodes.tv_show_id").
+		Select("tv_shows.id AS tv_show_id, tv_shows.name, COUNT(*) AS files, SUM(media_files.size) AS size").
+		Group("tv_shows.id, tv_shows.name").
+		Order("size DESC, tv_shows.id").
+		Limit(limit).
+		Scan(&usages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return usages, nil
+}
+
+// GetSeasonsUsage returns the seasons using the most storage, the largest first
+func (r *MediaRepository) GetSeasonsUsage(limit int) ([]SeasonUsage, error) {
+	var usages []SeasonUsage
+	result := r.db.Model(&repository.Episode{}).
+		Joins("JOIN media_files ON media_files.id = episodes.media_file_id").
+		Joins("JOIN tv_shows ON tv_shows.id = episodes.tv_show_id").
+		Select("tv_shows.id AS tv_show_id, tv_shows.name, episodes.nb_season AS season, COUNT(*) AS files, SUM(media_files.size) AS size").
+		Group("tv_shows.id, tv_shows.name, episodes.nb_season").
+		Order("size DESC, tv_shows.id, season").
+		Limit(limit).
+		Scan(&usages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return usages, nil
+}
+
+// GetYearlyUsage returns the storage used by the movie and episode files by year added, the latest first
+func (r *MediaRepository) GetYearlyUsage() ([]YearUsage, error) {
+	var usages []YearUsage
+	byYear := func(model any, table string, mediaType SavedMediaType) *gorm.DB {
+		return r.db.Model(model).
+			Joins("JOIN media_files ON media_files.id = "+table+".media_file_id").
+			Select("CAST(EXTRACT(YEAR FROM media_files.created_at) AS integer) AS year, CAST(? AS text) AS type, "+
+				"COUNT(*) AS files, SUM(media_files.size) AS size", mediaType).
+			Group("year")
+	}
+	result := r.db.Raw("? UNION ALL ? ORDER BY year DESC, type",
+		byYear(&repository.Movie{}, "movies", SavedMovie),
+		byYear(&repository.Episode{}, "episodes", SavedEpisode),
+	).Scan(&usages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return usages, nil
+}
+
+// GetLargestFiles returns the largest movie and episode files
+func (r *MediaRepository) GetLargestFiles(limit int) ([]FileUsage, error) {
+	var files []FileUsage
+	result := r.db.Table("(?) AS uses", r.mediaFileUses()).
+		Joins("JOIN media_files ON media_files.id = uses.media_file_id").
+		Select("uses.*, media_files.size, media_files.created_at").
+		Order("size DESC, media_file_id").
+		Limit(limit).
+		Scan(&files)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return files, nil
+}
+
+// mediaFileUses returns the subquery of the movies and episodes having a media file, one row per media with its
+// media_file_id, its type (SavedMovie or SavedEpisode), media_id, tv_show_id, the name of the movie or of the
+// tv show, season and episode, the tv show, season and episode being 0 for a movie
+func (r *MediaRepository) mediaFileUses() *gorm.DB {
+	movies := r.db.Model(&repository.Movie{}).
+		Select("movies.media_file_id, CAST(? AS text) AS type, movies.id AS media_id, 0 AS tv_show_id, "+
+			"movies.name, 0 AS season, 0 AS episode", SavedMovie).
+		Where("movies.media_file_id IS NOT NULL")
+	episodes := r.db.Model(&repository.Episode{}).
+		Joins("JOIN tv_shows ON tv_shows.id = episodes.tv_show_id").
+		Select("episodes.media_file_id, CAST(? AS text) AS type, episodes.id AS media_id, tv_shows.id AS tv_show_id, "+
+			"tv_shows.name, episodes.nb_season AS season, episodes.nb_episode AS episode", SavedEpisode).
+		Where("episodes.media_file_id IS NOT NULL")
+	return r.db.Raw("? UNION ALL ?", movies, episodes)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MovieView is the time a user spent watching a movie during a day, in seconds
+type MovieView struct {
+	UserID   string    `gorm:"type:uuid;primaryKey"`
+	MovieID  int       `gorm:"primaryKey"`
+	Day      time.Time `gorm:"type:date;primaryKey"`
+	Seconds  float64
+	Finished bool
+}
+
+func (MovieView) TableName() string {
+	return "movie_view_log"
+}
+
+// EpisodeView is the time a user spent watching an episode during a day, in seconds
+type EpisodeView struct {
+	UserID    string `gorm:"type:uuid;primaryKey"`
+	EpisodeID int    `gorm:"primaryKey"`
+	TvShowID  int
+	Day       time.Time `gorm:"type:date;primaryKey"`
+	Seconds   float64
+	Finished  bool
+}
+
+func (EpisodeView) TableName() string {
+	return "episode_view_log"
+}
+
+// ViewingTotals sums the views of a user over a period
+type ViewingTotals struct {
+	Seconds          float64
+	MoviesFinished   int
+	EpisodesFinished int
+	TvShowsWatched   int
+}
+
+// ViewingDay is the time a user spent watching during a day, in seconds
+type ViewingDay struct {
+	Day     time.Time
+	Seconds float64
+}
+
+// GenreViewing is the time a user spent watching the titles of a genre, and their number
+type GenreViewing struct {
+	Name    string
+	Seconds float64
+	Titles  int
+}
+
+// TvShowViewing is the time a user spent watching a tv show, and the number of its episodes finished
+type TvShowViewing struct {
+	TvShowID int
+	Name     string
+	Seconds  float64
+	Episodes int
+}
+
+// viewUpsert adds the seconds of a view to the ones already logged for the day
+func viewUpsert(table string, key string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}, {Name: key}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "seconds"}, Value: gorm.Expr(table + ".seconds + excluded.seconds")},
+			{Column: clause.Column{Name: "finished"}, Value: gorm.Expr(table + ".finished OR excluded.finished")},
+		},
+	}
+}
+
+// LogMovieView adds seconds to the time a user spent watching a movie during a day
+func (r *MediaRepository) LogMovieView(view *MovieView) error {
+	return r.db.Clauses(viewUpsert("movie_view_log", "movie_id")).Create(view).Error
+}
+
+// LogEpisodeView adds seconds to the time a user spent watching an episode during a day
+func (r *MediaRepository) LogEpisodeView(view *EpisodeView) error {
+	return r.db.Clauses(viewUpsert("episode_view_log", "episode_id")).Create(view).Error
+}
+
+// GetUserViewingTotals sums the views of a user between two days, both included
+func (r *MediaRepository) GetUserViewingTotals(userID string, start, end time.Time) (*ViewingTotals, error) {
+	var totals ViewingTotals
+	result := r.db.
+		Table("(?) AS views", r.userViews(userID, start, end)).
+		Select(`COALESCE(SUM(seconds), 0) AS seconds,
+			COUNT(DISTINCT movie_id) FILTER (WHERE finished) AS movies_finished,
+			COUNT(DISTINCT episode_id) FILTER (WHERE finished) AS episodes_finished,
+			COUNT(DISTINCT tv_show_id) AS tv_shows_watched`).
+		Scan(&totals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &totals, nil
+}
+
+// GetUserViewingDays returns the time a user spent watching each day between two days, both included,
+// the days without views being left out
+func (r *MediaRepository) GetUserViewingDays(userID string, start, end time.Time) ([]ViewingDay, error) {
+	var days []ViewingDay
+	result := r.db.
+		Table("(?) AS views", r.userViews(userID, start, end)).
+		Select("day, SUM(seconds) AS seconds").
+		Group("day").
+		Order("day").
+		Scan(&days)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return days, nil
+}
+
+// GetUserTopGenres returns the genres a user spent the most time watching between two days, both included
+func (r *MediaRepository) GetUserTopGenres(userID string, start, end time.Time, limit int) ([]GenreViewing, error) {
+	var genres []GenreViewing
+	result := r.db.
+		Table("(?) AS views", r.userViews(userID, start, end)).
+		Joins("LEFT JOIN category_movie ON category_movie.movie_id = views.movie_id").
+		Joins("LEFT JOIN category_tv_show ON category_tv_show.tv_show_id = views.tv_show_id").
+		Joins("JOIN categories ON categories.id = COALESCE(category_movie.category_id, category_tv_show.category_id)").
+		Select(`categories.name, SUM(views.seconds) AS seconds,
+			COUNT(DISTINCT views.movie_id) + COUNT(DISTINCT views.tv_show_id) AS titles`).
+		Group("categories.name").
+		Order("seconds DESC, categories.name").
+		Limit(limit).
+		Scan(&genres)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return genres, nil
+}
+
+// GetUserTopTvShows returns the tv shows a user spent the most time watching between two days, both included
+func (r *MediaRepository) GetUserTopTvShows(userID string, start, end time.Time, limit int) ([]TvShowViewing, error) {
+	var tvShows []TvShowViewing
+	result := r.db.
+		Table("episode_view_log").
+		Joins("JOIN tv_shows ON tv_shows.id = episode_view_log.tv_show_id").
+		Select(`episode_view_log.tv_show_id, tv_shows.name, SUM(episode_view_log.seconds) AS seconds,
+			COUNT(DISTINCT episode_view_log.episode_id) FILTER (WHERE episode_view_log.finished) AS episodes`).
+		Where("episode_view_log.user_id = ? AND episode_view_log.day BETWEEN ? AND ?", userID, start, end).
+		Group("episode_view_log.tv_show_id, tv_shows.name").
+		Order("seconds DESC, tv_shows.name").
+		Limit(limit).
+		Scan(&tvShows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tvShows, nil
+}
+
+// userViews returns the query of the movie and episode views of a user between two days, both included
+func (r *MediaRepository) userViews(userID string, start, end time.Time) *gorm.DB {
+	movies := r.db.
+		Table("movie_view_log").
+		Select("day, seconds, finished, movie_id, NULL::bigint AS episode_id, NULL::bigint AS tv_show_id").
+		Where("user_id = ? AND day BETWEEN ? AND ?", userID, start, end)
+	episodes := r.db.
+		Table("episode_view_log").
+		Select("day, seconds, finished, NULL::bigint AS movie_id, episode_id, tv_show_id").
+		Where("user_id = ? AND day BETWEEN ? AND ?", userID, start, end)
+	return r.db.Raw("? UNION ALL ?", movies, episodes)
+}
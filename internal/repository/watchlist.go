@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"github.com/bingemate/media-go-pkg/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WatchListFilter restricts the items of a watch list. Zero values do not filter.
+type WatchListFilter struct {
+	Statuses []repository.WatchListStatus
+	// Available keeps only the items having a file in the library
+	Available bool
+}
+
+// watchListImportBatch is the number of items inserted by a single statement of an import
+const watchListImportBatch = 500
+
+var watchListUpsert = clause.OnConflict{
+	UpdateAll: true,
+}
+
+// GetMovieWatchList returns the movies of the watch list of a user matching the filter, ordered by movie ID
+func (r *MediaRepository) GetMovieWatchList(userID string, filter WatchListFilter) ([]*repository.MovieWatchListItem, error) {
+	var items []*repository.MovieWatchListItem
+	query := r.db.Where("user_id = ?", userID)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Available {
+		query = query.Where("movie_id IN (SELECT movies.id FROM movies WHERE " + availableFile("movies.media_file_id") + ")")
+	}
+	result := query.Order("movie_id").Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// GetTvShowWatchList returns the tv shows of the watch list of a user matching the filter, ordered by tv show ID
+func (r *MediaRepository) GetTvShowWatchList(userID string, filter WatchListFilter) ([]*repository.TvShowWatchListItem, error) {
+	var items []*repository.TvShowWatchListItem
+	query := r.db.Where("user_id = ?", userID)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Available {
+		query = query.Where("tv_show_id IN (SELECT episodes.tv_show_id FROM episodes WHERE " + availableFile("episodes.media_file_id") + ")")
+	}
+	result := query.Order("tv_show_id").Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// GetMovieWatchListItem returns the watch list item of a user for a movie
+func (r *MediaRepository) GetMovieWatchListItem(userID string, movieID int) (*repository.MovieWatchListItem, error) {
+	var item repository.MovieWatchListItem
+	result := r.db.
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// GetTvShowWatchListItem returns the watch list item of a user for a tv show
+func (r *MediaRepository) GetTvShowWatchListItem(userID string, tvShowID int) (*repository.TvShowWatchListItem, error) {
+	var item repository.TvShowWatchListItem
+	result := r.db.
+		Where("user_id = ? AND tv_show_id = ?", userID, tvShowID).
+		First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// SaveMovieWatchListItem adds a movie to the watch list of a user, or updates its status
+func (r *MediaRepository) SaveMovieWatchListItem(item *repository.MovieWatchListItem) error {
+	return r.db.Clauses(watchListUpsert).Create(item).Error
+}
+
+// SaveTvShowWatchListItem adds a tv show to the watch list of a user, or updates its status
+func (r *MediaRepository) SaveTvShowWatchListItem(item *repository.TvShowWatchListItem) error {
+	return r.db.Clauses(watchListUpsert).Create(item).Error
+}
+
+// DeleteMovieWatchListItem removes a movie from the watch list of a user, gorm.ErrRecordNotFound if it is not in it
+func (r *MediaRepository) DeleteMovieWatchListItem(userID string, movieID int) error {
+	result := r.db.
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Delete(&repository.MovieWatchListItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteTvShowWatchListItem removes a tv show from the watch list of a user, gorm.ErrRecordNotFound if it is not in it
+func (r *MediaRepository) DeleteTvShowWatchListItem(userID string, tvShowID int) error {
+	result := r.db.
+		Where("user_id = ? AND tv_show_id = ?", userID, tvShowID).
+		Delete(&repository.TvShowWatchListItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ImportWatchList adds movies and tv shows to watch lists in a single transaction, updating the status of the items
+// already present
+func (r *MediaRepository) ImportWatchList(movies []*repository.MovieWatchListItem, tvShows []*repository.TvShowWatchListItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(movies) > 0 {
+			if err := tx.Clauses(watchListUpsert).CreateInBatches(movies, watchListImportBatch).Error; err != nil {
+				return err
+			}
+		}
+		if len(tvShows) > 0 {
+			if err := tx.Clauses(watchListUpsert).CreateInBatches(tvShows, watchListImportBatch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
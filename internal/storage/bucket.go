@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Bucket gives read access to the content of the media bucket, which objectstorage.ObjectStorage only writes to
+// by whole directories, deletes single objects for the storage reconciliation and writes the uploaded subtitles
+type Bucket struct {
+	client *s3.S3
+	name   string
+}
+
+func NewBucket(accessKey, secretKey, endpoint, region, name string) (*Bucket, error) {
+	bucketSession, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{
+		client: s3.New(bucketSession),
+		name:   name,
+	}, nil
+}
+
+// ListDirectories returns the names of the "directories" directly under prefix,
+// e.g. the media IDs for the prefix "movies/"
+func (b *Bucket) ListDirectories(prefix string) ([]string, error) {
+	var directories []string
+	err := b.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:    aws.String(b.name),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, commonPrefix := range page.CommonPrefixes {
+			name := strings.TrimSuffix(strings.TrimPrefix(aws.StringValue(commonPrefix.Prefix), prefix), "/")
+			directories = append(directories, name)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return directories, nil
+}
+
+// deleteBatchSize is the maximum number of keys of a DeleteObjects request
+const deleteBatchSize = 1000
+
+// Object is an object of the bucket listed under a prefix, its key being relative to it
+type Object struct {
+	Key          string
+	LastModified time.Time
+}
+
+// ListObjects returns every object under prefix
+func (b *Bucket) ListObjects(prefix string) ([]Object, error) {
+	var objects []Object
+	err := b.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(b.name),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, Object{
+				Key:          strings.TrimPrefix(aws.StringValue(object.Key), prefix),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// DeleteObjects deletes the objects having the given keys, failing if any of them could not be deleted
+func (b *Bucket) DeleteObjects(keys []string) error {
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		batch := keys[start:min(start+deleteBatchSize, len(keys))]
+		objects := make([]*s3.ObjectIdentifier, len(batch))
+		for i, key := range batch {
+			objects[i] = &s3.ObjectIdentifier{Key: aws.String(key)}
+		}
+		output, err := b.client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(b.name),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(output.Errors) > 0 {
+			failed := output.Errors[0]
+			return fmt.Errorf("could not delete %d objects, %s: %s",
+				len(output.Errors), aws.StringValue(failed.Key), aws.StringValue(failed.Message))
+		}
+	}
+	return nil
+}
+
+// GetObject returns the content of the object at key, ErrObjectNotFound if there is none
+func (b *Bucket) GetObject(key string) ([]byte, error) {
+	output, err := b.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.name),
+		Key:    aws.String(key),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return io.ReadAll(output.Body)
+}
+
+// PutObject creates or replaces the object at key
+func (b *Bucket) PutObject(key string, content []byte, contentType string) error {
+	_, err := b.client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(b.name),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// PresignGet returns a URL reading the object at key without credentials until it expires
+func (b *Bucket) PresignGet(key string, expiry time.Duration) (string, error) {
+	request, _ := b.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(b.name),
+		Key:    aws.String(key),
+	})
+	return request.Presign(expiry)
+}
@@ -0,0 +1,252 @@
+package tracing
+
+import (
+	"context"
+	"github.com/bingemate/media-go-pkg/tmdb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type mediaClient struct {
+	ctx    context.Context
+	client tmdb.MediaClient
+}
+
+// NewMediaClient wraps a media client so that every call creates a span
+// as a child of the span carried by ctx
+func NewMediaClient(ctx context.Context, client tmdb.MediaClient) tmdb.MediaClient {
+	return &mediaClient{ctx: ctx, client: client}
+}
+
+func (m *mediaClient) start(method string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := Tracer().Start(m.ctx, "tmdb."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return span
+}
+
+func (m *mediaClient) GetActor(actorID int) (*tmdb.Actor, error) {
+	span := m.start("GetActor", attribute.Int("tmdb.actor_id", actorID))
+	result, err := m.client.GetActor(actorID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMovie(id int) (*tmdb.Movie, error) {
+	span := m.start("GetMovie", attribute.Int("tmdb.id", id))
+	result, err := m.client.GetMovie(id)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMovieGenre(genreID int) (*tmdb.Genre, error) {
+	span := m.start("GetMovieGenre", attribute.Int("tmdb.genre_id", genreID))
+	result, err := m.client.GetMovieGenre(genreID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMovieGenres() ([]*tmdb.Genre, error) {
+	span := m.start("GetMovieGenres")
+	result, err := m.client.GetMovieGenres()
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMovieRecommendations(movieID int) ([]*tmdb.Movie, error) {
+	span := m.start("GetMovieRecommendations", attribute.Int("tmdb.movie_id", movieID))
+	result, err := m.client.GetMovieRecommendations(movieID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMovieShort(movieID int) (*tmdb.Movie, error) {
+	span := m.start("GetMovieShort", attribute.Int("tmdb.movie_id", movieID))
+	result, err := m.client.GetMovieShort(movieID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMoviesByActor(actorID int, page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("GetMoviesByActor", attribute.Int("tmdb.actor_id", actorID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetMoviesByActor(actorID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMoviesByDirector(directorID int, page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("GetMoviesByDirector", attribute.Int("tmdb.director_id", directorID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetMoviesByDirector(directorID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMoviesByGenre(genreID int, page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("GetMoviesByGenre", attribute.Int("tmdb.genre_id", genreID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetMoviesByGenre(genreID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMoviesByStudio(studioID int, page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("GetMoviesByStudio", attribute.Int("tmdb.studio_id", studioID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetMoviesByStudio(studioID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetMoviesReleases(movieIds []int, startDate time.Time, endDate time.Time) ([]*tmdb.Movie, error) {
+	span := m.start("GetMoviesReleases", attribute.IntSlice("tmdb.movie_ids", movieIds), attribute.String("tmdb.start_date", startDate.Format(time.DateOnly)), attribute.String("tmdb.end_date", endDate.Format(time.DateOnly)))
+	result, err := m.client.GetMoviesReleases(movieIds, startDate, endDate)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetNetwork(networkID int) (*tmdb.Studio, error) {
+	span := m.start("GetNetwork", attribute.Int("tmdb.network_id", networkID))
+	result, err := m.client.GetNetwork(networkID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetPopularMovies(page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("GetPopularMovies", attribute.Int("tmdb.page", page))
+	result, err := m.client.GetPopularMovies(page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetPopularTVShows(page int) (*tmdb.PaginatedTVShowResults, error) {
+	span := m.start("GetPopularTVShows", attribute.Int("tmdb.page", page))
+	result, err := m.client.GetPopularTVShows(page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetRecentMovies() ([]*tmdb.Movie, error) {
+	span := m.start("GetRecentMovies")
+	result, err := m.client.GetRecentMovies()
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetRecentTVShows() ([]*tmdb.TVShow, error) {
+	span := m.start("GetRecentTVShows")
+	result, err := m.client.GetRecentTVShows()
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetStudio(studioID int) (*tmdb.Studio, error) {
+	span := m.start("GetStudio", attribute.Int("tmdb.studio_id", studioID))
+	result, err := m.client.GetStudio(studioID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVEpisode(tvID int, season int, episodeNumber int) (*tmdb.TVEpisode, error) {
+	span := m.start("GetTVEpisode", attribute.Int("tmdb.tv_id", tvID), attribute.Int("tmdb.season", season), attribute.Int("tmdb.episode_number", episodeNumber))
+	result, err := m.client.GetTVEpisode(tvID, season, episodeNumber)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVGenre(genreID int) (*tmdb.Genre, error) {
+	span := m.start("GetTVGenre", attribute.Int("tmdb.genre_id", genreID))
+	result, err := m.client.GetTVGenre(genreID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVSeasonEpisodes(id int, season int) ([]*tmdb.TVEpisode, error) {
+	span := m.start("GetTVSeasonEpisodes", attribute.Int("tmdb.id", id), attribute.Int("tmdb.season", season))
+	result, err := m.client.GetTVSeasonEpisodes(id, season)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShow(id int) (*tmdb.TVShow, error) {
+	span := m.start("GetTVShow", attribute.Int("tmdb.id", id))
+	result, err := m.client.GetTVShow(id)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowGenres() ([]*tmdb.Genre, error) {
+	span := m.start("GetTVShowGenres")
+	result, err := m.client.GetTVShowGenres()
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowRecommendations(tvShowID int) ([]*tmdb.TVShow, error) {
+	span := m.start("GetTVShowRecommendations", attribute.Int("tmdb.tv_show_id", tvShowID))
+	result, err := m.client.GetTVShowRecommendations(tvShowID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowShort(tvShowID int) (*tmdb.TVShow, error) {
+	span := m.start("GetTVShowShort", attribute.Int("tmdb.tv_show_id", tvShowID))
+	result, err := m.client.GetTVShowShort(tvShowID)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowsByActor(actorID int, page int) (*tmdb.PaginatedTVShowResults, error) {
+	span := m.start("GetTVShowsByActor", attribute.Int("tmdb.actor_id", actorID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetTVShowsByActor(actorID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowsByGenre(genreID int, page int) (*tmdb.PaginatedTVShowResults, error) {
+	span := m.start("GetTVShowsByGenre", attribute.Int("tmdb.genre_id", genreID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetTVShowsByGenre(genreID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowsByNetwork(studioID int, page int) (*tmdb.PaginatedTVShowResults, error) {
+	span := m.start("GetTVShowsByNetwork", attribute.Int("tmdb.studio_id", studioID), attribute.Int("tmdb.page", page))
+	result, err := m.client.GetTVShowsByNetwork(studioID, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) GetTVShowsReleases(tvIds []int, startDate time.Time, endDate time.Time) ([]*tmdb.TVEpisode, []*tmdb.TVShow, error) {
+	span := m.start("GetTVShowsReleases", attribute.IntSlice("tmdb.tv_ids", tvIds), attribute.String("tmdb.start_date", startDate.Format(time.DateOnly)), attribute.String("tmdb.end_date", endDate.Format(time.DateOnly)))
+	episodes, tvShows, err := m.client.GetTVShowsReleases(tvIds, startDate, endDate)
+	end(span, err)
+	return episodes, tvShows, err
+}
+
+func (m *mediaClient) SearchActors(query string, page int, adult bool) (*tmdb.PaginatedActorResults, error) {
+	span := m.start("SearchActors", attribute.String("tmdb.query", query), attribute.Int("tmdb.page", page), attribute.Bool("tmdb.adult", adult))
+	result, err := m.client.SearchActors(query, page, adult)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) SearchMovies(query string, page int, adult bool) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("SearchMovies", attribute.String("tmdb.query", query), attribute.Int("tmdb.page", page), attribute.Bool("tmdb.adult", adult))
+	result, err := m.client.SearchMovies(query, page, adult)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) SearchMoviesYear(query string, year string, page int) (*tmdb.PaginatedMovieResults, error) {
+	span := m.start("SearchMoviesYear", attribute.String("tmdb.query", query), attribute.String("tmdb.year", year), attribute.Int("tmdb.page", page))
+	result, err := m.client.SearchMoviesYear(query, year, page)
+	end(span, err)
+	return result, err
+}
+
+func (m *mediaClient) SearchTVShows(query string, page int, adult bool) (*tmdb.PaginatedTVShowResults, error) {
+	span := m.start("SearchTVShows", attribute.String("tmdb.query", query), attribute.Int("tmdb.page", page), attribute.Bool("tmdb.adult", adult))
+	result, err := m.client.SearchTVShows(query, page, adult)
+	end(span, err)
+	return result, err
+}
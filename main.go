@@ -3,8 +3,7 @@ package main
 import (
 	"flag"
 	"github.com/bingemate/media-service/cmd"
-	"github.com/bingemate/media-service/initializers"
-	"log"
+	"os"
 )
 
 // @title Media Service API
@@ -12,13 +11,8 @@ import (
 // @description This help to give info about the media files and metadata
 // @description This also help to manage the media files for admins
 func main() {
+	configFile := flag.String("config", "", "path of the YAML or TOML configuration file, layered under the environment variables (default $CONFIG_FILE)")
+	flag.Usage = cmd.Usage
 	flag.Parse()
-	env, err := initializers.LoadEnv()
-	if err != nil {
-		log.Fatal(err)
-	}
-	logFile := initializers.InitLog(env.LogFile)
-	defer logFile.Close()
-	log.Println("Starting server mode...")
-	cmd.Serve(env)
+	os.Exit(cmd.Execute(*configFile, flag.Args()))
 }